@@ -0,0 +1,234 @@
+package aof
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// fsync policies
+const (
+	FSYNC_ALWAYS   string = "always"
+	FSYNC_EVERYSEC string = "everysec"
+	FSYNC_NO       string = "no"
+)
+
+// AOF is an append only log of the write commands executed by the server,
+// stored in the same RESP format clients use to send them
+type AOF struct {
+	file   *os.File
+	policy string
+	lock   *sync.Mutex
+	dirty  bool
+	done   chan struct{}
+}
+
+func NewAOF(filename string, policy string) (*AOF, error) {
+	if policy != FSYNC_ALWAYS && policy != FSYNC_EVERYSEC && policy != FSYNC_NO {
+		return nil, fmt.Errorf("Invalid appendfsync policy %s", policy)
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aof := &AOF{
+		file:   file,
+		policy: policy,
+		lock:   &sync.Mutex{},
+		done:   make(chan struct{}),
+	}
+
+	if policy == FSYNC_EVERYSEC {
+		go aof.syncEverySecond()
+	}
+
+	return aof, nil
+}
+
+// commands around the effects of a transaction, so that they are replayed
+// all at once or not at all
+const (
+	MULTI string = "MULTI"
+	EXEC  string = "EXEC"
+)
+
+// entry is a command read from the log
+type entry struct {
+	command string
+	args    []any
+}
+
+// Load replays every command of the log through replay. A truncated last entry,
+// as left behind by a crash in the middle of a write, is dropped from the file,
+// along with a transaction whose EXEC was never written.
+func (a *AOF) Load(replay func(command string, args []any) error) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	content, err := os.ReadFile(a.file.Name())
+
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	// commands of the transaction being read, nil outside of one
+	var transaction []entry
+	transactionOffset := 0
+
+	replayEntry := func(current entry) {
+		if err := replay(current.command, current.args); err != nil {
+			fmt.Printf("Error while replaying %s from append only file : %v\n", current.command, err)
+		}
+	}
+
+	for offset < len(content) {
+		request, requestType, err, readLength := resp.Deserialize(content[offset:])
+
+		if err == resp.ErrIncompleteData {
+			fmt.Printf("Truncating incomplete append only file entry at offset %d\n", offset)
+
+			if err := a.file.Truncate(int64(offset)); err != nil {
+				return err
+			}
+
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Bad append only file format at offset %d : %w", offset, err)
+		}
+
+		command, args, err := parseCommand(request, requestType)
+
+		if err != nil {
+			return fmt.Errorf("Bad append only file format at offset %d : %w", offset, err)
+		}
+
+		switch {
+		case strings.EqualFold(command, MULTI) && transaction == nil:
+			transaction, transactionOffset = []entry{}, offset
+		case strings.EqualFold(command, EXEC) && transaction != nil:
+			for _, current := range transaction {
+				replayEntry(current)
+			}
+
+			transaction = nil
+		case transaction != nil:
+			transaction = append(transaction, entry{command: command, args: args})
+		default:
+			replayEntry(entry{command: command, args: args})
+		}
+
+		offset += readLength
+	}
+
+	if transaction != nil {
+		fmt.Printf("Discarding incomplete transaction from append only file at offset %d\n", transactionOffset)
+		return a.file.Truncate(int64(transactionOffset))
+	}
+
+	return nil
+}
+
+// Append logs a single command, syncing it to disk according to the policy
+func (a *AOF) Append(command string, args ...any) error {
+	items := []resp.ArrayType{{Value: command, Type: resp.BULK_STRING}}
+
+	for _, arg := range args {
+		items = append(items, resp.ArrayType{Value: arg, Type: resp.BULK_STRING})
+	}
+
+	data, err := resp.Serialize(resp.ARRAY, items)
+
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if _, err := a.file.Write(data); err != nil {
+		return err
+	}
+
+	if a.policy == FSYNC_ALWAYS {
+		return a.file.Sync()
+	}
+
+	a.dirty = true
+	return nil
+}
+
+func (a *AOF) Close() error {
+	close(a.done)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+
+	return a.file.Close()
+}
+
+func (a *AOF) syncEverySecond() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			a.sync()
+		}
+	}
+}
+
+func (a *AOF) sync() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.dirty {
+		return
+	}
+
+	if err := a.file.Sync(); err != nil {
+		fmt.Println("Error while syncing append only file : ", err)
+		return
+	}
+
+	a.dirty = false
+}
+
+func parseCommand(request any, requestType string) (string, []any, error) {
+	items, isArray := request.([]resp.ArrayType)
+
+	if requestType != resp.ARRAY || !isArray || len(items) < 1 {
+		return "", nil, errors.New("expected a command array")
+	}
+
+	command, isString := items[0].Value.(string)
+
+	if !isString {
+		return "", nil, errors.New("expected a command name")
+	}
+
+	args := []any{}
+
+	for _, item := range items[1:] {
+		args = append(args, item.Value)
+	}
+
+	return command, args, nil
+}
@@ -0,0 +1,105 @@
+package aof
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "appendonly.aof")
+
+	appendOnlyFile, err := NewAOF(filename, FSYNC_ALWAYS)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	appendOnlyFile.Append("SET", "key", "value")
+	appendOnlyFile.Append("RPUSH", "list", "a", "b")
+	appendOnlyFile.Close()
+
+	replayed := [][]any{}
+
+	appendOnlyFile, err = NewAOF(filename, FSYNC_NO)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer appendOnlyFile.Close()
+
+	err = appendOnlyFile.Load(func(command string, args []any) error {
+		replayed = append(replayed, append([]any{command}, args...))
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, replayed, [][]any{
+		{"SET", "key", "value"},
+		{"RPUSH", "list", "a", "b"},
+	})
+}
+
+func TestLoadTruncatedEntry(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "appendonly.aof")
+	complete := "*2\r\n$3\r\nDEL\r\n$3\r\nkey\r\n"
+
+	os.WriteFile(filename, []byte(complete+"*3\r\n$3\r\nSET\r\n$1\r\nk"), 0644)
+
+	appendOnlyFile, err := NewAOF(filename, FSYNC_ALWAYS)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	replayed := 0
+
+	err = appendOnlyFile.Load(func(command string, args []any) error {
+		replayed++
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, replayed, 1)
+
+	appendOnlyFile.Append("PING")
+	appendOnlyFile.Close()
+
+	content, _ := os.ReadFile(filename)
+	assert.Equal(t, string(content), complete+"*1\r\n$4\r\nPING\r\n")
+}
+
+func TestLoadTransactions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "appendonly.aof")
+	complete := "*1\r\n$5\r\nMULTI\r\n*2\r\n$3\r\nDEL\r\n$1\r\na\r\n*2\r\n$3\r\nDEL\r\n$1\r\nb\r\n*1\r\n$4\r\nEXEC\r\n"
+	// a crash after the first effect of a transaction leaves it without its EXEC
+	incomplete := "*1\r\n$5\r\nMULTI\r\n*2\r\n$3\r\nDEL\r\n$1\r\nc\r\n"
+
+	os.WriteFile(filename, []byte(complete+incomplete), 0644)
+
+	appendOnlyFile, err := NewAOF(filename, FSYNC_ALWAYS)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	replayed := [][]any{}
+
+	err = appendOnlyFile.Load(func(command string, args []any) error {
+		replayed = append(replayed, append([]any{command}, args...))
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]any{{"DEL", "a"}, {"DEL", "b"}}, replayed)
+
+	appendOnlyFile.Append("PING")
+	appendOnlyFile.Close()
+
+	content, _ := os.ReadFile(filename)
+	assert.Equal(t, complete+"*1\r\n$4\r\nPING\r\n", string(content))
+}
@@ -1,25 +1,50 @@
 package main
 
 import (
+	"flag"
+	"log"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/aof"
 	"github.com/iamvineettiwari/go-redis-server-lite/handler"
+	"github.com/iamvineettiwari/go-redis-server-lite/rdb"
 	"github.com/iamvineettiwari/go-redis-server-lite/server"
 )
 
 func main() {
+	listenAddr := flag.String("addr", ":6379", "address to listen on")
+	appendOnly := flag.Bool("appendonly", false, "log every write command to the append only file")
+	appendFilename := flag.String("appendfilename", "appendonly.aof", "name of the append only file")
+	appendFsync := flag.String("appendfsync", aof.FSYNC_EVERYSEC, "append only file fsync policy (always | everysec | no)")
+	dbFilename := flag.String("dbfilename", "dump.rdb", "name of the snapshot file")
+	databases := flag.Int("databases", 16, "number of databases")
+	saveRules := flag.String("save", "3600 1 300 100 60 10000", "save a snapshot after <seconds> <changes>, empty to disable")
+	flag.Parse()
+
+	rules, err := rdb.ParseSaveRules(*saveRules)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	handlerInstance := handler.NewHandler()
-	redisServer := server.NewRedisServer(":6379", handlerInstance)
-
-	handlerInstance.AddHandler(handler.PING, handlerInstance.Ping)
-	handlerInstance.AddHandler(handler.ECHO, handlerInstance.Echo)
-	handlerInstance.AddHandler(handler.SET, handlerInstance.Set)
-	handlerInstance.AddHandler(handler.GET, handlerInstance.Get)
-	handlerInstance.AddHandler(handler.EXISTS, handlerInstance.Exists)
-	handlerInstance.AddHandler(handler.DEL, handlerInstance.Delete)
-	handlerInstance.AddHandler(handler.INCR, handlerInstance.Incr)
-	handlerInstance.AddHandler(handler.DECR, handlerInstance.Decr)
-	handlerInstance.AddHandler(handler.LRANGE, handlerInstance.LRange)
-	handlerInstance.AddHandler(handler.LPUSH, handlerInstance.Lpush)
-	handlerInstance.AddHandler(handler.RPUSH, handlerInstance.Rpush)
-
-	redisServer.Start()
+	if *databases < 1 {
+		log.Fatal("The number of databases must be at least 1")
+	}
+
+	redisServer := server.NewRedisServer(*listenAddr, handlerInstance, *databases)
+
+	// the append only file is more up to date than the snapshot when enabled
+	if err := redisServer.EnableSnapshots(*dbFilename, rules, !*appendOnly); err != nil {
+		log.Fatal(err)
+	}
+
+	if *appendOnly {
+		if err := redisServer.EnableAppendOnly(*appendFilename, *appendFsync); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := redisServer.Start(); err != nil {
+		log.Fatal(err)
+	}
 }
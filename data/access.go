@@ -0,0 +1,76 @@
+package data
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Access frequencies are kept the way redis does in its LFU mode: a
+// logarithmic counter which grows slower the higher it is, and which
+// decreases by one for every minute the key is not accessed.
+const (
+	lfuInitValue   = 5
+	lfuMaxValue    = 255
+	lfuLogFactor   = 10
+	lfuDecayPeriod = time.Minute
+)
+
+type keyAccess struct {
+	// unix time in milliseconds of the last access
+	lastAccess int64
+	counter    int
+}
+
+// recordAccess updates the access statistics of the key, it is called by every
+// read and write of the key
+func (s *Store) recordAccess(key string) {
+	s.al.Lock()
+	defer s.al.Unlock()
+
+	now := time.Now().UnixMilli()
+	access, found := s.access[key]
+
+	if !found {
+		s.access[key] = &keyAccess{lastAccess: now, counter: lfuInitValue}
+		return
+	}
+
+	access.counter = access.decayedCounter(now)
+
+	if access.counter < lfuMaxValue {
+		base := max(access.counter-lfuInitValue, 0)
+
+		if rand.Float64() < 1/float64(base*lfuLogFactor+1) {
+			access.counter++
+		}
+	}
+
+	access.lastAccess = now
+}
+
+func (s *Store) forgetAccess(key string) {
+	s.al.Lock()
+	defer s.al.Unlock()
+
+	delete(s.access, key)
+}
+
+// lookupAccess returns a copy of the access statistics of the key, without
+// recording an access
+func (s *Store) lookupAccess(key string) (keyAccess, bool) {
+	s.al.Lock()
+	defer s.al.Unlock()
+
+	access, found := s.access[key]
+
+	if !found {
+		return keyAccess{}, false
+	}
+
+	return *access, true
+}
+
+func (a keyAccess) decayedCounter(now int64) int {
+	periods := int((now - a.lastAccess) / lfuDecayPeriod.Milliseconds())
+	return max(a.counter-periods, 0)
+}
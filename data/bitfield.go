@@ -0,0 +1,171 @@
+package data
+
+// BITFIELD subcommands
+const (
+	BITFIELD_GET    string = "GET"
+	BITFIELD_SET    string = "SET"
+	BITFIELD_INCRBY string = "INCRBY"
+)
+
+// BITFIELD overflow behaviours
+const (
+	OVERFLOW_WRAP string = "WRAP"
+	OVERFLOW_SAT  string = "SAT"
+	OVERFLOW_FAIL string = "FAIL"
+)
+
+// BitfieldOp is a BITFIELD subcommand on the integer of Bits bits stored from
+// the bit Offset of a string
+type BitfieldOp struct {
+	// BITFIELD_GET, BITFIELD_SET or BITFIELD_INCRBY
+	Command string
+	Signed  bool
+	Bits    int
+	Offset  int
+	// the value to set or the increment
+	Value int64
+	// how SET and INCRBY handle results which do not fit the integer
+	Overflow string
+}
+
+// Bitfield runs the operations on the integers stored in the string of the
+// key. Returns their results: the integer for GET, the previous one for SET
+// and the new one for INCRBY, nil when FAIL prevented a write. The string is
+// only created or grown when there is a SET or an INCRBY.
+func (s *Store) Bitfield(key string, operations []BitfieldOp) ([]interface{}, error) {
+	size := 0
+
+	for _, operation := range operations {
+		if operation.Command != BITFIELD_GET {
+			size = max(size, (operation.Offset+operation.Bits+7)/8)
+		}
+	}
+
+	results := make([]interface{}, 0, len(operations))
+
+	if size == 0 {
+		buffer, _, err := s.getBytes(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, operation := range operations {
+			results = append(results, operation.get(buffer))
+		}
+
+		return results, nil
+	}
+
+	err := s.updateBytes(key, size, func(buffer []byte) ([]byte, error) {
+		for _, operation := range operations {
+			results = append(results, operation.apply(buffer))
+		}
+
+		return buffer, nil
+	})
+
+	return results, err
+}
+
+// apply runs the operation on buffer, which is large enough for writes
+func (op BitfieldOp) apply(buffer []byte) interface{} {
+	current := op.get(buffer)
+
+	switch op.Command {
+	case BITFIELD_SET:
+		value, fits := op.fit(0, op.Value)
+
+		if !fits {
+			return nil
+		}
+
+		op.set(buffer, value)
+		return current
+
+	case BITFIELD_INCRBY:
+		value, fits := op.fit(current, op.Value)
+
+		if !fits {
+			return nil
+		}
+
+		op.set(buffer, value)
+		return value
+	}
+
+	return current
+}
+
+func (op BitfieldOp) get(buffer []byte) int64 {
+	var value uint64
+
+	for i := 0; i < op.Bits; i++ {
+		value = value<<1 | uint64(getBit(buffer, op.Offset+i))
+	}
+
+	return op.extend(value)
+}
+
+func (op BitfieldOp) set(buffer []byte, value int64) {
+	for i := 0; i < op.Bits; i++ {
+		setBit(buffer, op.Offset+i, int(uint64(value)>>(op.Bits-1-i))&1)
+	}
+}
+
+// fit returns value plus increment as an integer of the type of the
+// operation, handling overflows according to its behaviour. Returns false
+// when the result does not fit and the behaviour is FAIL.
+func (op BitfieldOp) fit(value int64, increment int64) (int64, bool) {
+	var minimum, maximum int64
+	var overflow, underflow bool
+
+	if op.Signed {
+		maximum = int64(uint64(1)<<(op.Bits-1) - 1)
+		minimum = -maximum - 1
+
+		// the differences can not overflow for 64 bits integers of the same sign
+		overflow = increment > 0 && (op.Bits < 64 || value >= 0) && increment > maximum-value
+		underflow = increment < 0 && (op.Bits < 64 || value < 0) && increment < minimum-value
+	} else {
+		// unsigned integers have at most 63 bits
+		maximum = int64(uint64(1)<<op.Bits - 1)
+
+		overflow = increment > 0 && increment > maximum-value
+		underflow = increment < 0 && increment < -value
+	}
+
+	if !overflow && !underflow {
+		return value + increment, true
+	}
+
+	switch op.Overflow {
+	case OVERFLOW_SAT:
+		if overflow {
+			return maximum, true
+		}
+
+		return minimum, true
+
+	case OVERFLOW_FAIL:
+		return 0, false
+	}
+
+	// wrap around, keeping the low bits of the sum
+	sum := uint64(value) + uint64(increment)
+
+	if op.Bits < 64 {
+		sum &= 1<<op.Bits - 1
+	}
+
+	return op.extend(sum), true
+}
+
+// extend converts the low bits of value to an integer of the type of the operation
+func (op BitfieldOp) extend(value uint64) int64 {
+	if op.Signed && op.Bits < 64 && value&(1<<(op.Bits-1)) != 0 {
+		value |= ^uint64(0) << op.Bits
+	}
+
+	return int64(value)
+}
@@ -0,0 +1,232 @@
+package data
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// units of the offsets of BITCOUNT and BITPOS ranges
+const (
+	BIT_RANGE_BYTE string = "BYTE"
+	BIT_RANGE_BIT  string = "BIT"
+)
+
+// BITOP operations
+const (
+	BITOP_AND string = "AND"
+	BITOP_OR  string = "OR"
+	BITOP_XOR string = "XOR"
+	BITOP_NOT string = "NOT"
+)
+
+// bitmaps are strings addressed by bit, so they are limited like strings
+const MAX_BIT_OFFSET = MAX_STRING_LENGTH*8 - 1
+
+var ErrBitOffset = errors.New("ERR bit offset is not an integer or out of range")
+
+// SetBit sets the bit at offset to value, growing the string with zero bytes
+// when it is too short. Returns the previous bit.
+func (s *Store) SetBit(key string, offset int, value int) (int, error) {
+	if offset < 0 || offset > MAX_BIT_OFFSET {
+		return 0, ErrBitOffset
+	}
+
+	previous := 0
+
+	err := s.updateBytes(key, offset/8+1, func(buffer []byte) ([]byte, error) {
+		previous = getBit(buffer, offset)
+		setBit(buffer, offset, value)
+
+		return buffer, nil
+	})
+
+	return previous, err
+}
+
+// GetBit returns the bit at offset, bits past the end of the string are 0
+func (s *Store) GetBit(key string, offset int) (int, error) {
+	if offset < 0 || offset > MAX_BIT_OFFSET {
+		return 0, ErrBitOffset
+	}
+
+	buffer, _, err := s.getBytes(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return getBit(buffer, offset), nil
+}
+
+// BitCount counts the bits set between the offsets start and end, both
+// inclusive, counted in unit. Negative offsets count from the end of the string.
+func (s *Store) BitCount(key string, start int, end int, unit string) (int, error) {
+	buffer, _, err := s.getBytes(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	first, last, found := bitRange(start, end, unit, len(buffer))
+
+	if !found {
+		return 0, nil
+	}
+
+	count := 0
+
+	for _, value := range buffer[first/8 : last/8+1] {
+		count += bits.OnesCount8(value)
+	}
+
+	// the bits of the first and last bytes which are out of the range
+	count -= bits.OnesCount8(buffer[first/8] &^ (0xff >> (first % 8)))
+	count -= bits.OnesCount8(buffer[last/8] & (0xff >> (last%8 + 1)))
+
+	return count, nil
+}
+
+// BitPos returns the offset of the first bit equal to bit between the offsets
+// start and end, counted like BitCount, -1 when there is none. A string is
+// considered padded with clear bits, so looking for a clear bit without an
+// end finds the first bit past the string.
+func (s *Store) BitPos(key string, bit int, start int, end int, endGiven bool, unit string) (int, error) {
+	buffer, found, err := s.getBytes(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// a missing key only holds clear bits, even with a range
+	if !found {
+		if bit == 1 {
+			return -1, nil
+		}
+
+		return 0, nil
+	}
+
+	first, last, found := bitRange(start, end, unit, len(buffer))
+
+	if !found {
+		return -1, nil
+	}
+
+	// bytes which can not hold the bit are skipped as a whole
+	skip := byte(0x00)
+
+	if bit == 0 {
+		skip = 0xff
+	}
+
+	for offset := first; offset <= last; {
+		if offset%8 == 0 && offset+7 <= last && buffer[offset/8] == skip {
+			offset += 8
+			continue
+		}
+
+		if getBit(buffer, offset) == bit {
+			return offset, nil
+		}
+
+		offset++
+	}
+
+	if bit == 0 && !endGiven {
+		return last + 1, nil
+	}
+
+	return -1, nil
+}
+
+// BitOp stores the result of operation on the strings of the keys at
+// destination, removing its timeout. Shorter strings and missing keys are
+// padded with zero bytes, NOT takes a single key. Returns the length of the
+// result, the destination is removed when the result is empty.
+func (s *Store) BitOp(operation string, destination string, keys ...string) (int, error) {
+	sources := make([][]byte, 0, len(keys))
+	length := 0
+
+	for _, key := range keys {
+		buffer, _, err := s.getBytes(key)
+
+		if err != nil {
+			return 0, err
+		}
+
+		sources = append(sources, buffer)
+		length = max(length, len(buffer))
+	}
+
+	if length == 0 {
+		s.Delete(destination)
+		return 0, nil
+	}
+
+	result := make([]byte, length)
+
+	for i := range result {
+		value := byteAt(sources[0], i)
+
+		if operation == BITOP_NOT {
+			value = ^value
+		}
+
+		for _, source := range sources[1:] {
+			switch operation {
+			case BITOP_AND:
+				value &= byteAt(source, i)
+			case BITOP_OR:
+				value |= byteAt(source, i)
+			case BITOP_XOR:
+				value ^= byteAt(source, i)
+			}
+		}
+
+		result[i] = value
+	}
+
+	s.setWithExpiry(destination, result, 0)
+
+	return length, nil
+}
+
+// bitRange converts a range of BITCOUNT or BITPOS to inclusive bit offsets
+// within a string of length bytes, false when it is empty
+func bitRange(start int, end int, unit string, length int) (int, int, bool) {
+	if unit == BIT_RANGE_BIT {
+		return normalizeRange(start, end, length*8)
+	}
+
+	start, end, found := normalizeRange(start, end, length)
+	return start * 8, end*8 + 7, found
+}
+
+// getBit returns the bit at offset, bits are numbered from the most
+// significant bit of the first byte
+func getBit(buffer []byte, offset int) int {
+	if offset/8 >= len(buffer) {
+		return 0
+	}
+
+	return int(buffer[offset/8]>>(7-offset%8)) & 1
+}
+
+// setBit sets the bit at offset, which must be within buffer
+func setBit(buffer []byte, offset int, value int) {
+	mask := byte(1) << (7 - offset%8)
+
+	if value == 0 {
+		buffer[offset/8] &^= mask
+	} else {
+		buffer[offset/8] |= mask
+	}
+}
+
+func byteAt(buffer []byte, index int) byte {
+	if index >= len(buffer) {
+		return 0
+	}
+
+	return buffer[index]
+}
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBit(t *testing.T) {
+	store := NewStore()
+	store.Set("bits", "a", "EX", 100)
+
+	// 'a' is 0b01100001
+	previous, _ := store.SetBit("bits", 6, 1)
+	assert.Equal(t, 0, previous)
+
+	previous, _ = store.SetBit("bits", 7, 0)
+	assert.Equal(t, 1, previous)
+
+	store.SetBit("bits", 23, 1)
+	value, _, _ := store.Get("bits")
+	assert.Equal(t, "b\x00\x01", value)
+	assert.Greater(t, store.TTL("bits"), int64(0))
+
+	bit, _ := store.GetBit("bits", 23)
+	assert.Equal(t, 1, bit)
+
+	bit, _ = store.GetBit("bits", 1000)
+	assert.Equal(t, 0, bit)
+
+	_, err := store.SetBit("bits", MAX_BIT_OFFSET+1, 1)
+	assert.Equal(t, ErrBitOffset, err)
+
+	// in place modifications do not leak into copies
+	store.Copy("bits", store, "copy", false)
+	store.SetBit("bits", 0, 1)
+	value, _, _ = store.Get("copy")
+	assert.Equal(t, "b\x00\x01", value)
+
+	store.Rpush("list", "x")
+	_, err = store.SetBit("list", 0, 1)
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestBitCountAndPos(t *testing.T) {
+	store := NewStore()
+	store.Set("bits", "foobar", "", 0)
+
+	count, _ := store.BitCount("bits", 0, -1, BIT_RANGE_BYTE)
+	assert.Equal(t, 26, count)
+
+	count, _ = store.BitCount("bits", 1, 1, BIT_RANGE_BYTE)
+	assert.Equal(t, 6, count)
+
+	count, _ = store.BitCount("bits", 5, 30, BIT_RANGE_BIT)
+	assert.Equal(t, 17, count)
+
+	store.Set("bits", "\xff\xf0\x00", "", 0)
+
+	position, _ := store.BitPos("bits", 0, 0, -1, false, BIT_RANGE_BYTE)
+	assert.Equal(t, 12, position)
+
+	position, _ = store.BitPos("bits", 1, 2, -1, false, BIT_RANGE_BYTE)
+	assert.Equal(t, -1, position)
+
+	store.Set("bits", "\x00\xff\xf0", "", 0)
+
+	position, _ = store.BitPos("bits", 1, 2, -1, true, BIT_RANGE_BYTE)
+	assert.Equal(t, 16, position)
+
+	position, _ = store.BitPos("bits", 1, 7, 15, true, BIT_RANGE_BIT)
+	assert.Equal(t, 8, position)
+
+	store.Set("bits", "\xff\xff", "", 0)
+
+	// without an end the string is padded with clear bits
+	position, _ = store.BitPos("bits", 0, 0, -1, false, BIT_RANGE_BYTE)
+	assert.Equal(t, 16, position)
+
+	position, _ = store.BitPos("bits", 0, 0, -1, true, BIT_RANGE_BYTE)
+	assert.Equal(t, -1, position)
+
+	position, _ = store.BitPos("missing", 0, 0, -1, false, BIT_RANGE_BYTE)
+	assert.Equal(t, 0, position)
+}
+
+func TestBitOp(t *testing.T) {
+	store := NewStore()
+	store.Set("a", "\x0f\xff", "", 0)
+	store.Set("b", "\xf0", "", 0)
+	store.Set("destination", "value", "EX", 100)
+
+	length, _ := store.BitOp(BITOP_OR, "destination", "a", "b", "missing")
+	assert.Equal(t, 2, length)
+
+	value, _, _ := store.Get("destination")
+	assert.Equal(t, "\xff\xff", value)
+	assert.Equal(t, int64(-1), store.TTL("destination"))
+
+	store.BitOp(BITOP_AND, "destination", "a", "b")
+	value, _, _ = store.Get("destination")
+	assert.Equal(t, "\x00\x00", value)
+
+	store.BitOp(BITOP_XOR, "destination", "a", "b")
+	value, _, _ = store.Get("destination")
+	assert.Equal(t, "\xff\xff", value)
+
+	store.BitOp(BITOP_NOT, "destination", "b")
+	value, _, _ = store.Get("destination")
+	assert.Equal(t, "\x0f", value)
+
+	length, _ = store.BitOp(BITOP_AND, "destination", "missing")
+	assert.Equal(t, 0, length)
+	assert.False(t, store.Exists("destination"))
+}
+
+func TestBitfield(t *testing.T) {
+	store := NewStore()
+
+	results, _ := store.Bitfield("missing", []BitfieldOp{{Command: BITFIELD_GET, Bits: 8}})
+	assert.Equal(t, []interface{}{int64(0)}, results)
+	assert.False(t, store.Exists("missing"))
+
+	results, _ = store.Bitfield("field", []BitfieldOp{
+		{Command: BITFIELD_SET, Signed: true, Bits: 8, Offset: 0, Value: -100, Overflow: OVERFLOW_WRAP},
+		{Command: BITFIELD_GET, Bits: 8, Offset: 0},
+		{Command: BITFIELD_INCRBY, Signed: true, Bits: 8, Offset: 0, Value: -100, Overflow: OVERFLOW_WRAP},
+		{Command: BITFIELD_INCRBY, Signed: true, Bits: 8, Offset: 0, Value: -100, Overflow: OVERFLOW_SAT},
+		{Command: BITFIELD_INCRBY, Bits: 2, Offset: 100, Value: 4, Overflow: OVERFLOW_FAIL},
+		{Command: BITFIELD_INCRBY, Bits: 2, Offset: 100, Value: 5, Overflow: OVERFLOW_WRAP},
+		{Command: BITFIELD_SET, Bits: 4, Offset: 100, Value: -1, Overflow: OVERFLOW_SAT},
+	})
+
+	assert.Equal(t, []interface{}{int64(0), int64(156), int64(56), int64(-44), nil, int64(1), int64(4)}, results)
+
+	length, _ := store.StrLen("field")
+	assert.Equal(t, 13, length)
+
+	results, _ = store.Bitfield("wide", []BitfieldOp{
+		{Command: BITFIELD_INCRBY, Signed: true, Bits: 64, Value: math.MaxInt64, Overflow: OVERFLOW_WRAP},
+		{Command: BITFIELD_INCRBY, Signed: true, Bits: 64, Value: 1, Overflow: OVERFLOW_WRAP},
+		{Command: BITFIELD_INCRBY, Signed: true, Bits: 64, Value: -1, Overflow: OVERFLOW_SAT},
+		{Command: BITFIELD_INCRBY, Bits: 63, Offset: 64, Value: -1, Overflow: OVERFLOW_SAT},
+	})
+	assert.Equal(t, []interface{}{int64(math.MaxInt64), int64(math.MinInt64), int64(math.MinInt64), int64(0)}, results)
+}
@@ -0,0 +1,121 @@
+package data
+
+import "slices"
+
+// Waiter is a client blocked until an element is pushed to one of its keys
+type Waiter struct {
+	keys []string
+	// serve runs the blocked command again, returns false when it still could
+	// not be served
+	serve func() bool
+}
+
+// Block registers a waiter on keys. Waiters on the same key are served in the
+// order they blocked, see ServeBlocked.
+func (s *Store) Block(keys []string, serve func() bool) *Waiter {
+	s.bl.Lock()
+	defer s.bl.Unlock()
+
+	waiter := &Waiter{keys: keys, serve: serve}
+
+	for _, key := range keys {
+		s.waiters[key] = append(s.waiters[key], waiter)
+	}
+
+	return waiter
+}
+
+// Unblock removes the waiter from every key it is waiting on, it is safe to
+// call more than once
+func (s *Store) Unblock(waiter *Waiter) {
+	s.bl.Lock()
+	defer s.bl.Unlock()
+
+	for _, key := range waiter.keys {
+		queue := s.waiters[key]
+
+		for i, queued := range queue {
+			if queued == waiter {
+				queue = append(queue[:i:i], queue[i+1:]...)
+				break
+			}
+		}
+
+		if len(queue) == 0 {
+			delete(s.waiters, key)
+		} else {
+			s.waiters[key] = queue
+		}
+	}
+}
+
+// ServeBlocked serves the waiters of the keys which received elements since
+// the last call. It must run after every command, by the same goroutine
+// executing commands, so blocked clients are served before anything else runs.
+func (s *Store) ServeBlocked() {
+	for {
+		s.bl.Lock()
+
+		if len(s.readyKeys) == 0 {
+			s.bl.Unlock()
+			return
+		}
+
+		key := s.readyKeys[0]
+		s.readyKeys = s.readyKeys[1:]
+		s.bl.Unlock()
+
+		s.serveKey(key)
+	}
+}
+
+// serveKey serves the waiters of key in the order they blocked. Each of them
+// is tried, as waiters of streams may wait for entries past different IDs.
+func (s *Store) serveKey(key string) {
+	s.bl.Lock()
+	queue := slices.Clone(s.waiters[key])
+	s.bl.Unlock()
+
+	for _, waiter := range queue {
+		if waiter.serve() {
+			s.Unblock(waiter)
+		}
+	}
+}
+
+// signalReady marks key as ready when clients are waiting on it
+func (s *Store) signalReady(key string) {
+	s.bl.Lock()
+	defer s.bl.Unlock()
+
+	if len(s.waiters[key]) == 0 {
+		return
+	}
+
+	for _, readyKey := range s.readyKeys {
+		if readyKey == key {
+			return
+		}
+	}
+
+	s.readyKeys = append(s.readyKeys, key)
+}
+
+// signalExisting marks the keys having waiters as ready when they exist, for
+// operations replacing the whole keyspace
+func (s *Store) signalExisting() {
+	s.bl.Lock()
+	keys := make([]string, 0, len(s.waiters))
+
+	for key := range s.waiters {
+		keys = append(keys, key)
+	}
+
+	s.bl.Unlock()
+
+	for _, key := range keys {
+		if s.Exists(key) {
+			s.signalReady(key)
+		}
+	}
+}
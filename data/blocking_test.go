@@ -0,0 +1,75 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// popper returns a serve function popping from key, recording who got what
+func popper(store *Store, name string, key string, served *[]string) func() bool {
+	return func() bool {
+		items, _ := store.LPop(key, 1)
+
+		if len(items) == 0 {
+			return false
+		}
+
+		*served = append(*served, name+":"+items[0].Value.(string))
+		return true
+	}
+}
+
+func TestServeBlockedInOrder(t *testing.T) {
+	store := NewStore()
+	served := []string{}
+
+	store.Block([]string{"queue"}, popper(store, "first", "queue", &served))
+	store.Block([]string{"other", "queue"}, popper(store, "second", "queue", &served))
+	store.Block([]string{"queue"}, popper(store, "third", "queue", &served))
+
+	store.Rpush("queue", "a", "b")
+	store.ServeBlocked()
+
+	assert.Equal(t, []string{"first:a", "second:b"}, served)
+	assert.False(t, store.Exists("queue"))
+
+	// the served waiters are gone from every key they waited on
+	store.Rpush("other", "x")
+	store.Rpush("queue", "c")
+	store.ServeBlocked()
+
+	assert.Equal(t, []string{"first:a", "second:b", "third:c"}, served)
+	assert.Equal(t, 0, len(store.waiters))
+}
+
+func TestUnblock(t *testing.T) {
+	store := NewStore()
+	served := []string{}
+
+	waiter := store.Block([]string{"queue"}, popper(store, "gone", "queue", &served))
+	store.Unblock(waiter)
+	store.Unblock(waiter)
+
+	store.Rpush("queue", "a")
+	store.ServeBlocked()
+
+	assert.Equal(t, []string{}, served)
+
+	length, _ := store.LLen("queue")
+	assert.Equal(t, 1, length)
+}
+
+func TestServeBlockedTriesEveryWaiter(t *testing.T) {
+	store := NewStore()
+	served := []string{}
+
+	store.Block([]string{"queue"}, func() bool { return false })
+	store.Block([]string{"queue"}, popper(store, "second", "queue", &served))
+
+	store.Rpush("queue", "a")
+	store.ServeBlocked()
+
+	assert.Equal(t, []string{"second:a"}, served)
+	assert.Equal(t, 1, len(store.waiters["queue"]))
+}
@@ -2,24 +2,53 @@ package data
 
 import (
 	"errors"
-	"strconv"
 	"sync"
 	"time"
 
-	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
 	"github.com/iamvineettiwari/go-redis-server-lite/resp"
 )
 
+var ErrWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
 type Store struct {
 	data map[string]interface{}
-	wl   *sync.RWMutex
+	// the keys of data in the order SCAN visits them
+	keyIndex *scan.Index
+	// absolute expiry time in unix milliseconds of the keys having a timeout
+	expires map[string]int64
+	wl      *sync.RWMutex
+	// clients blocked on keys and the keys which received elements since they
+	// were last served, guarded by bl
+	waiters   map[string][]*Waiter
+	readyKeys []string
+	bl        *sync.Mutex
+	// watches of the keys watched by transactions, guarded by wtl
+	watchers map[string]map[*Watch]struct{}
+	wtl      *sync.Mutex
+	// access statistics of the keys reported by OBJECT, guarded by al as
+	// reads record them while holding the read lock only
+	access map[string]*keyAccess
+	al     *sync.Mutex
 }
 
 func NewStore() *Store {
-	return &Store{
-		data: make(map[string]interface{}),
-		wl:   &sync.RWMutex{},
-	}
+	store := &Store{
+		data:     make(map[string]interface{}),
+		keyIndex: scan.NewIndex(),
+		expires:  make(map[string]int64),
+		wl:       &sync.RWMutex{},
+		waiters:  make(map[string][]*Waiter),
+		bl:       &sync.Mutex{},
+		watchers: make(map[string]map[*Watch]struct{}),
+		wtl:      &sync.Mutex{},
+		access:   make(map[string]*keyAccess),
+		al:       &sync.Mutex{},
+	}
+
+	go store.activeExpireCycle()
+
+	return store
 }
 
 func (s *Store) Set(key string, value interface{}, expireCommand string, expireTime int) {
@@ -27,18 +56,17 @@ func (s *Store) Set(key string, value interface{}, expireCommand string, expireT
 		return
 	}
 
-	s.setWithLock(key, value)
+	var expireAt int64
 
 	if expireCommand != "" && expireTime != 0 {
 		timeDuration := s.getTimeDuration(expireCommand, expireTime)
 
 		if timeDuration != time.Duration(0) {
-			go func() {
-				<-time.After(timeDuration)
-				s.deleteWithLock(key)
-			}()
+			expireAt = time.Now().Add(timeDuration).UnixMilli()
 		}
 	}
+
+	s.setWithExpiry(key, value, expireAt)
 }
 
 func (s *Store) Get(key string) (interface{}, bool, error) {
@@ -52,13 +80,13 @@ func (s *Store) Get(key string) (interface{}, bool, error) {
 		return data, found, nil
 	}
 
-	_, dataIsOfListType := data.(*list.List)
+	value, dataIsOfStringType := stringValue(data)
 
-	if dataIsOfListType {
-		return nil, found, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	if !dataIsOfStringType {
+		return nil, found, ErrWrongType
 	}
 
-	return data, found, nil
+	return value, found, nil
 }
 
 func (s *Store) Exists(key string) bool {
@@ -86,181 +114,131 @@ func (s *Store) Delete(key string) bool {
 	return true
 }
 
-func (s *Store) Incr(key string) (interface{}, error) {
+func (s *Store) LRange(key string, start, end int) (interface{}, error) {
 	if key == "" {
-		return nil, errors.New("Invalid operation")
+		return nil, errors.New("Invalid Operation")
 	}
 
-	data, exists, err := s.Get(key)
+	existList, err := s.getList(key, false)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !exists {
-		data = "1"
-		s.setWithLock(key, data)
-		return data, nil
-	}
-
-	data, isString := data.(string)
-
-	if !isString {
-		return nil, errors.New("ERR value is not an integer or out of range")
-	}
-
-	value, err := strconv.Atoi(data.(string))
-
-	if err != nil {
-		return nil, errors.New("ERR value is not an integer or out of range")
+	if existList == nil {
+		return []resp.ArrayType{}, nil
 	}
 
-	newValue := strconv.Itoa(value + 1)
-
-	s.setWithLock(key, newValue)
-	return newValue, nil
+	return existList.Range(start, end), nil
 }
 
-func (s *Store) Decr(key string) (interface{}, error) {
+// Lpush inserts the values at the head of the list, returns the length of the list
+func (s *Store) Lpush(key string, val ...interface{}) (int, error) {
 	if key == "" {
-		return nil, errors.New("Invalid operation")
-	}
-
-	data, exists, err := s.Get(key)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if !exists {
-		data = "-1"
-		s.setWithLock(key, data)
-		return data, nil
+		return 0, errors.New("Invalid operation")
 	}
 
-	data, isString := data.(string)
-
-	if !isString {
-		return nil, errors.New("ERR value is not an integer or out of range")
-	}
-
-	value, err := strconv.Atoi(data.(string))
-
-	if err != nil {
-		return nil, errors.New("ERR value is not an integer or out of range")
+	if len(val) < 1 {
+		return 0, errors.New("ERR wrong number of arguments for 'lpush' command")
 	}
 
-	newValue := strconv.Itoa(value - 1)
-
-	s.setWithLock(key, newValue)
-	return newValue, nil
+	return s.push(key, true, true, val...)
 }
 
-func (s *Store) LRange(key string, start, end int) (interface{}, error) {
+// Rpush inserts the values at the tail of the list, returns the length of the list
+func (s *Store) Rpush(key string, val ...interface{}) (int, error) {
 	if key == "" {
-		return nil, errors.New("Invalid Operation")
-	}
-
-	data, found := s.setLockAndGet(key)
-
-	if !found {
-		return []resp.ArrayType{}, nil
+		return 0, errors.New("Invalid operation")
 	}
 
-	list, isListType := data.(*list.List)
-
-	if !isListType {
-		return nil, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	if len(val) < 1 {
+		return 0, errors.New("ERR wrong number of arguments for 'rpush' command")
 	}
 
-	value := list.GetValues()
-
-	end = min(len(value), end+1)
-
-	return value[start:end], nil
+	return s.push(key, false, true, val...)
 }
 
-func (s *Store) Lpush(key string, val ...interface{}) (interface{}, error) {
-	if key == "" {
-		return nil, errors.New("Invalid operation")
-	}
+func (s *Store) setLockAndGet(key string) (data interface{}, found bool) {
+	data, found = s.peek(key)
 
-	if len(val) < 1 {
-		return nil, errors.New("ERR wrong number of arguments for 'lpush' command")
+	if found {
+		s.recordAccess(key)
 	}
 
-	data, found := s.setLockAndGet(key)
-
-	var existList *list.List
-	var typeMatch bool
-
-	if !found {
-		existList = list.NewList()
-	} else {
-		existList, typeMatch = data.(*list.List)
+	return
+}
 
-		if !typeMatch {
-			return nil, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
-		}
-	}
+// peek is setLockAndGet without recording an access to the key
+func (s *Store) peek(key string) (data interface{}, found bool) {
+	s.wl.RLock()
+	data, found = s.data[key]
+	expired := found && s.isExpired(key, time.Now().UnixMilli())
+	s.wl.RUnlock()
 
-	for _, item := range val {
-		existList.InsertLast(item, resp.BULK_STRING)
+	if expired {
+		s.expireIfNeeded(key)
+		return nil, false
 	}
 
-	s.setWithLock(key, existList)
-	return existList.GetValues(), nil
+	return
 }
 
-func (s *Store) Rpush(key string, val ...interface{}) (interface{}, error) {
-	if key == "" {
-		return nil, errors.New("Invalid operation")
-	}
-
-	if len(val) < 1 {
-		return nil, errors.New("ERR wrong number of arguments for 'rpush' command")
-	}
+// setWithLock replaces the value of the key, keeping its timeout if any
+func (s *Store) setWithLock(key string, value interface{}) {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+	s.putWithoutLock(key, value)
+	s.recordAccess(key)
+	s.touch(key)
+}
 
-	data, found := s.setLockAndGet(key)
+// setWithExpiry replaces the value and the timeout of the key,
+// a zero expireAt leaves the key without a timeout
+func (s *Store) setWithExpiry(key string, value interface{}, expireAt int64) {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+	s.setWithoutLock(key, value, expireAt)
+}
 
-	var existList *list.List
-	var typeMatch bool
+// setWithoutLock is setWithExpiry for callers already holding the write lock
+func (s *Store) setWithoutLock(key string, value interface{}, expireAt int64) {
+	s.putWithoutLock(key, value)
 
-	if !found {
-		existList = list.NewList()
+	if expireAt > 0 {
+		s.expires[key] = expireAt
 	} else {
-		existList, typeMatch = data.(*list.List)
-
-		if !typeMatch {
-			return nil, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
-		}
-	}
-
-	for _, item := range val {
-		existList.InsertFirst(item, resp.BULK_STRING)
+		delete(s.expires, key)
 	}
 
-	s.setWithLock(key, existList)
-	return existList.GetValues(), nil
+	s.recordAccess(key)
+	s.touch(key)
 }
 
-func (s *Store) setLockAndGet(key string) (data interface{}, found bool) {
-	s.wl.RLock()
-	defer s.wl.RUnlock()
-	data, found = s.data[key]
-	return
-}
+// putWithoutLock stores the value of the key, indexing the key when it is new
+func (s *Store) putWithoutLock(key string, value interface{}) {
+	if _, exists := s.data[key]; !exists {
+		s.keyIndex.Add(key)
+	}
 
-func (s *Store) setWithLock(key string, value interface{}) {
-	s.wl.Lock()
-	defer s.wl.Unlock()
 	s.data[key] = value
 }
 
 func (s *Store) deleteWithLock(key string) {
 	s.wl.Lock()
 	defer s.wl.Unlock()
+	s.removeWithoutLock(key)
+}
+
+// removeWithoutLock deletes the key, for callers already holding the write lock
+func (s *Store) removeWithoutLock(key string) {
+	if _, exists := s.data[key]; exists {
+		s.keyIndex.Remove(key)
+	}
+
 	delete(s.data, key)
+	delete(s.expires, key)
+	s.forgetAccess(key)
+	s.touch(key)
 }
 
 func (s *Store) getTimeDuration(expireCommand string, timeValue int) time.Duration {
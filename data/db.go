@@ -0,0 +1,79 @@
+package data
+
+import "github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+
+// Size returns the number of keys, including the expired ones which were
+// not removed yet
+func (s *Store) Size() int {
+	s.wl.RLock()
+	defer s.wl.RUnlock()
+
+	return len(s.data)
+}
+
+// Flush removes every key
+func (s *Store) Flush() {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	s.touchExisting(s.data)
+
+	s.data = make(map[string]interface{})
+	s.keyIndex = scan.NewIndex()
+	s.expires = make(map[string]int64)
+
+	s.al.Lock()
+	s.access = make(map[string]*keyAccess)
+	s.al.Unlock()
+}
+
+// Swap exchanges the keys of both stores. Watches and blocked clients stay
+// with their store, they see the keys of the other one from now on.
+func (s *Store) Swap(other *Store) {
+	if s == other {
+		return
+	}
+
+	// commands run one at a time, so no other swap can lock them the other way around
+	s.wl.Lock()
+	other.wl.Lock()
+
+	s.touchExisting(s.data, other.data)
+	other.touchExisting(s.data, other.data)
+
+	s.data, other.data = other.data, s.data
+	s.keyIndex, other.keyIndex = other.keyIndex, s.keyIndex
+	s.expires, other.expires = other.expires, s.expires
+
+	s.al.Lock()
+	other.al.Lock()
+	s.access, other.access = other.access, s.access
+	other.al.Unlock()
+	s.al.Unlock()
+
+	other.wl.Unlock()
+	s.wl.Unlock()
+
+	s.signalExisting()
+	other.signalExisting()
+}
+
+// Move moves the key to destination along with its timeout. Nothing is moved
+// when the key does not exist, or when it already exists in destination.
+func (s *Store) Move(key string, destination *Store) bool {
+	value, found := s.setLockAndGet(key)
+
+	if !found || destination.Exists(key) {
+		return false
+	}
+
+	s.wl.RLock()
+	expireAt := s.expires[key]
+	s.wl.RUnlock()
+
+	destination.setWithExpiry(key, value, expireAt)
+	destination.signalReady(key)
+	s.deleteWithLock(key)
+
+	return true
+}
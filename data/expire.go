@@ -0,0 +1,202 @@
+package data
+
+import (
+	"errors"
+	"time"
+)
+
+// expire conditions
+const (
+	EXPIRE_ALWAYS string = ""
+	EXPIRE_NX     string = "NX"
+	EXPIRE_XX     string = "XX"
+	EXPIRE_GT     string = "GT"
+	EXPIRE_LT     string = "LT"
+)
+
+// ttl replies for keys without a timeout
+const (
+	TTL_NO_KEY    int64 = -2
+	TTL_NO_EXPIRE int64 = -1
+)
+
+// active expiration tuning, every cycle samples a few keys having a timeout and
+// repeats as long as enough of them turned out to be expired
+const (
+	expireCycleInterval      = 100 * time.Millisecond
+	expireCycleSampleSize    = 20
+	expireCycleRepeatPercent = 25
+	expireCycleMaxDuration   = 25 * time.Millisecond
+)
+
+// Expire sets the timeout of the key to the given unix time in milliseconds when
+// the condition holds. A time in the past deletes the key right away.
+// Returns whether the timeout was set.
+func (s *Store) Expire(key string, expireAt int64, condition string) (bool, error) {
+	if key == "" {
+		return false, errors.New("Invalid operation")
+	}
+
+	now := time.Now().UnixMilli()
+
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	if s.expireWithoutLock(key, now) {
+		return false, nil
+	}
+
+	if _, found := s.data[key]; !found {
+		return false, nil
+	}
+
+	current, hasExpiry := s.expires[key]
+
+	switch condition {
+	case EXPIRE_NX:
+		if hasExpiry {
+			return false, nil
+		}
+	case EXPIRE_XX:
+		if !hasExpiry {
+			return false, nil
+		}
+	case EXPIRE_GT:
+		// a key without timeout has an infinite ttl, which can not be increased
+		if !hasExpiry || expireAt <= current {
+			return false, nil
+		}
+	case EXPIRE_LT:
+		if hasExpiry && expireAt >= current {
+			return false, nil
+		}
+	}
+
+	if expireAt <= now {
+		s.removeWithoutLock(key)
+		return true, nil
+	}
+
+	s.expires[key] = expireAt
+	s.touch(key)
+	return true, nil
+}
+
+// Persist removes the timeout of the key, returns whether there was one
+func (s *Store) Persist(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	if _, found := s.setLockAndGet(key); !found {
+		return false
+	}
+
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	if _, hasExpiry := s.expires[key]; !hasExpiry {
+		return false
+	}
+
+	delete(s.expires, key)
+	s.touch(key)
+	return true
+}
+
+// ExpireTime returns the unix time in milliseconds at which the key expires,
+// TTL_NO_KEY when it does not exist and TTL_NO_EXPIRE when it has no timeout
+func (s *Store) ExpireTime(key string) int64 {
+	if _, found := s.setLockAndGet(key); !found {
+		return TTL_NO_KEY
+	}
+
+	s.wl.RLock()
+	defer s.wl.RUnlock()
+
+	expireAt, hasExpiry := s.expires[key]
+
+	if !hasExpiry {
+		return TTL_NO_EXPIRE
+	}
+
+	return expireAt
+}
+
+// TTL returns the remaining time to live of the key in milliseconds,
+// TTL_NO_KEY when it does not exist and TTL_NO_EXPIRE when it has no timeout
+func (s *Store) TTL(key string) int64 {
+	expireAt := s.ExpireTime(key)
+
+	if expireAt < 0 {
+		return expireAt
+	}
+
+	return max(expireAt-time.Now().UnixMilli(), 0)
+}
+
+func (s *Store) isExpired(key string, now int64) bool {
+	expireAt, hasExpiry := s.expires[key]
+	return hasExpiry && expireAt <= now
+}
+
+// expireIfNeeded deletes the key when its timeout has passed
+func (s *Store) expireIfNeeded(key string) bool {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+	return s.expireWithoutLock(key, time.Now().UnixMilli())
+}
+
+// expireWithoutLock is expireIfNeeded for callers already holding the write lock
+func (s *Store) expireWithoutLock(key string, now int64) bool {
+	if !s.isExpired(key, now) {
+		return false
+	}
+
+	s.removeWithoutLock(key)
+	return true
+}
+
+// activeExpireCycle periodically removes expired keys which are never accessed
+// again, so they do not hold memory forever
+func (s *Store) activeExpireCycle() {
+	ticker := time.NewTicker(expireCycleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		start := time.Now()
+
+		for time.Since(start) < expireCycleMaxDuration {
+			if !s.expireSample() {
+				break
+			}
+		}
+	}
+}
+
+// expireSample checks a random sample of keys having a timeout and deletes the
+// expired ones, returns whether another sample is worth checking
+func (s *Store) expireSample() bool {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	now := time.Now().UnixMilli()
+	sampled := 0
+	expired := 0
+
+	// map iteration order is randomized, which makes this a random sample
+	for key, expireAt := range s.expires {
+		if sampled >= expireCycleSampleSize {
+			break
+		}
+
+		sampled++
+
+		if expireAt <= now {
+			s.removeWithoutLock(key)
+			expired++
+		}
+	}
+
+	return sampled > 0 && expired*100 > sampled*expireCycleRepeatPercent
+}
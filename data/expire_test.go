@@ -0,0 +1,81 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetReplacesTimeout(t *testing.T) {
+	store := NewStore()
+
+	store.Set("key", "old", "PX", 50)
+	store.Set("key", "new", "", 0)
+
+	<-time.After(80 * time.Millisecond)
+
+	value, found, _ := store.Get("key")
+
+	assert.True(t, found)
+	assert.Equal(t, value, "new")
+	assert.Equal(t, store.TTL("key"), TTL_NO_EXPIRE)
+}
+
+func TestLazyExpiration(t *testing.T) {
+	store := NewStore()
+
+	store.Set("key", "value", "PX", 20)
+	assert.True(t, store.TTL("key") > 0)
+
+	<-time.After(30 * time.Millisecond)
+
+	assert.False(t, store.Exists("key"))
+	assert.Equal(t, store.TTL("key"), TTL_NO_KEY)
+}
+
+func TestActiveExpiration(t *testing.T) {
+	store := NewStore()
+
+	for _, key := range []string{"a", "b", "c"} {
+		store.Set(key, "value", "PX", 10)
+	}
+
+	<-time.After(3 * expireCycleInterval)
+
+	store.wl.RLock()
+	defer store.wl.RUnlock()
+
+	assert.Equal(t, len(store.data), 0)
+	assert.Equal(t, len(store.expires), 0)
+}
+
+func TestExpireConditions(t *testing.T) {
+	store := NewStore()
+	store.Set("key", "value", "", 0)
+
+	later := time.Now().Add(time.Hour).UnixMilli()
+
+	updated, _ := store.Expire("key", later, EXPIRE_XX)
+	assert.False(t, updated)
+
+	updated, _ = store.Expire("key", later, EXPIRE_GT)
+	assert.False(t, updated)
+
+	updated, _ = store.Expire("key", later, EXPIRE_LT)
+	assert.True(t, updated)
+
+	updated, _ = store.Expire("key", later, EXPIRE_NX)
+	assert.False(t, updated)
+
+	updated, _ = store.Expire("key", later+1000, EXPIRE_GT)
+	assert.True(t, updated)
+	assert.Equal(t, store.ExpireTime("key"), later+1000)
+
+	assert.True(t, store.Persist("key"))
+	assert.False(t, store.Persist("key"))
+
+	updated, _ = store.Expire("key", time.Now().UnixMilli()-1, EXPIRE_ALWAYS)
+	assert.True(t, updated)
+	assert.False(t, store.Exists("key"))
+}
@@ -0,0 +1,135 @@
+package data
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/geo"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+)
+
+var ErrGeoMember = errors.New("ERR could not decode requested zset member")
+
+// GeoLocation is a member of a geo index along with its position. Geo indexes
+// are sorted sets scored by the geohash of their members, so the sorted set
+// commands work on them too.
+type GeoLocation struct {
+	Member string
+	Point  geo.Point
+}
+
+// GeoQuery selects the members of a geo search. The shape is centered on the
+// position of Member when FromMember is set. Count limits the number of results
+// unless zero, and with Any the search stops once Count members are found.
+type GeoQuery struct {
+	Shape      geo.Shape
+	Member     string
+	FromMember bool
+	Sort       int
+	Count      int
+	Any        bool
+}
+
+// GeoAdd adds the members at their position or moves them according to the
+// zset.ADD_* flags. Returns the number of added members and of moved ones.
+func (s *Store) GeoAdd(key string, locations []GeoLocation, flags int) (int, int, error) {
+	entries := []zset.Entry{}
+
+	for _, location := range locations {
+		entries = append(entries, zset.Entry{Member: location.Member, Score: float64(geo.Encode(location.Point))})
+	}
+
+	return s.ZAdd(key, entries, flags)
+}
+
+// GeoPos returns the positions of the members, nil for the ones which are not
+// members
+func (s *Store) GeoPos(key string, members ...string) ([]*geo.Point, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*geo.Point, len(members))
+
+	if existSet == nil {
+		return positions, nil
+	}
+
+	for i, member := range members {
+		if score, found := existSet.Score(member); found {
+			point := geo.Decode(uint64(score))
+			positions[i] = &point
+		}
+	}
+
+	return positions, nil
+}
+
+// GeoDist returns the distance in meters between two members, false when one
+// of them is not a member
+func (s *Store) GeoDist(key string, member1 string, member2 string) (float64, bool, error) {
+	positions, err := s.GeoPos(key, member1, member2)
+
+	if err != nil || positions[0] == nil || positions[1] == nil {
+		return 0, false, err
+	}
+
+	return geo.Distance(*positions[0], *positions[1]), true, nil
+}
+
+// GeoSearch returns the members within the shape of the query. Only the cells
+// of the index around the shape are scanned, see geo.Shape.Ranges.
+func (s *Store) GeoSearch(key string, query GeoQuery) ([]geo.Result, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []geo.Result{}, err
+	}
+
+	if query.FromMember {
+		score, found := existSet.Score(query.Member)
+
+		if !found {
+			return nil, ErrGeoMember
+		}
+
+		query.Shape.Center = geo.Decode(uint64(score))
+	}
+
+	results := []geo.Result{}
+
+scan:
+	for _, hashRange := range query.Shape.Ranges() {
+		scoreRange := zset.ScoreRange{Min: float64(hashRange.Min), Max: float64(hashRange.Max), MaxExclusive: true}
+
+		for _, entry := range existSet.RangeByScore(scoreRange, false, 0, -1) {
+			point := geo.Decode(uint64(entry.Score))
+			distance, inside := query.Shape.Contains(point)
+
+			if !inside {
+				continue
+			}
+
+			results = append(results, geo.Result{Member: entry.Member, Point: point, Hash: uint64(entry.Score), Distance: distance})
+
+			if query.Any && len(results) == query.Count {
+				break scan
+			}
+		}
+	}
+
+	switch query.Sort {
+	case geo.SORT_ASC:
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+	case geo.SORT_DESC:
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Distance > results[j].Distance })
+	}
+
+	if query.Count > 0 && len(results) > query.Count {
+		results = results[:query.Count]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	palermo = Point{Longitude: 13.361389, Latitude: 38.115556}
+	catania = Point{Longitude: 15.087269, Latitude: 37.502669}
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// the scores redis gives to the same positions
+	assert.Equal(t, uint64(3479099956230698), Encode(palermo))
+	assert.Equal(t, uint64(3479447370796909), Encode(catania))
+
+	decoded := Decode(Encode(palermo))
+	assert.InDelta(t, palermo.Longitude, decoded.Longitude, 1e-5)
+	assert.InDelta(t, palermo.Latitude, decoded.Latitude, 1e-5)
+
+	assert.Less(t, Encode(Point{Longitude: LONGITUDE_MAX, Latitude: LATITUDE_MAX}), uint64(1)<<HASH_BITS)
+	assert.False(t, Point{Longitude: 0, Latitude: 86}.Valid())
+}
+
+func TestHashAndDistance(t *testing.T) {
+	assert.Equal(t, "sqc8b49rny0", Hash(Decode(Encode(palermo))))
+	assert.Equal(t, "sqdtr74hyu0", Hash(Decode(Encode(catania))))
+
+	assert.InDelta(t, 166274.1516, Distance(Decode(Encode(palermo)), Decode(Encode(catania))), 1e-4)
+	assert.Equal(t, 0.0, Distance(palermo, palermo))
+}
+
+// search returns the positions within the shape, scanning its ranges
+func search(shape Shape, hashes []uint64) map[uint64]bool {
+	found := map[uint64]bool{}
+
+	for _, hashRange := range shape.Ranges() {
+		for _, hash := range hashes {
+			if _, inside := shape.Contains(Decode(hash)); inside && hash >= hashRange.Min && hash < hashRange.Max {
+				found[hash] = true
+			}
+		}
+	}
+
+	return found
+}
+
+func TestRangesCoverShape(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	hashes := []uint64{}
+
+	for i := 0; i < 5000; i++ {
+		point := Point{Longitude: random.Float64()*40 - 20, Latitude: random.Float64()*40 + 20}
+		hashes = append(hashes, Encode(point))
+	}
+
+	shapes := []Shape{
+		{Center: Point{Longitude: 0, Latitude: 40}, Radius: 500000},
+		{Center: Point{Longitude: 3.3, Latitude: 31.7}, Radius: 60000},
+		{Center: Point{Longitude: -7, Latitude: 52}, Box: true, Width: 800000, Height: 300000},
+		{Center: Point{Longitude: 10, Latitude: 25}, Box: true, Width: 50000, Height: 900000},
+	}
+
+	for _, shape := range shapes {
+		expected := map[uint64]bool{}
+
+		for _, hash := range hashes {
+			if _, inside := shape.Contains(Decode(hash)); inside {
+				expected[hash] = true
+			}
+		}
+
+		assert.NotEmpty(t, expected)
+		assert.Equal(t, expected, search(shape, hashes))
+	}
+}
+
+func TestRangesWrapAround(t *testing.T) {
+	east, west := Encode(Point{Longitude: 179.9}), Encode(Point{Longitude: -179.9})
+	shape := Shape{Center: Point{Longitude: 180}, Radius: 50000}
+
+	assert.Equal(t, map[uint64]bool{east: true, west: true}, search(shape, []uint64{east, west}))
+}
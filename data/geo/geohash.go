@@ -0,0 +1,174 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Positions are indexed by the geohash redis uses: the longitude and the
+// latitude are each divided in 2^STEP_MAX intervals, and the indexes of both
+// intervals are interleaved into a 52 bits hash, the longitude taking the odd
+// bits. Hashes are exact as the score of a sorted set, and positions sharing a
+// prefix of their hash are within the same cell of a coarser grid.
+const (
+	STEP_MAX  = 26
+	HASH_BITS = 2 * STEP_MAX
+
+	LONGITUDE_MIN = -180.0
+	LONGITUDE_MAX = 180.0
+	// the latitudes of the web mercator projection
+	LATITUDE_MIN = -85.05112878
+	LATITUDE_MAX = 85.05112878
+
+	// radius of the earth in meters, the one redis uses
+	EARTH_RADIUS = 6372797.560856
+	// half the circumference of the earth in the web mercator projection
+	MERCATOR_MAX = 20037726.37
+)
+
+// alphabet of the standard geohash strings
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+type Point struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// ErrInvalidPoint is the error for positions which cannot be indexed
+func ErrInvalidPoint(p Point) error {
+	return fmt.Errorf("ERR invalid longitude,latitude pair %f,%f", p.Longitude, p.Latitude)
+}
+
+// Valid reports whether the position can be indexed
+func (p Point) Valid() bool {
+	return p.Longitude >= LONGITUDE_MIN && p.Longitude <= LONGITUDE_MAX &&
+		p.Latitude >= LATITUDE_MIN && p.Latitude <= LATITUDE_MAX
+}
+
+// cell is the area covered by a hash of step bits per coordinate
+type cell struct {
+	hash   uint64
+	step   int
+	minLon float64
+	maxLon float64
+	minLat float64
+	maxLat float64
+}
+
+func (c cell) center() Point {
+	return Point{Longitude: (c.minLon + c.maxLon) / 2, Latitude: (c.minLat + c.maxLat) / 2}
+}
+
+// Encode returns the hash of the position, which must be valid
+func Encode(p Point) uint64 {
+	return encode(p, STEP_MAX, LATITUDE_MIN, LATITUDE_MAX)
+}
+
+// Decode returns the center of the cell of the hash
+func Decode(hash uint64) Point {
+	center := decode(hash, STEP_MAX, LATITUDE_MIN, LATITUDE_MAX).center()
+
+	return Point{
+		Longitude: min(max(center.Longitude, LONGITUDE_MIN), LONGITUDE_MAX),
+		Latitude:  min(max(center.Latitude, LATITUDE_MIN), LATITUDE_MAX),
+	}
+}
+
+// Hash returns the 11 characters geohash string of the position. Standard
+// geohashes cover the latitudes from -90 to 90 and the last character is
+// always 0, as only 52 bits are known.
+func Hash(p Point) string {
+	hash := encode(p, STEP_MAX, -90, 90)
+	result := make([]byte, 11)
+
+	for i := 0; i < 10; i++ {
+		result[i] = base32[hash>>(HASH_BITS-(i+1)*5)&0x1f]
+	}
+
+	result[10] = base32[0]
+	return string(result)
+}
+
+// Distance returns the distance in meters between both positions with the
+// haversine formula
+func Distance(a Point, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	v := math.Sin((radians(b.Longitude) - radians(a.Longitude)) / 2)
+
+	if v == 0 {
+		return latitudeDistance(a.Latitude, b.Latitude)
+	}
+
+	u := math.Sin((lat2 - lat1) / 2)
+	h := u*u + math.Cos(lat1)*math.Cos(lat2)*v*v
+
+	return 2 * EARTH_RADIUS * math.Asin(math.Sqrt(h))
+}
+
+// latitudeDistance returns the distance in meters between two latitudes along
+// a meridian
+func latitudeDistance(lat1 float64, lat2 float64) float64 {
+	return EARTH_RADIUS * math.Abs(radians(lat2)-radians(lat1))
+}
+
+func encode(p Point, step int, latMin float64, latMax float64) uint64 {
+	cells := uint64(1) << step
+
+	latIndex := uint64((p.Latitude - latMin) / (latMax - latMin) * float64(cells))
+	lonIndex := uint64((p.Longitude - LONGITUDE_MIN) / (LONGITUDE_MAX - LONGITUDE_MIN) * float64(cells))
+
+	// the highest latitude and longitude belong to the last interval
+	latIndex = min(latIndex, cells-1)
+	lonIndex = min(lonIndex, cells-1)
+
+	return interleave(latIndex, lonIndex)
+}
+
+func decode(hash uint64, step int, latMin float64, latMax float64) cell {
+	latIndex, lonIndex := deinterleave(hash)
+	cells := float64(uint64(1) << step)
+
+	latScale := latMax - latMin
+	lonScale := LONGITUDE_MAX - LONGITUDE_MIN
+
+	return cell{
+		hash:   hash,
+		step:   step,
+		minLon: LONGITUDE_MIN + float64(lonIndex)/cells*lonScale,
+		maxLon: LONGITUDE_MIN + float64(lonIndex+1)/cells*lonScale,
+		minLat: latMin + float64(latIndex)/cells*latScale,
+		maxLat: latMin + float64(latIndex+1)/cells*latScale,
+	}
+}
+
+// interleave places the bits of even at the even positions of the result and
+// the bits of odd at the odd ones
+func interleave(even uint64, odd uint64) uint64 {
+	var result uint64
+
+	for i := 0; i < 32; i++ {
+		result |= (even >> i & 1) << (2 * i)
+		result |= (odd >> i & 1) << (2*i + 1)
+	}
+
+	return result
+}
+
+func deinterleave(hash uint64) (uint64, uint64) {
+	var even, odd uint64
+
+	for i := 0; i < 32; i++ {
+		even |= (hash >> (2 * i) & 1) << i
+		odd |= (hash >> (2*i + 1) & 1) << i
+	}
+
+	return even, odd
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
@@ -0,0 +1,192 @@
+package geo
+
+import "math"
+
+// sort orders of search results
+const (
+	SORT_NONE = iota
+	SORT_ASC
+	SORT_DESC
+)
+
+// Result is a position found by a search, along with its distance in meters
+// from the center of the shape
+type Result struct {
+	Member   string
+	Point    Point
+	Hash     uint64
+	Distance float64
+}
+
+// Shape is the area of a search, a circle of Radius around Center, or a box
+// of Width by Height centered on it when Box is set. Sizes are in meters.
+type Shape struct {
+	Center Point
+	Box    bool
+	Radius float64
+	Width  float64
+	Height float64
+}
+
+// Range is a range of hashes, Min being inclusive and Max exclusive
+type Range struct {
+	Min uint64
+	Max uint64
+}
+
+// Contains returns the distance between the center of the shape and the
+// position, false when the position is outside of the shape
+func (s Shape) Contains(p Point) (float64, bool) {
+	if !s.Box {
+		distance := Distance(s.Center, p)
+		return distance, distance <= s.Radius
+	}
+
+	// the latitude distance is the cheapest to compute, so it is checked first
+	if latitudeDistance(s.Center.Latitude, p.Latitude) > s.Height/2 {
+		return 0, false
+	}
+
+	if Distance(Point{Longitude: s.Center.Longitude, Latitude: p.Latitude}, p) > s.Width/2 {
+		return 0, false
+	}
+
+	return Distance(s.Center, p), true
+}
+
+// Ranges returns the ranges of hashes to scan for the positions within the
+// shape, the ones of the cell holding its center and of the 8 cells around it.
+// The cells are the smallest ones for which this covers the whole shape.
+func (s Shape) Ranges() []Range {
+	minLon, minLat, maxLon, maxLat := s.bounds()
+
+	radius := s.Radius
+
+	if s.Box {
+		radius = math.Sqrt(s.Width*s.Width+s.Height*s.Height) / 2
+	}
+
+	step := estimateStep(radius, s.Center.Latitude)
+	cells := neighbours(s.Center, step)
+
+	// near the edges of its cell, the shape may overflow the cells around it
+	north, south, east, west := cells[1][2], cells[1][0], cells[2][1], cells[0][1]
+
+	if step > 1 && (north.maxLat < maxLat || south.minLat > minLat || east.maxLon < maxLon || west.minLon > minLon) {
+		step--
+		cells = neighbours(s.Center, step)
+	}
+
+	center := cells[1][1]
+	useful := [3][3]bool{}
+
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			useful[x][y] = step < 2 ||
+				!(y == 0 && center.minLat < minLat) && !(y == 2 && center.maxLat > maxLat) &&
+					!(x == 0 && center.minLon < minLon) && !(x == 2 && center.maxLon > maxLon)
+		}
+	}
+
+	ranges := []Range{}
+	seen := map[uint64]bool{}
+	shift := HASH_BITS - 2*step
+
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			hash := cells[x][y].hash
+
+			// around the poles and with large cells, neighbours may repeat
+			if !useful[x][y] || cells[x][y].step == 0 || seen[hash] {
+				continue
+			}
+
+			seen[hash] = true
+			ranges = append(ranges, Range{Min: hash << shift, Max: (hash + 1) << shift})
+		}
+	}
+
+	return ranges
+}
+
+// bounds returns the longitudes and latitudes bounding the shape
+func (s Shape) bounds() (float64, float64, float64, float64) {
+	width, height := s.Radius, s.Radius
+
+	if s.Box {
+		width, height = s.Width/2, s.Height/2
+	}
+
+	latitude := s.Center.Latitude
+	latDelta := degrees(height / EARTH_RADIUS)
+	lonDeltaTop := degrees(width / EARTH_RADIUS / math.Cos(radians(latitude+latDelta)))
+	lonDeltaBottom := degrees(width / EARTH_RADIUS / math.Cos(radians(latitude-latDelta)))
+
+	// the widest side of the shape is the one closest to the equator
+	lonDelta := lonDeltaTop
+
+	if latitude < 0 {
+		lonDelta = lonDeltaBottom
+	}
+
+	return s.Center.Longitude - lonDelta, latitude - latDelta, s.Center.Longitude + lonDelta, latitude + latDelta
+}
+
+// estimateStep returns the number of bits per coordinate of the cells in
+// which a circle of radius meters is mostly included
+func estimateStep(radius float64, latitude float64) int {
+	if radius == 0 {
+		return STEP_MAX
+	}
+
+	step := 1
+
+	for radius < MERCATOR_MAX {
+		radius *= 2
+		step++
+	}
+
+	step -= 2
+
+	// the cells get narrower towards the poles
+	if latitude > 66 || latitude < -66 {
+		step--
+
+		if latitude > 80 || latitude < -80 {
+			step--
+		}
+	}
+
+	return min(max(step, 1), STEP_MAX)
+}
+
+// neighbours returns the cell holding p at step, indexed [1][1], and the ones
+// around it indexed by their direction, [0][1] being west and [1][2] north.
+// Cells past the poles have a zero step, longitudes wrap around.
+func neighbours(p Point, step int) [3][3]cell {
+	center := decode(encode(p, step, LATITUDE_MIN, LATITUDE_MAX), step, LATITUDE_MIN, LATITUDE_MAX)
+	width, height := center.maxLon-center.minLon, center.maxLat-center.minLat
+	cells := [3][3]cell{}
+
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			neighbour := center.center()
+			neighbour.Longitude += float64(x-1) * width
+			neighbour.Latitude += float64(y-1) * height
+
+			if neighbour.Latitude < LATITUDE_MIN || neighbour.Latitude > LATITUDE_MAX {
+				continue
+			}
+
+			if neighbour.Longitude > LONGITUDE_MAX {
+				neighbour.Longitude -= LONGITUDE_MAX - LONGITUDE_MIN
+			} else if neighbour.Longitude < LONGITUDE_MIN {
+				neighbour.Longitude += LONGITUDE_MAX - LONGITUDE_MIN
+			}
+
+			cells[x][y] = decode(encode(neighbour, step, LATITUDE_MIN, LATITUDE_MAX), step, LATITUDE_MIN, LATITUDE_MAX)
+		}
+	}
+
+	return cells
+}
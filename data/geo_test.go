@@ -0,0 +1,58 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoSearch(t *testing.T) {
+	store := NewStore()
+
+	added, _, err := store.GeoAdd("sicily", []GeoLocation{
+		{Member: "Palermo", Point: geo.Point{Longitude: 13.361389, Latitude: 38.115556}},
+		{Member: "Catania", Point: geo.Point{Longitude: 15.087269, Latitude: 37.502669}},
+		{Member: "edge1", Point: geo.Point{Longitude: 12.758489, Latitude: 38.788135}},
+		{Member: "edge2", Point: geo.Point{Longitude: 17.241510, Latitude: 38.788135}},
+	}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, added)
+
+	center := geo.Point{Longitude: 15, Latitude: 37}
+
+	results, _ := store.GeoSearch("sicily", GeoQuery{Shape: geo.Shape{Center: center, Radius: 200000}, Sort: geo.SORT_ASC})
+	assert.Equal(t, []string{"Catania", "Palermo"}, geoMembers(results))
+	assert.InDelta(t, 56441.3, results[0].Distance, 0.1)
+
+	results, _ = store.GeoSearch("sicily", GeoQuery{Shape: geo.Shape{Center: center, Box: true, Width: 400000, Height: 400000}, Sort: geo.SORT_DESC, Count: 2})
+	assert.Equal(t, []string{"edge1", "edge2"}, geoMembers(results))
+
+	results, _ = store.GeoSearch("sicily", GeoQuery{Shape: geo.Shape{Radius: 100000}, Member: "Palermo", FromMember: true, Sort: geo.SORT_ASC})
+	assert.Equal(t, []string{"Palermo", "edge1"}, geoMembers(results))
+
+	_, err = store.GeoSearch("sicily", GeoQuery{Shape: geo.Shape{Radius: 100000}, Member: "Rome", FromMember: true})
+	assert.Equal(t, ErrGeoMember, err)
+
+	results, err = store.GeoSearch("missing", GeoQuery{Member: "Rome", FromMember: true})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	distance, found, _ := store.GeoDist("sicily", "Palermo", "Catania")
+	assert.True(t, found)
+	assert.InDelta(t, 166274.1516, distance, 1e-4)
+
+	positions, _ := store.GeoPos("sicily", "Rome", "Palermo")
+	assert.Nil(t, positions[0])
+	assert.InDelta(t, 13.361389, positions[1].Longitude, 1e-5)
+}
+
+func geoMembers(results []geo.Result) []string {
+	members := []string{}
+
+	for _, result := range results {
+		members = append(members, result.Member)
+	}
+
+	return members
+}
@@ -0,0 +1,128 @@
+package hash
+
+import (
+	"sync"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+)
+
+type Hash struct {
+	fields map[string]string
+	// the fields in the order HSCAN visits them
+	index *scan.Index
+	lock  *sync.RWMutex
+}
+
+func NewHash() *Hash {
+	return &Hash{
+		fields: make(map[string]string),
+		index:  scan.NewIndex(),
+		lock:   &sync.RWMutex{},
+	}
+}
+
+func (h *Hash) IsEmpty() bool {
+	return h.Len() == 0
+}
+
+func (h *Hash) Len() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return len(h.fields)
+}
+
+// Set stores the value of the field, returns whether the field is new
+func (h *Hash) Set(field string, value string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	_, exists := h.fields[field]
+	h.fields[field] = value
+
+	if !exists {
+		h.index.Add(field)
+	}
+
+	return !exists
+}
+
+func (h *Hash) Get(field string) (string, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	value, found := h.fields[field]
+	return value, found
+}
+
+// Delete removes the field, returns whether it existed
+func (h *Hash) Delete(field string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	_, exists := h.fields[field]
+	delete(h.fields, field)
+	h.index.Remove(field)
+
+	return exists
+}
+
+func (h *Hash) Fields() []string {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	fields := make([]string, 0, len(h.fields))
+
+	for field := range h.fields {
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Entries returns a copy of every field and its value
+func (h *Hash) Entries() map[string]string {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	entries := make(map[string]string, len(h.fields))
+
+	for field, value := range h.fields {
+		entries[field] = value
+	}
+
+	return entries
+}
+
+// Scan returns up to count fields not visited yet by the iteration at cursor
+// along with their values, see scan.Scan for the cursor guarantees
+func (h *Hash) Scan(cursor uint64, count int) ([][2]string, uint64) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	fields, nextCursor := h.index.Scan(cursor, count)
+	entries := make([][2]string, 0, len(fields))
+
+	for _, field := range fields {
+		entries = append(entries, [2]string{field, h.fields[field]})
+	}
+
+	return entries, nextCursor
+}
+
+// Clone returns a copy of the hash which shares nothing with the original
+func (h *Hash) Clone() *Hash {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	fields := make(map[string]string, len(h.fields))
+
+	for field, value := range h.fields {
+		fields[field] = value
+	}
+
+	return &Hash{
+		fields: fields,
+		index:  h.index.Clone(),
+		lock:   &sync.RWMutex{},
+	}
+}
@@ -0,0 +1,74 @@
+package hash
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAndDelete(t *testing.T) {
+	h := NewHash()
+
+	assert.True(t, h.IsEmpty())
+	assert.True(t, h.Set("a", "1"))
+	assert.True(t, h.Set("b", "2"))
+	assert.False(t, h.Set("a", "3"))
+
+	value, found := h.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, "3", value)
+	assert.Equal(t, 2, h.Len())
+
+	assert.True(t, h.Delete("a"))
+	assert.False(t, h.Delete("a"))
+
+	_, found = h.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 1, h.Len())
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	h := NewHash()
+	h.Set("a", "1")
+	h.Set("b", "2")
+
+	clone := h.Clone()
+	clone.Set("a", "changed")
+	clone.Delete("b")
+
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, h.Entries())
+	assert.Equal(t, map[string]string{"a": "changed"}, clone.Entries())
+
+	fields := h.Fields()
+	sort.Strings(fields)
+	assert.Equal(t, []string{"a", "b"}, fields)
+}
+
+func TestScanFollowsChanges(t *testing.T) {
+	h := NewHash()
+
+	for i := 0; i < 100; i++ {
+		h.Set(strconv.Itoa(i), "value")
+	}
+
+	h.Set("0", "updated")
+	h.Delete("99")
+	clone := h.Clone()
+	clone.Delete("0")
+
+	entries, cursor := h.Scan(0, 1000)
+	assert.Len(t, entries, 99)
+	assert.Equal(t, uint64(0), cursor)
+	assert.Contains(t, entries, [2]string{"0", "updated"})
+	assert.NotContains(t, entries, [2]string{"99", "value"})
+
+	// the clone has its own order
+	entries, _ = clone.Scan(0, 1000)
+	assert.Len(t, entries, 98)
+
+	entries, cursor = h.Scan(0, 10)
+	assert.GreaterOrEqual(t, len(entries), 10)
+	assert.NotEqual(t, uint64(0), cursor)
+}
@@ -0,0 +1,286 @@
+package data
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+)
+
+// getHash returns the hash stored at key, creating an empty one when create is
+// set. The returned hash is nil when the key does not exist and create is not set.
+func (s *Store) getHash(key string, create bool) (*hash.Hash, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		if !create {
+			return nil, nil
+		}
+
+		return hash.NewHash(), nil
+	}
+
+	existHash, isHashType := data.(*hash.Hash)
+
+	if !isHashType {
+		return nil, ErrWrongType
+	}
+
+	return s.own(key, existHash).(*hash.Hash), nil
+}
+
+// HSet sets the given field value pairs, returns the number of new fields
+func (s *Store) HSet(key string, pairs ...string) (int, error) {
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return 0, errors.New("ERR wrong number of arguments for 'hset' command")
+	}
+
+	existHash, err := s.getHash(key, true)
+
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+
+	for i := 0; i < len(pairs); i += 2 {
+		if existHash.Set(pairs[i], pairs[i+1]) {
+			added++
+		}
+	}
+
+	s.setWithLock(key, existHash)
+	return added, nil
+}
+
+// HSetNX sets the field only when it does not exist yet
+func (s *Store) HSetNX(key string, field string, value string) (bool, error) {
+	existHash, err := s.getHash(key, true)
+
+	if err != nil {
+		return false, err
+	}
+
+	if _, exists := existHash.Get(field); exists {
+		return false, nil
+	}
+
+	existHash.Set(field, value)
+	s.setWithLock(key, existHash)
+	return true, nil
+}
+
+// HGet returns the value of the field, nil when the field or the key does not exist
+func (s *Store) HGet(key string, field string) (interface{}, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return nil, err
+	}
+
+	value, found := existHash.Get(field)
+
+	if !found {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+func (s *Store) HMGet(key string, fields ...string) ([]interface{}, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(fields))
+
+	if existHash == nil {
+		return values, nil
+	}
+
+	for i, field := range fields {
+		if value, found := existHash.Get(field); found {
+			values[i] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (s *Store) HGetAll(key string) (map[string]string, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return map[string]string{}, err
+	}
+
+	return existHash.Entries(), nil
+}
+
+// HDel removes the fields, deleting the key once the hash is empty.
+// Returns the number of removed fields.
+func (s *Store) HDel(key string, fields ...string) (int, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for _, field := range fields {
+		if existHash.Delete(field) {
+			removed++
+		}
+	}
+
+	if existHash.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if removed > 0 {
+		s.touch(key)
+	}
+
+	return removed, nil
+}
+
+func (s *Store) HExists(key string, field string) (bool, error) {
+	value, err := s.HGet(key, field)
+	return value != nil, err
+}
+
+func (s *Store) HLen(key string) (int, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return 0, err
+	}
+
+	return existHash.Len(), nil
+}
+
+func (s *Store) HStrLen(key string, field string) (int, error) {
+	value, err := s.HGet(key, field)
+
+	if err != nil || value == nil {
+		return 0, err
+	}
+
+	return len(value.(string)), nil
+}
+
+// HIncrBy increments the integer value of the field, a missing field counts as zero
+func (s *Store) HIncrBy(key string, field string, increment int64) (int64, error) {
+	existHash, err := s.getHash(key, true)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+
+	if value, found := existHash.Get(field); found {
+		current, err = strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+			return 0, errors.New("ERR hash value is not an integer")
+		}
+	}
+
+	newValue, err := addInt64(current, increment)
+
+	if err != nil {
+		return 0, err
+	}
+
+	existHash.Set(field, strconv.FormatInt(newValue, 10))
+	s.setWithLock(key, existHash)
+	return newValue, nil
+}
+
+// HIncrByFloat increments the float value of the field, a missing field counts as zero
+func (s *Store) HIncrByFloat(key string, field string, increment string) (string, error) {
+	if _, valid := parseFloat(increment); !valid {
+		return "", ErrNotFloat
+	}
+
+	existHash, err := s.getHash(key, true)
+
+	if err != nil {
+		return "", err
+	}
+
+	current, found := existHash.Get(field)
+
+	if !found {
+		current = "0"
+	} else if _, valid := parseFloat(current); !valid {
+		return "", errors.New("ERR hash value is not a float")
+	}
+
+	formatted, err := addFloats(current, increment)
+
+	if err != nil {
+		return "", err
+	}
+
+	existHash.Set(field, formatted)
+	s.setWithLock(key, existHash)
+	return formatted, nil
+}
+
+// HRandField returns random fields along with their values. A positive count
+// returns distinct fields, a negative one allows the same field multiple times.
+func (s *Store) HRandField(key string, count int) ([][2]string, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return [][2]string{}, err
+	}
+
+	entries := existHash.Entries()
+	fields := existHash.Fields()
+	result := [][2]string{}
+
+	if count < 0 {
+		for i := 0; i < -count; i++ {
+			field := fields[rand.Intn(len(fields))]
+			result = append(result, [2]string{field, entries[field]})
+		}
+
+		return result, nil
+	}
+
+	rand.Shuffle(len(fields), func(i, j int) {
+		fields[i], fields[j] = fields[j], fields[i]
+	})
+
+	for _, field := range fields[:min(count, len(fields))] {
+		result = append(result, [2]string{field, entries[field]})
+	}
+
+	return result, nil
+}
+
+// HScan iterates the fields of the hash, see scan.Scan for the cursor guarantees
+func (s *Store) HScan(key string, cursor uint64, pattern string, count int) ([][2]string, uint64, error) {
+	existHash, err := s.getHash(key, false)
+
+	if err != nil || existHash == nil {
+		return [][2]string{}, 0, err
+	}
+
+	entries, nextCursor := existHash.Scan(cursor, count)
+	result := [][2]string{}
+
+	for _, entry := range entries {
+		if pattern == "" || scan.Match(pattern, entry[0]) {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nextCursor, nil
+}
@@ -0,0 +1,179 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHSetAndHDel(t *testing.T) {
+	store := NewStore()
+
+	added, _ := store.HSet("hash", "a", "1", "b", "2")
+	assert.Equal(t, 2, added)
+
+	added, _ = store.HSet("hash", "a", "10", "c", "3")
+	assert.Equal(t, 1, added)
+
+	values, _ := store.HMGet("hash", "a", "missing", "c")
+	assert.Equal(t, []interface{}{"10", nil, "3"}, values)
+
+	length, _ := store.HStrLen("hash", "a")
+	assert.Equal(t, 2, length)
+
+	// the hash is deleted with its last field
+	deleted, _ := store.HDel("hash", "a", "b", "missing")
+	assert.Equal(t, 2, deleted)
+
+	deleted, _ = store.HDel("hash", "c")
+	assert.Equal(t, 1, deleted)
+	assert.False(t, store.Exists("hash"))
+
+	store.Set("name", "value", "", 0)
+	_, err := store.HSet("name", "a", "1")
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestHSetNX(t *testing.T) {
+	store := NewStore()
+
+	set, _ := store.HSetNX("hash", "a", "1")
+	assert.True(t, set)
+
+	set, _ = store.HSetNX("hash", "a", "2")
+	assert.False(t, set)
+
+	value, _ := store.HGet("hash", "a")
+	assert.Equal(t, "1", value)
+}
+
+func TestHIncrBy(t *testing.T) {
+	store := NewStore()
+
+	value, _ := store.HIncrBy("hash", "n", 5)
+	assert.Equal(t, int64(5), value)
+
+	value, _ = store.HIncrBy("hash", "n", -7)
+	assert.Equal(t, int64(-2), value)
+
+	store.HSet("hash", "max", strconv.FormatInt(math.MaxInt64, 10))
+	_, err := store.HIncrBy("hash", "max", 1)
+	assert.Equal(t, ErrOverflow, err)
+
+	store.HSet("hash", "min", strconv.FormatInt(math.MinInt64, 10))
+	_, err = store.HIncrBy("hash", "min", -1)
+	assert.Equal(t, ErrOverflow, err)
+
+	// a failed increment leaves the field as it was
+	current, _ := store.HGet("hash", "max")
+	assert.Equal(t, strconv.FormatInt(math.MaxInt64, 10), current)
+
+	store.HSet("hash", "text", "abc")
+	_, err = store.HIncrBy("hash", "text", 1)
+	assert.EqualError(t, err, "ERR hash value is not an integer")
+}
+
+func TestHIncrByFloat(t *testing.T) {
+	store := NewStore()
+	store.HSet("hash", "f", "10.50")
+
+	value, _ := store.HIncrByFloat("hash", "f", "0.1")
+	assert.Equal(t, "10.6", value)
+
+	value, _ = store.HIncrByFloat("hash", "f", "-5")
+	assert.Equal(t, "5.6", value)
+
+	value, _ = store.HIncrByFloat("hash", "sum", "0.1")
+	assert.Equal(t, "0.1", value)
+
+	value, _ = store.HIncrByFloat("hash", "sum", "0.2")
+	assert.Equal(t, "0.3", value)
+
+	_, err := store.HIncrByFloat("hash", "f", "inf")
+	assert.Equal(t, ErrNaNOrInf, err)
+
+	_, err = store.HIncrByFloat("hash", "f", "abc")
+	assert.Equal(t, ErrNotFloat, err)
+
+	store.HSet("hash", "text", "abc")
+	_, err = store.HIncrByFloat("hash", "text", "1")
+	assert.EqualError(t, err, "ERR hash value is not a float")
+
+	value, _ = store.HIncrByFloat("hash", "hex", "0x1p4")
+	assert.Equal(t, "16", value)
+
+	current, _ := store.HGet("hash", "f")
+	assert.Equal(t, "5.6", current)
+}
+
+func TestHRandField(t *testing.T) {
+	store := NewStore()
+	store.HSet("hash", "a", "1", "b", "2", "c", "3")
+
+	entries, _ := store.HRandField("hash", 2)
+	assert.Len(t, entries, 2)
+	assert.NotEqual(t, entries[0][0], entries[1][0])
+
+	// a positive count never returns a field twice
+	entries, _ = store.HRandField("hash", 10)
+	assert.Len(t, entries, 3)
+
+	fields := map[string]string{}
+
+	for _, entry := range entries {
+		fields[entry[0]] = entry[1]
+	}
+
+	assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": "3"}, fields)
+
+	// a negative count returns exactly that many fields, repeating them
+	entries, _ = store.HRandField("hash", -10)
+	assert.Len(t, entries, 10)
+
+	for _, entry := range entries {
+		assert.Equal(t, fields[entry[0]], entry[1])
+	}
+
+	entries, _ = store.HRandField("hash", 0)
+	assert.Empty(t, entries)
+
+	entries, _ = store.HRandField("missing", -5)
+	assert.Empty(t, entries)
+}
+
+func TestHScan(t *testing.T) {
+	store := NewStore()
+
+	for i := 0; i < 100; i++ {
+		store.HSet("hash", fmt.Sprintf("field:%d", i), strconv.Itoa(i))
+	}
+
+	seen := map[string]string{}
+	cursor := uint64(0)
+
+	for {
+		entries, next, err := store.HScan("hash", cursor, "", 10)
+		assert.NoError(t, err)
+
+		for _, entry := range entries {
+			seen[entry[0]] = entry[1]
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	assert.Len(t, seen, 100)
+	assert.Equal(t, "42", seen["field:42"])
+
+	entries, _, _ := store.HScan("hash", 0, "field:4?", 1000)
+	assert.Len(t, entries, 10)
+
+	entries, cursor, _ = store.HScan("missing", 0, "", 10)
+	assert.Empty(t, entries)
+	assert.Equal(t, uint64(0), cursor)
+}
@@ -0,0 +1,441 @@
+package hll
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// The HyperLogLogs use the string layout of redis: a 16 bytes header holding
+// the magic "HYLL", the encoding and a cached cardinality, followed by the
+// registers. Dense HyperLogLogs pack 16384 registers of 6 bits, sparse ones
+// run length encode them and are used while most registers are zero.
+const (
+	// bits of the hash used to select the register
+	P = 14
+	// number of registers
+	REGISTERS = 1 << P
+	// bits of the hash counted by the registers
+	Q = 64 - P
+
+	REGISTER_BITS = 6
+	REGISTER_MAX  = 1<<REGISTER_BITS - 1
+
+	HEADER_SIZE = 16
+	DENSE_SIZE  = HEADER_SIZE + (REGISTERS*REGISTER_BITS+7)/8
+
+	// sparse HyperLogLogs larger than this are converted to dense ones
+	SPARSE_MAX_BYTES = 3000
+)
+
+const (
+	ENCODING_DENSE  byte = 0
+	ENCODING_SPARSE byte = 1
+)
+
+// sparse opcodes
+const (
+	// 00xxxxxx, a run of 1 to 64 zero registers
+	opZero byte = 0x00
+	// 01xxxxxx yyyyyyyy, a run of 1 to 16384 zero registers
+	opXZero byte = 0x40
+	// 1vvvvvxx, a run of 1 to 4 registers holding 1 to 32
+	opVal byte = 0x80
+
+	zeroMaxLen  = 64
+	xzeroMaxLen = 16384
+	valMaxLen   = 4
+	valMax      = 32
+)
+
+const (
+	magic = "HYLL"
+	seed  = 0xadc83b19
+	// the cardinality cache is invalid when the highest bit of its last byte is set
+	cacheInvalid = 1 << 7
+	alphaInf     = 0.721347520444481703680
+)
+
+var (
+	ErrInvalid   = errors.New("WRONGTYPE Key is not a valid HyperLogLog string value.")
+	ErrCorrupted = errors.New("INVALIDOBJ Corrupted HLL object detected")
+)
+
+// New returns an empty sparse HyperLogLog
+func New() []byte {
+	return Encode(make([]uint8, REGISTERS), false)
+}
+
+// Add adds the elements to the HyperLogLog, returns it along with whether a
+// register changed. Dense HyperLogLogs are modified in place, sparse ones are
+// replaced and may turn dense.
+func Add(buffer []byte, elements ...string) ([]byte, bool, error) {
+	if err := Validate(buffer); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+
+	if buffer[4] == ENCODING_DENSE {
+		for _, element := range elements {
+			index, count := position(element)
+
+			if count > denseRegister(buffer, index) {
+				setDenseRegister(buffer, index, count)
+				changed = true
+			}
+		}
+	} else {
+		registers, err := Registers(buffer)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, element := range elements {
+			index, count := position(element)
+
+			if count > registers[index] {
+				registers[index] = count
+				changed = true
+			}
+		}
+
+		if changed {
+			buffer = Encode(registers, false)
+		}
+	}
+
+	if changed {
+		invalidateCache(buffer)
+	}
+
+	return buffer, changed, nil
+}
+
+// IsDense reports whether a valid HyperLogLog is dense
+func IsDense(buffer []byte) bool {
+	return buffer[4] == ENCODING_DENSE
+}
+
+// Registers decodes the registers of the HyperLogLog
+func Registers(buffer []byte) ([]uint8, error) {
+	if err := Validate(buffer); err != nil {
+		return nil, err
+	}
+
+	registers := make([]uint8, REGISTERS)
+
+	if buffer[4] == ENCODING_DENSE {
+		for index := range registers {
+			registers[index] = denseRegister(buffer, index)
+		}
+
+		return registers, nil
+	}
+
+	index := 0
+
+	for i := HEADER_SIZE; i < len(buffer); i++ {
+		op := buffer[i]
+		length := 0
+		var value uint8
+
+		switch {
+		case op&0xc0 == opZero:
+			length = int(op&0x3f) + 1
+		case op&0xc0 == opXZero:
+			if i+1 >= len(buffer) {
+				return nil, ErrCorrupted
+			}
+
+			length = (int(op&0x3f)<<8 | int(buffer[i+1])) + 1
+			i++
+		default:
+			length = int(op&0x03) + 1
+			value = (op>>2)&0x1f + 1
+		}
+
+		if index+length > REGISTERS {
+			return nil, ErrCorrupted
+		}
+
+		for end := index + length; index < end; index++ {
+			registers[index] = value
+		}
+	}
+
+	if index != REGISTERS {
+		return nil, ErrCorrupted
+	}
+
+	return registers, nil
+}
+
+// Encode builds a HyperLogLog holding registers, sparse unless dense is set or
+// the registers do not fit the sparse encoding
+func Encode(registers []uint8, dense bool) []byte {
+	if !dense {
+		if buffer, fits := encodeSparse(registers); fits {
+			return buffer
+		}
+	}
+
+	buffer := newHeader(ENCODING_DENSE, DENSE_SIZE)
+
+	for index, value := range registers {
+		setDenseRegister(buffer, index, value)
+	}
+
+	return buffer
+}
+
+// Merge sets each register of registers to the highest of its value and the
+// value of the register of the HyperLogLog
+func Merge(registers []uint8, buffer []byte) error {
+	other, err := Registers(buffer)
+
+	if err != nil {
+		return err
+	}
+
+	for index, value := range other {
+		registers[index] = max(registers[index], value)
+	}
+
+	return nil
+}
+
+// CachedCount returns the cardinality cached in the header, false when it is
+// not valid anymore
+func CachedCount(buffer []byte) (int64, bool) {
+	if buffer[15]&cacheInvalid != 0 {
+		return 0, false
+	}
+
+	return int64(binary.LittleEndian.Uint64(buffer[8:HEADER_SIZE])), true
+}
+
+// SetCachedCount caches the cardinality in the header
+func SetCachedCount(buffer []byte, count int64) {
+	binary.LittleEndian.PutUint64(buffer[8:HEADER_SIZE], uint64(count))
+}
+
+// Count estimates the cardinality of the registers with the estimator of
+// Otmar Ertl, which needs no bias correction for small or large cardinalities
+func Count(registers []uint8) int64 {
+	// registers past Q+1 can only be found in corrupted HyperLogLogs
+	histogram := make([]int, REGISTER_MAX+1)
+
+	for _, value := range registers {
+		histogram[value]++
+	}
+
+	m := float64(REGISTERS)
+	z := m * tau((m-float64(histogram[Q+1]))/m)
+
+	for j := Q; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+
+	z += m * sigma(float64(histogram[0])/m)
+
+	return int64(math.Round(alphaInf * m * m / z))
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+
+	y, z := 1.0, x
+
+	for {
+		x *= x
+		previous := z
+		z += x * y
+		y += y
+
+		if previous == z {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+
+	y, z := 1.0, 1-x
+
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+
+		if previous == z {
+			return z / 3
+		}
+	}
+}
+
+// position returns the register of the element and the length of the run of
+// zeros ending its hash, plus one
+func position(element string) (int, uint8) {
+	hash := murmurHash64A([]byte(element), seed)
+	index := int(hash & (REGISTERS - 1))
+
+	// setting the bit past the Q counted bits bounds the run
+	hash >>= P
+	hash |= 1 << Q
+
+	count := uint8(1)
+
+	for bit := uint64(1); hash&bit == 0; bit <<= 1 {
+		count++
+	}
+
+	return index, count
+}
+
+// Validate checks the header of the HyperLogLog without decoding its registers
+func Validate(buffer []byte) error {
+	if len(buffer) < HEADER_SIZE || string(buffer[:4]) != magic {
+		return ErrInvalid
+	}
+
+	switch buffer[4] {
+	case ENCODING_DENSE:
+		if len(buffer) != DENSE_SIZE {
+			return ErrInvalid
+		}
+	case ENCODING_SPARSE:
+	default:
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+// newHeader returns a HyperLogLog of size bytes with an empty cache
+func newHeader(encoding byte, size int) []byte {
+	buffer := make([]byte, size)
+	copy(buffer, magic)
+	buffer[4] = encoding
+	invalidateCache(buffer)
+
+	return buffer
+}
+
+func invalidateCache(buffer []byte) {
+	buffer[15] |= cacheInvalid
+}
+
+// encodeSparse run length encodes the registers, false when a register is too
+// large for the sparse encoding or the result is too large
+func encodeSparse(registers []uint8) ([]byte, bool) {
+	buffer := newHeader(ENCODING_SPARSE, HEADER_SIZE)
+
+	for index := 0; index < len(registers); {
+		value := registers[index]
+		length := 1
+
+		for index+length < len(registers) && registers[index+length] == value {
+			length++
+		}
+
+		index += length
+
+		if value > valMax {
+			return nil, false
+		}
+
+		for length > 0 {
+			switch {
+			case value != 0:
+				run := min(length, valMaxLen)
+				buffer = append(buffer, opVal|(value-1)<<2|byte(run-1))
+				length -= run
+			case length > zeroMaxLen:
+				run := min(length, xzeroMaxLen)
+				buffer = append(buffer, opXZero|byte((run-1)>>8), byte(run-1))
+				length -= run
+			default:
+				buffer = append(buffer, opZero|byte(length-1))
+				length = 0
+			}
+		}
+
+		if len(buffer) > SPARSE_MAX_BYTES {
+			return nil, false
+		}
+	}
+
+	return buffer, true
+}
+
+// denseRegister returns a register of a dense HyperLogLog, registers are
+// stored from the least significant bits of their first byte
+func denseRegister(buffer []byte, index int) uint8 {
+	registers := buffer[HEADER_SIZE:]
+	offset := index * REGISTER_BITS
+	first, shift := offset/8, offset%8
+
+	value := uint(registers[first]) >> shift
+
+	if first+1 < len(registers) {
+		value |= uint(registers[first+1]) << (8 - shift)
+	}
+
+	return uint8(value & REGISTER_MAX)
+}
+
+func setDenseRegister(buffer []byte, index int, value uint8) {
+	registers := buffer[HEADER_SIZE:]
+	offset := index * REGISTER_BITS
+	first, shift := offset/8, offset%8
+
+	registers[first] &^= REGISTER_MAX << shift
+	registers[first] |= value << shift
+
+	if first+1 < len(registers) {
+		registers[first+1] &^= REGISTER_MAX >> (8 - shift)
+		registers[first+1] |= value >> (8 - shift)
+	}
+}
+
+// murmurHash64A is the 64 bits MurmurHash2 of Austin Appleby, reading the key
+// as little endian words
+func murmurHash64A(key []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+
+	hash := seed ^ uint64(len(key))*m
+
+	for ; len(key) >= 8; key = key[8:] {
+		k := binary.LittleEndian.Uint64(key)
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		hash ^= k
+		hash *= m
+	}
+
+	if len(key) > 0 {
+		for i := len(key) - 1; i >= 0; i-- {
+			hash ^= uint64(key[i]) << (8 * i)
+		}
+
+		hash *= m
+	}
+
+	hash ^= hash >> r
+	hash *= m
+	hash ^= hash >> r
+
+	return hash
+}
@@ -0,0 +1,81 @@
+package hll
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparse(t *testing.T) {
+	buffer := New()
+	assert.Equal(t, []byte{0x7f, 0xff}, buffer[HEADER_SIZE:])
+
+	buffer, changed, _ := Add(buffer, "a", "b", "c", "d", "e", "f", "g")
+	assert.True(t, changed)
+	assert.False(t, IsDense(buffer))
+
+	_, changed, _ = Add(buffer, "a")
+	assert.False(t, changed)
+
+	registers, _ := Registers(buffer)
+	assert.Equal(t, int64(7), Count(registers))
+	assert.Equal(t, registers, mustRegisters(t, Encode(registers, true)))
+}
+
+func TestDense(t *testing.T) {
+	buffer := New()
+
+	for i := 0; i < 100000; i++ {
+		buffer, _, _ = Add(buffer, strconv.Itoa(i))
+	}
+
+	assert.True(t, IsDense(buffer))
+	assert.Len(t, buffer, DENSE_SIZE)
+
+	count := Count(mustRegisters(t, buffer))
+	assert.InDelta(t, 100000, count, 100000*0.02)
+
+	// merging a disjoint HyperLogLog doubles the estimate
+	registers := mustRegisters(t, buffer)
+	other := New()
+
+	for i := 100000; i < 200000; i++ {
+		other, _, _ = Add(other, strconv.Itoa(i))
+	}
+
+	Merge(registers, other)
+	assert.InDelta(t, 200000, Count(registers), 200000*0.02)
+}
+
+func TestCache(t *testing.T) {
+	buffer := New()
+	_, valid := CachedCount(buffer)
+	assert.False(t, valid)
+
+	SetCachedCount(buffer, 42)
+	count, valid := CachedCount(buffer)
+	assert.True(t, valid)
+	assert.Equal(t, int64(42), count)
+
+	buffer, _, _ = Add(buffer, "a")
+	_, valid = CachedCount(buffer)
+	assert.False(t, valid)
+}
+
+func TestInvalid(t *testing.T) {
+	_, _, err := Add([]byte("not a hyperloglog"), "a")
+	assert.Equal(t, ErrInvalid, err)
+
+	// a run which does not cover every register
+	buffer := New()
+	buffer[HEADER_SIZE+1] = 0x00
+	_, err = Registers(buffer)
+	assert.Equal(t, ErrCorrupted, err)
+}
+
+func mustRegisters(t *testing.T, buffer []byte) []uint8 {
+	registers, err := Registers(buffer)
+	assert.NoError(t, err)
+	return registers
+}
@@ -0,0 +1,129 @@
+package data
+
+import "github.com/iamvineettiwari/go-redis-server-lite/data/hll"
+
+// PFAdd adds the elements to the HyperLogLog of the key, creating it when it
+// does not exist. Returns whether its estimate may have changed.
+func (s *Store) PFAdd(key string, elements ...string) (bool, error) {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	buffer, found, err := s.bytesWithoutLock(key)
+
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		buffer = hll.New()
+	}
+
+	buffer, changed, err := hll.Add(buffer, elements...)
+
+	if err != nil {
+		return false, err
+	}
+
+	if changed || !found {
+		s.putWithoutLock(key, buffer)
+		s.touch(key)
+	}
+
+	s.recordAccess(key)
+
+	return changed || !found, nil
+}
+
+// PFCount estimates the number of distinct elements added to the
+// HyperLogLogs of the keys, missing keys count as empty ones. The estimate of
+// a single key is cached in its header until the next addition.
+func (s *Store) PFCount(keys ...string) (int64, error) {
+	if len(keys) == 1 {
+		return s.pfCount(keys[0])
+	}
+
+	registers, _, err := s.pfRegisters(keys...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return hll.Count(registers), nil
+}
+
+// PFMerge stores the union of the HyperLogLogs of the keys and of the
+// destination at destination, keeping its timeout. The result is dense when
+// one of them is.
+func (s *Store) PFMerge(destination string, keys ...string) error {
+	registers, dense, err := s.pfRegisters(append([]string{destination}, keys...)...)
+
+	if err != nil {
+		return err
+	}
+
+	return s.updateBytes(destination, 0, func(buffer []byte) ([]byte, error) {
+		return hll.Encode(registers, dense), nil
+	})
+}
+
+func (s *Store) pfCount(key string) (int64, error) {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	buffer, found, err := s.bytesWithoutLock(key)
+
+	if err != nil || !found {
+		return 0, err
+	}
+
+	if err := hll.Validate(buffer); err != nil {
+		return 0, err
+	}
+
+	s.recordAccess(key)
+
+	if count, cached := hll.CachedCount(buffer); cached {
+		return count, nil
+	}
+
+	// registers are only decoded when the cache is not valid anymore
+	registers, err := hll.Registers(buffer)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// the cache does not change the elements, watchers are not told about it
+	count := hll.Count(registers)
+	hll.SetCachedCount(buffer, count)
+	s.data[key] = buffer
+
+	return count, nil
+}
+
+// pfRegisters returns the union of the registers of the HyperLogLogs of the
+// keys, along with whether one of them is dense
+func (s *Store) pfRegisters(keys ...string) ([]uint8, bool, error) {
+	registers := make([]uint8, hll.REGISTERS)
+	dense := false
+
+	for _, key := range keys {
+		buffer, found, err := s.getBytes(key)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !found {
+			continue
+		}
+
+		if err := hll.Merge(registers, buffer); err != nil {
+			return nil, false, err
+		}
+
+		dense = dense || hll.IsDense(buffer)
+	}
+
+	return registers, dense, nil
+}
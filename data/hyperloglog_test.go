@@ -0,0 +1,84 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hll"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPFAddAndCount(t *testing.T) {
+	store := NewStore()
+
+	changed, _ := store.PFAdd("visitors")
+	assert.True(t, changed)
+
+	changed, _ = store.PFAdd("visitors", "a", "b", "c")
+	assert.True(t, changed)
+
+	watch := NewWatch()
+	store.Watch(watch, "visitors")
+
+	changed, _ = store.PFAdd("visitors", "a")
+	assert.False(t, changed)
+
+	count, _ := store.PFCount("visitors")
+	assert.Equal(t, int64(3), count)
+
+	// caching the estimate does not modify the key
+	assert.False(t, watch.IsDirty())
+	value, _, _ := store.Get("visitors")
+	cached, valid := hll.CachedCount([]byte(value.(string)))
+	assert.True(t, valid)
+	assert.Equal(t, int64(3), cached)
+
+	store.PFAdd("other", "c", "d")
+	count, _ = store.PFCount("visitors", "other", "missing")
+	assert.Equal(t, int64(4), count)
+
+	store.Set("string", "value", "", 0)
+	_, err := store.PFAdd("string", "a")
+	assert.Equal(t, hll.ErrInvalid, err)
+
+	store.Rpush("list", "x")
+	_, err = store.PFCount("list")
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestPFCountUsesCache(t *testing.T) {
+	store := NewStore()
+	store.PFAdd("visitors", "a", "b", "c")
+	store.PFCount("visitors")
+
+	// a cached count is returned without decoding the registers, which a
+	// corrupted run would make fail
+	buffer := store.data["visitors"].([]byte)
+	buffer[hll.HEADER_SIZE] = 0x00
+	count, err := store.PFCount("visitors")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// the header is still checked
+	store.Set("string", "value", "", 0)
+	_, err = store.PFCount("string")
+	assert.Equal(t, hll.ErrInvalid, err)
+}
+
+func TestPFMerge(t *testing.T) {
+	store := NewStore()
+	store.PFAdd("first", "a", "b")
+	store.PFAdd("second", "b", "c")
+	store.PFAdd("destination", "d")
+	store.Expire("destination", time.Now().Add(time.Minute).UnixMilli(), "")
+
+	assert.NoError(t, store.PFMerge("destination", "first", "second"))
+
+	count, _ := store.PFCount("destination")
+	assert.Equal(t, int64(4), count)
+	assert.Greater(t, store.TTL("destination"), int64(0))
+
+	assert.NoError(t, store.PFMerge("empty"))
+	count, _ = store.PFCount("empty")
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,192 @@
+package data
+
+import (
+	"strings"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+)
+
+// type names, as replied by TYPE and filtered by SCAN
+const (
+	TYPE_NONE   string = "none"
+	TYPE_STRING string = "string"
+	TYPE_LIST   string = "list"
+	TYPE_SET    string = "set"
+	TYPE_ZSET   string = "zset"
+	TYPE_HASH   string = "hash"
+	TYPE_STREAM string = "stream"
+)
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string, []byte:
+		return TYPE_STRING
+	case *list.List:
+		return TYPE_LIST
+	case *set.Set:
+		return TYPE_SET
+	case *zset.SortedSet:
+		return TYPE_ZSET
+	case *hash.Hash:
+		return TYPE_HASH
+	case *stream.Stream:
+		return TYPE_STREAM
+	}
+
+	return TYPE_NONE
+}
+
+// liveKeys returns the keys which did not expire
+func (s *Store) liveKeys() []string {
+	s.wl.RLock()
+	defer s.wl.RUnlock()
+
+	now := time.Now().UnixMilli()
+	keys := make([]string, 0, len(s.data))
+
+	for key := range s.data {
+		if !s.isExpired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Keys returns every key matching pattern
+func (s *Store) Keys(pattern string) []string {
+	keys := []string{}
+
+	for _, key := range s.liveKeys() {
+		if scan.Match(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Scan iterates the keys, see scan.Scan for the cursor guarantees. Like in
+// redis, the pattern and the type filter the batch once it is picked, so a
+// batch may hold fewer keys than count, or none, before the iteration ends.
+func (s *Store) Scan(cursor uint64, pattern string, count int, keyType string) ([]string, uint64) {
+	s.wl.RLock()
+	keys, nextCursor := s.keyIndex.Scan(cursor, count)
+	now := time.Now().UnixMilli()
+	live := keys[:0]
+
+	for _, key := range keys {
+		if !s.isExpired(key, now) {
+			live = append(live, key)
+		}
+	}
+
+	s.wl.RUnlock()
+
+	result := []string{}
+
+	for _, key := range live {
+		if pattern != "" && !scan.Match(pattern, key) {
+			continue
+		}
+
+		if keyType != "" {
+			value, found := s.setLockAndGet(key)
+
+			if !found || !strings.EqualFold(typeName(value), keyType) {
+				continue
+			}
+		}
+
+		result = append(result, key)
+	}
+
+	return result, nextCursor
+}
+
+// RandomKey returns a random key, false when there is none
+func (s *Store) RandomKey() (string, bool) {
+	s.wl.RLock()
+	defer s.wl.RUnlock()
+
+	now := time.Now().UnixMilli()
+
+	// map iteration starts at a random position
+	for key := range s.data {
+		if !s.isExpired(key, now) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// Type returns the type name of the value of the key, TYPE_NONE when it does not exist
+func (s *Store) Type(key string) string {
+	value, found := s.setLockAndGet(key)
+
+	if !found {
+		return TYPE_NONE
+	}
+
+	return typeName(value)
+}
+
+// Rename renames source to destination, keeping its timeout. When replace is
+// not set nothing is done if destination exists. Returns whether it was renamed.
+func (s *Store) Rename(source string, destination string, replace bool) (bool, error) {
+	value, found := s.setLockAndGet(source)
+
+	if !found {
+		return false, ErrNoSuchKey
+	}
+
+	if source == destination {
+		return replace, nil
+	}
+
+	if !replace && s.Exists(destination) {
+		return false, nil
+	}
+
+	s.wl.Lock()
+	expireAt := s.expires[source]
+	s.removeWithoutLock(source)
+	s.wl.Unlock()
+
+	s.setWithExpiry(destination, value, expireAt)
+	s.signalReady(destination)
+
+	return true, nil
+}
+
+// Copy copies the value of source, along with its timeout, to destination in
+// the target store. When replace is not set nothing is done if destination
+// exists. Returns whether it was copied.
+func (s *Store) Copy(source string, target *Store, destination string, replace bool) bool {
+	value, found := s.setLockAndGet(source)
+
+	if !found {
+		return false
+	}
+
+	if !replace && target.Exists(destination) {
+		return false
+	}
+
+	s.wl.RLock()
+	value = cloneValue(value)
+	expireAt := s.expires[source]
+	s.wl.RUnlock()
+
+	target.setWithExpiry(destination, value, expireAt)
+	target.signalReady(destination)
+
+	return true
+}
@@ -0,0 +1,200 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	store := NewStore()
+	store.Set("user:1", "a", "", 0)
+	store.Set("user:2", "b", "", 0)
+	store.Set("session:1", "c", "", 0)
+
+	keys := store.Keys("user:*")
+	sort.Strings(keys)
+
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+	assert.Empty(t, store.Keys("missing*"))
+}
+
+func TestScanWhileGrowing(t *testing.T) {
+	store := NewStore()
+
+	for i := 0; i < 100; i++ {
+		store.Set(fmt.Sprintf("key:%d", i), "value", "", 0)
+	}
+
+	store.Rpush("list", "x")
+
+	seen := map[string]int{}
+	cursor := uint64(0)
+
+	for {
+		keys, next := store.Scan(cursor, "key:*", 7, "")
+
+		for _, key := range keys {
+			seen[key]++
+		}
+
+		// keys added meanwhile must not make the iteration miss the others
+		store.Set(fmt.Sprintf("added:%d", len(seen)), "value", "", 0)
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	assert.Len(t, seen, 100)
+
+	for key, count := range seen {
+		assert.Equal(t, 1, count, key)
+	}
+
+	keys, _ := store.Scan(0, "", 1000, "LIST")
+	assert.Equal(t, []string{"list"}, keys)
+}
+
+func TestScanFollowsKeyspaceChanges(t *testing.T) {
+	store := NewStore()
+	store.Set("kept", "value", "", 0)
+	store.Set("deleted", "value", "", 0)
+	store.Set("expired", "value", "PX", 1)
+	store.Set("kept", "other", "", 0)
+	store.Delete("deleted")
+	time.Sleep(5 * time.Millisecond)
+
+	keys, cursor := store.Scan(0, "", 10, "")
+	assert.Equal(t, []string{"kept"}, keys)
+	assert.Equal(t, uint64(0), cursor)
+
+	other := NewStore()
+	other.Set("swapped", "value", "", 0)
+	store.Swap(other)
+
+	keys, _ = store.Scan(0, "", 10, "")
+	assert.Equal(t, []string{"swapped"}, keys)
+	keys, _ = other.Scan(0, "", 10, "")
+	assert.Equal(t, []string{"kept"}, keys)
+
+	store.Flush()
+	keys, _ = store.Scan(0, "", 10, "")
+	assert.Empty(t, keys)
+}
+
+func TestRandomKey(t *testing.T) {
+	store := NewStore()
+
+	_, found := store.RandomKey()
+	assert.False(t, found)
+
+	store.Set("key", "value", "", 0)
+	key, found := store.RandomKey()
+
+	assert.True(t, found)
+	assert.Equal(t, "key", key)
+}
+
+func TestRename(t *testing.T) {
+	store := NewStore()
+	store.Set("source", "value", "EX", 100)
+	store.Set("taken", "other", "", 0)
+	expireAt := store.ExpireTime("source")
+
+	renamed, err := store.Rename("source", "taken", false)
+	assert.False(t, renamed)
+	assert.Nil(t, err)
+
+	renamed, _ = store.Rename("source", "destination", false)
+	assert.True(t, renamed)
+	assert.False(t, store.Exists("source"))
+	assert.Equal(t, expireAt, store.ExpireTime("destination"))
+
+	_, err = store.Rename("missing", "destination", true)
+	assert.Equal(t, ErrNoSuchKey, err)
+}
+
+func TestCopy(t *testing.T) {
+	store, other := NewStore(), NewStore()
+	store.Rpush("list", "a")
+
+	assert.True(t, store.Copy("list", other, "copy", false))
+	assert.False(t, store.Copy("list", other, "copy", false))
+
+	// the copy does not share the value
+	other.Rpush("copy", "b")
+	length, _ := store.LLen("list")
+	assert.Equal(t, 1, length)
+	assert.Equal(t, TYPE_LIST, other.Type("copy"))
+}
+
+func TestObjectEncoding(t *testing.T) {
+	store := NewStore()
+	store.Set("int", "123", "", 0)
+	store.Set("padded", "0123", "", 0)
+	store.SAdd("intset", "1", "2")
+	store.SAdd("set", "a")
+
+	encodings := map[string]string{
+		"int":    "int",
+		"padded": "embstr",
+		"intset": "intset",
+		"set":    "listpack",
+	}
+
+	for key, expected := range encodings {
+		encoding, found := store.ObjectEncoding(key)
+		assert.True(t, found)
+		assert.Equal(t, expected, encoding, key)
+	}
+
+	freq, _ := store.ObjectFreq("int")
+	assert.Equal(t, lfuInitValue, freq)
+
+	refCount, found := store.ObjectRefCount("int")
+	assert.True(t, found)
+	assert.Equal(t, 1, refCount)
+
+	_, found = store.ObjectRefCount("missing")
+	assert.False(t, found)
+}
+
+func TestObjectEncodingLimits(t *testing.T) {
+	store := NewStore()
+
+	for i := 0; i < 4097; i++ {
+		store.Rpush("empty items", "")
+	}
+
+	store.Rpush("small list", "a", "b")
+	store.Rpush("large values", strings.Repeat("x", 5000), strings.Repeat("x", 5000))
+
+	for i := 0; i < 600; i++ {
+		store.SAdd("large intset", strconv.Itoa(i))
+		store.HSet("large hash", strconv.Itoa(i), "value")
+	}
+
+	store.SAdd("long member", strings.Repeat("x", 65))
+	store.HSet("long value", "field", strings.Repeat("x", 65))
+
+	encodings := map[string]string{
+		"empty items":  "quicklist",
+		"small list":   "listpack",
+		"large values": "quicklist",
+		"large intset": "hashtable",
+		"long member":  "hashtable",
+		"large hash":   "hashtable",
+		"long value":   "hashtable",
+	}
+
+	for key, expected := range encodings {
+		encoding, _ := store.ObjectEncoding(key)
+		assert.Equal(t, expected, encoding, key)
+	}
+}
@@ -0,0 +1,110 @@
+package data
+
+import "errors"
+
+// the table of the lengths of common subsequences is limited like in redis
+const lcsMaxTableBytes = MAX_STRING_LENGTH
+
+var ErrLCSTooLarge = errors.New("ERR Insufficient memory, transient memory for LCS exceeds proto-max-bulk-len")
+
+// LCSRange is an inclusive range of offsets
+type LCSRange struct {
+	Start int
+	End   int
+}
+
+// LCSMatch is a run of the common subsequence which is contiguous in both strings
+type LCSMatch struct {
+	First  LCSRange
+	Second LCSRange
+}
+
+func (m LCSMatch) Len() int {
+	return m.First.End - m.First.Start + 1
+}
+
+// LCS returns the longest common subsequence of the strings at first and
+// second, along with the runs it is made of, from the last one to the first
+// one. Missing keys count as empty strings.
+func (s *Store) LCS(first string, second string) (string, []LCSMatch, error) {
+	a, _, err := s.getString(first)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	b, _, err := s.getString(second)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return lcs(a, b)
+}
+
+func lcs(a string, b string) (string, []LCSMatch, error) {
+	width := len(b) + 1
+
+	if uint64(len(a)+1)*uint64(width)*4 > lcsMaxTableBytes {
+		return "", nil, ErrLCSTooLarge
+	}
+
+	// lengths[i*width+j] is the length of the longest common subsequence of
+	// the first i bytes of a and the first j bytes of b
+	lengths := make([]uint32, (len(a)+1)*width)
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lengths[i*width+j] = lengths[(i-1)*width+j-1] + 1
+			} else {
+				lengths[i*width+j] = max(lengths[(i-1)*width+j], lengths[i*width+j-1])
+			}
+		}
+	}
+
+	result := make([]byte, lengths[len(a)*width+len(b)])
+	matches := []LCSMatch{}
+
+	var current *LCSMatch
+
+	// walk back from the end, grouping the common bytes into runs
+	for i, j, k := len(a), len(b), len(result); i > 0 && j > 0; {
+		if a[i-1] == b[j-1] {
+			k--
+			result[k] = a[i-1]
+
+			if current != nil && current.First.Start == i && current.Second.Start == j {
+				current.First.Start--
+				current.Second.Start--
+			} else {
+				if current != nil {
+					matches = append(matches, *current)
+				}
+
+				current = &LCSMatch{First: LCSRange{i - 1, i - 1}, Second: LCSRange{j - 1, j - 1}}
+			}
+
+			i--
+			j--
+			continue
+		}
+
+		if current != nil {
+			matches = append(matches, *current)
+			current = nil
+		}
+
+		if lengths[(i-1)*width+j] > lengths[i*width+j-1] {
+			i--
+		} else {
+			j--
+		}
+	}
+
+	if current != nil {
+		matches = append(matches, *current)
+	}
+
+	return string(result), matches, nil
+}
@@ -97,3 +97,328 @@ func (l *List) GetValues() []resp.ArrayType {
 
 	return data
 }
+
+// Clone returns a copy of the list which shares no nodes with the original
+func (l *List) Clone() *List {
+	clone := NewList()
+
+	for _, item := range l.GetValues() {
+		clone.InsertLast(item.Value, item.Type.(string))
+	}
+
+	return clone
+}
+
+func (l *List) Len() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return int(l.totalElement)
+}
+
+// PopFirst removes and returns the first element
+func (l *List) PopFirst() (resp.ArrayType, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.head == nil {
+		return resp.ArrayType{}, false
+	}
+
+	node := l.head
+	l.unlink(node)
+
+	return node.data, true
+}
+
+// PopLast removes and returns the last element
+func (l *List) PopLast() (resp.ArrayType, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.tail == nil {
+		return resp.ArrayType{}, false
+	}
+
+	node := l.tail
+	l.unlink(node)
+
+	return node.data, true
+}
+
+// Index returns the element at index, negative indexes count from the end
+func (l *List) Index(index int) (resp.ArrayType, bool) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	node := l.nodeAt(index)
+
+	if node == nil {
+		return resp.ArrayType{}, false
+	}
+
+	return node.data, true
+}
+
+// Set replaces the element at index, returns false when index is out of range
+func (l *List) Set(index int, data any, dataType string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	node := l.nodeAt(index)
+
+	if node == nil {
+		return false
+	}
+
+	node.data = resp.ArrayType{Value: data, Type: dataType}
+	return true
+}
+
+// Insert adds data before, or after, the first element equal to pivot.
+// Returns the new length, or -1 when pivot is not found.
+func (l *List) Insert(pivot any, data any, dataType string, before bool) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	node := l.head
+
+	for node != nil && node.data.Value != pivot {
+		node = node.next
+	}
+
+	if node == nil {
+		return -1
+	}
+
+	newNode := &ListNode{
+		data: resp.ArrayType{
+			Value: data,
+			Type:  dataType,
+		},
+	}
+
+	if before {
+		newNode.prev = node.prev
+		newNode.next = node
+	} else {
+		newNode.prev = node
+		newNode.next = node.next
+	}
+
+	if newNode.prev != nil {
+		newNode.prev.next = newNode
+	} else {
+		l.head = newNode
+	}
+
+	if newNode.next != nil {
+		newNode.next.prev = newNode
+	} else {
+		l.tail = newNode
+	}
+
+	l.totalElement++
+	return int(l.totalElement)
+}
+
+// Remove removes the elements equal to data, up to count of them from the head
+// when count is positive, from the tail when it is negative, all of them when
+// it is zero. Returns the number of removed elements.
+func (l *List) Remove(data any, count int) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	fromTail := count < 0
+
+	if fromTail {
+		count = -count
+	}
+
+	node := l.head
+
+	if fromTail {
+		node = l.tail
+	}
+
+	removed := 0
+
+	for node != nil && (count == 0 || removed < count) {
+		following := node.next
+
+		if fromTail {
+			following = node.prev
+		}
+
+		if node.data.Value == data {
+			l.unlink(node)
+			removed++
+		}
+
+		node = following
+	}
+
+	return removed
+}
+
+// Range returns the elements between start and stop, both inclusive.
+// Negative indexes count from the end.
+func (l *List) Range(start int, stop int) []resp.ArrayType {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	data := []resp.ArrayType{}
+	start, stop, ok := l.normalizeRange(start, stop)
+
+	if !ok {
+		return data
+	}
+
+	node := l.nodeAt(start)
+
+	for i := start; i <= stop && node != nil; i++ {
+		data = append(data, node.data)
+		node = node.next
+	}
+
+	return data
+}
+
+// Trim keeps only the elements between start and stop, both inclusive.
+// Negative indexes count from the end.
+func (l *List) Trim(start int, stop int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	start, stop, ok := l.normalizeRange(start, stop)
+
+	if !ok {
+		l.head, l.tail, l.totalElement = nil, nil, 0
+		return
+	}
+
+	for i := 0; i < start; i++ {
+		l.unlink(l.head)
+	}
+
+	for remaining := stop - start + 1; int(l.totalElement) > remaining; {
+		l.unlink(l.tail)
+	}
+}
+
+// Positions returns the indexes of the elements equal to data. Matching starts
+// at the rank-th match, from the tail when rank is negative, returns at most
+// count indexes unless count is zero and compares at most maxLen elements
+// unless maxLen is zero.
+func (l *List) Positions(data any, rank int, count int, maxLen int) []int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	positions := []int{}
+	fromTail := rank < 0
+
+	if fromTail {
+		rank = -rank
+	}
+
+	node, index, step := l.head, 0, 1
+
+	if fromTail {
+		node, index, step = l.tail, int(l.totalElement)-1, -1
+	}
+
+	for compared := 0; node != nil && (maxLen == 0 || compared < maxLen); compared++ {
+		if node.data.Value == data {
+			if rank > 1 {
+				rank--
+			} else {
+				positions = append(positions, index)
+
+				if count != 0 && len(positions) == count {
+					break
+				}
+			}
+		}
+
+		if fromTail {
+			node = node.prev
+		} else {
+			node = node.next
+		}
+
+		index += step
+	}
+
+	return positions
+}
+
+// nodeAt returns the node at index, negative indexes count from the end,
+// nil when out of range
+func (l *List) nodeAt(index int) *ListNode {
+	length := int(l.totalElement)
+
+	if index < 0 {
+		index += length
+	}
+
+	if index < 0 || index >= length {
+		return nil
+	}
+
+	// walk from the closest end
+	if index < length/2 {
+		node := l.head
+
+		for i := 0; i < index; i++ {
+			node = node.next
+		}
+
+		return node
+	}
+
+	node := l.tail
+
+	for i := length - 1; i > index; i-- {
+		node = node.prev
+	}
+
+	return node
+}
+
+// normalizeRange converts start and stop to positive indexes within the list,
+// ok is false when the range is empty
+func (l *List) normalizeRange(start int, stop int) (int, int, bool) {
+	length := int(l.totalElement)
+
+	if start < 0 {
+		start = max(start+length, 0)
+	}
+
+	if stop < 0 {
+		stop += length
+	}
+
+	stop = min(stop, length-1)
+
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+
+	return start, stop, true
+}
+
+func (l *List) unlink(node *ListNode) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		l.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		l.tail = node.prev
+	}
+
+	node.prev, node.next = nil, nil
+	l.totalElement--
+}
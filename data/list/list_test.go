@@ -0,0 +1,96 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestList(values ...string) *List {
+	l := NewList()
+
+	for _, value := range values {
+		l.InsertLast(value, resp.BULK_STRING)
+	}
+
+	return l
+}
+
+func values(items []resp.ArrayType) []string {
+	result := []string{}
+
+	for _, item := range items {
+		result = append(result, item.Value.(string))
+	}
+
+	return result
+}
+
+func TestRange(t *testing.T) {
+	l := newTestList("a", "b", "c", "d")
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values(l.Range(0, -1)))
+	assert.Equal(t, []string{"c", "d"}, values(l.Range(-2, 10)))
+	assert.Equal(t, []string{"a"}, values(l.Range(-10, 0)))
+	assert.Equal(t, []string{}, values(l.Range(3, 1)))
+	assert.Equal(t, []string{}, values(l.Range(4, 5)))
+}
+
+func TestPopAndIndex(t *testing.T) {
+	l := newTestList("a", "b", "c")
+
+	item, _ := l.PopFirst()
+	assert.Equal(t, "a", item.Value)
+
+	item, _ = l.PopLast()
+	assert.Equal(t, "c", item.Value)
+
+	item, found := l.Index(-1)
+	assert.True(t, found)
+	assert.Equal(t, "b", item.Value)
+
+	_, found = l.Index(1)
+	assert.False(t, found)
+
+	l.PopFirst()
+	_, found = l.PopFirst()
+	assert.False(t, found)
+	assert.True(t, l.IsEmpty())
+}
+
+func TestInsertAndRemove(t *testing.T) {
+	l := newTestList("a", "b", "a", "c", "a")
+
+	assert.Equal(t, 6, l.Insert("c", "x", resp.BULK_STRING, true))
+	assert.Equal(t, 7, l.Insert("a", "y", resp.BULK_STRING, false))
+	assert.Equal(t, -1, l.Insert("z", "y", resp.BULK_STRING, false))
+	assert.Equal(t, []string{"a", "y", "b", "a", "x", "c", "a"}, values(l.GetValues()))
+
+	assert.Equal(t, 1, l.Remove("a", -1))
+	assert.Equal(t, []string{"a", "y", "b", "a", "x", "c"}, values(l.GetValues()))
+
+	assert.Equal(t, 2, l.Remove("a", 0))
+	assert.Equal(t, []string{"y", "b", "x", "c"}, values(l.GetValues()))
+}
+
+func TestTrim(t *testing.T) {
+	l := newTestList("a", "b", "c", "d", "e")
+
+	l.Trim(1, -2)
+	assert.Equal(t, []string{"b", "c", "d"}, values(l.GetValues()))
+	assert.Equal(t, 3, l.Len())
+
+	l.Trim(5, 10)
+	assert.True(t, l.IsEmpty())
+}
+
+func TestPositions(t *testing.T) {
+	l := newTestList("a", "b", "c", "a", "b", "a")
+
+	assert.Equal(t, []int{0}, l.Positions("a", 1, 1, 0))
+	assert.Equal(t, []int{3, 5}, l.Positions("a", 2, 0, 0))
+	assert.Equal(t, []int{5, 3}, l.Positions("a", -1, 2, 0))
+	assert.Equal(t, []int{0}, l.Positions("a", 1, 0, 3))
+	assert.Equal(t, []int{}, l.Positions("z", 1, 0, 0))
+}
@@ -0,0 +1,254 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+var (
+	ErrNoSuchKey     = errors.New("ERR no such key")
+	ErrIndexOutRange = errors.New("ERR index out of range")
+)
+
+// getList returns the list stored at key, creating an empty one when create
+// is set. The returned list is nil when the key does not exist and create is not set.
+func (s *Store) getList(key string, create bool) (*list.List, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		if !create {
+			return nil, nil
+		}
+
+		return list.NewList(), nil
+	}
+
+	existList, isListType := data.(*list.List)
+
+	if !isListType {
+		return nil, ErrWrongType
+	}
+
+	return s.own(key, existList).(*list.List), nil
+}
+
+// push inserts the values at the head, or the tail, of the list. When create is
+// not set nothing is done unless the list already exists. Returns the length of the list.
+func (s *Store) push(key string, head bool, create bool, values ...interface{}) (int, error) {
+	existList, err := s.getList(key, create)
+
+	if err != nil || existList == nil {
+		return 0, err
+	}
+
+	for _, value := range values {
+		if head {
+			existList.InsertFirst(value, resp.BULK_STRING)
+		} else {
+			existList.InsertLast(value, resp.BULK_STRING)
+		}
+	}
+
+	s.setWithLock(key, existList)
+	s.signalReady(key)
+
+	return existList.Len(), nil
+}
+
+// Lpushx is Lpush for lists which already exist
+func (s *Store) Lpushx(key string, val ...interface{}) (int, error) {
+	return s.push(key, true, false, val...)
+}
+
+// Rpushx is Rpush for lists which already exist
+func (s *Store) Rpushx(key string, val ...interface{}) (int, error) {
+	return s.push(key, false, false, val...)
+}
+
+// LPop removes and returns up to count elements from the head of the list,
+// nil when the key does not exist
+func (s *Store) LPop(key string, count int) ([]resp.ArrayType, error) {
+	return s.pop(key, true, count)
+}
+
+// RPop removes and returns up to count elements from the tail of the list,
+// nil when the key does not exist
+func (s *Store) RPop(key string, count int) ([]resp.ArrayType, error) {
+	return s.pop(key, false, count)
+}
+
+func (s *Store) pop(key string, head bool, count int) ([]resp.ArrayType, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return nil, err
+	}
+
+	items := []resp.ArrayType{}
+
+	for len(items) < count {
+		var item resp.ArrayType
+		var found bool
+
+		if head {
+			item, found = existList.PopFirst()
+		} else {
+			item, found = existList.PopLast()
+		}
+
+		if !found {
+			break
+		}
+
+		items = append(items, item)
+	}
+
+	if existList.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if len(items) > 0 {
+		s.touch(key)
+	}
+
+	return items, nil
+}
+
+func (s *Store) LLen(key string) (int, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return 0, err
+	}
+
+	return existList.Len(), nil
+}
+
+// LIndex returns the element at index, nil when out of range
+func (s *Store) LIndex(key string, index int) (interface{}, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return nil, err
+	}
+
+	item, found := existList.Index(index)
+
+	if !found {
+		return nil, nil
+	}
+
+	return item.Value, nil
+}
+
+func (s *Store) LSet(key string, index int, value string) error {
+	existList, err := s.getList(key, false)
+
+	if err != nil {
+		return err
+	}
+
+	if existList == nil {
+		return ErrNoSuchKey
+	}
+
+	if !existList.Set(index, value, resp.BULK_STRING) {
+		return ErrIndexOutRange
+	}
+
+	s.touch(key)
+	return nil
+}
+
+// LInsert inserts value before, or after, pivot. Returns the length of the
+// list, 0 when the key does not exist and -1 when pivot is not found.
+func (s *Store) LInsert(key string, before bool, pivot string, value string) (int, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return 0, err
+	}
+
+	length := existList.Insert(pivot, value, resp.BULK_STRING, before)
+
+	if length > 0 {
+		s.touch(key)
+	}
+
+	return length, nil
+}
+
+// LRem removes count occurrences of value, see list.Remove for the meaning of count
+func (s *Store) LRem(key string, count int, value string) (int, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return 0, err
+	}
+
+	removed := existList.Remove(value, count)
+
+	if existList.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if removed > 0 {
+		s.touch(key)
+	}
+
+	return removed, nil
+}
+
+func (s *Store) LTrim(key string, start int, stop int) error {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return err
+	}
+
+	existList.Trim(start, stop)
+
+	if existList.IsEmpty() {
+		s.deleteWithLock(key)
+	} else {
+		s.touch(key)
+	}
+
+	return nil
+}
+
+// LPos returns the indexes of value, see list.Positions for the options
+func (s *Store) LPos(key string, value string, rank int, count int, maxLen int) ([]int, error) {
+	existList, err := s.getList(key, false)
+
+	if err != nil || existList == nil {
+		return []int{}, err
+	}
+
+	return existList.Positions(value, rank, count, maxLen), nil
+}
+
+// LMove pops an element from one end of source and pushes it to one end of
+// destination, atomically. Returns the element, nil when source does not exist.
+func (s *Store) LMove(source string, destination string, fromHead bool, toHead bool) (interface{}, error) {
+	sourceList, err := s.getList(source, false)
+
+	if err != nil || sourceList == nil {
+		return nil, err
+	}
+
+	// check destination before popping so a wrong type leaves source untouched
+	if _, err := s.getList(destination, false); err != nil {
+		return nil, err
+	}
+
+	items, err := s.pop(source, fromHead, 1)
+
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	if _, err := s.push(destination, toHead, true, items[0].Value); err != nil {
+		return nil, err
+	}
+
+	return items[0].Value, nil
+}
@@ -0,0 +1,150 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// mantissa bits of the long doubles redis computes float increments with
+const LONG_DOUBLE_PRECISION = 64
+
+var (
+	ErrNotInteger = errors.New("ERR value is not an integer or out of range")
+	ErrNotFloat   = errors.New("ERR value is not a valid float")
+	ErrOverflow   = errors.New("ERR increment or decrement would overflow")
+	ErrNaNOrInf   = errors.New("ERR increment would produce NaN or Infinity")
+)
+
+// parseFloat parses a float the way redis does, rejecting NaN, surrounding
+// spaces and the underscores strconv allows between digits
+func parseFloat(value string) (float64, bool) {
+	if value == "" || strings.TrimSpace(value) != value || strings.Contains(value, "_") {
+		return 0, false
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || math.IsNaN(number) {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// parseInt64 parses an integer the way redis does, rejecting signs, spaces
+// and leading zeros
+func parseInt64(value string) (int64, bool) {
+	number, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil || strconv.FormatInt(number, 10) != value {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// addFloats adds the increment to the value the way redis does, with long
+// double precision, formatting the sum with 17 decimals without trailing
+// zeros. The operands must be valid floats.
+func addFloats(value string, increment string) (string, error) {
+	a, _ := strconv.ParseFloat(value, 64)
+	b, _ := strconv.ParseFloat(increment, 64)
+
+	// infinite operands give infinite or NaN sums, big.Float panics on the latter
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return "", ErrNaNOrInf
+	}
+
+	// base 0 accepts the hexadecimal floats strconv does, like strtold
+	x, _, errValue := big.ParseFloat(value, 0, LONG_DOUBLE_PRECISION, big.ToNearestEven)
+	y, _, errIncrement := big.ParseFloat(increment, 0, LONG_DOUBLE_PRECISION, big.ToNearestEven)
+
+	if errValue != nil || errIncrement != nil {
+		return "", ErrNotFloat
+	}
+
+	sum := new(big.Float).SetPrec(LONG_DOUBLE_PRECISION).Add(x, y)
+
+	if result, _ := sum.Float64(); math.IsInf(result, 0) {
+		return "", ErrNaNOrInf
+	}
+
+	formatted := strings.TrimRight(sum.Text('f', 17), "0")
+	formatted = strings.TrimSuffix(formatted, ".")
+
+	if formatted == "-0" {
+		return "0", nil
+	}
+
+	return formatted, nil
+}
+
+// addInt64 adds two integers, failing instead of wrapping around on overflow
+func addInt64(value int64, increment int64) (int64, error) {
+	if (increment > 0 && value > math.MaxInt64-increment) || (increment < 0 && value < math.MinInt64-increment) {
+		return 0, ErrOverflow
+	}
+
+	return value + increment, nil
+}
+
+// IncrBy adds increment to the integer stored at key, keeping its timeout. A
+// missing key counts as zero. Returns the new value.
+func (s *Store) IncrBy(key string, increment int64) (int64, error) {
+	current, found, err := s.getString(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+
+	if found {
+		var valid bool
+
+		if value, valid = parseInt64(current); !valid {
+			return 0, ErrNotInteger
+		}
+	}
+
+	newValue, err := addInt64(value, increment)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.setWithLock(key, strconv.FormatInt(newValue, 10))
+	return newValue, nil
+}
+
+// IncrByFloat adds increment to the float stored at key, keeping its timeout.
+// A missing key counts as zero. Returns the new value, formatted like redis does.
+func (s *Store) IncrByFloat(key string, increment string) (string, error) {
+	if _, valid := parseFloat(increment); !valid {
+		return "", ErrNotFloat
+	}
+
+	current, found, err := s.getString(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		current = "0"
+	} else if _, valid := parseFloat(current); !valid {
+		return "", ErrNotFloat
+	}
+
+	formatted, err := addFloats(current, increment)
+
+	if err != nil {
+		return "", err
+	}
+
+	s.setWithLock(key, formatted)
+	return formatted, nil
+}
@@ -0,0 +1,173 @@
+package data
+
+import (
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+)
+
+// Values are stored the same way whatever their size, ObjectEncoding reports
+// the encoding redis would use for them with its default configuration, so
+// tooling relying on it keeps working.
+const (
+	embstrMaxLength = 44
+	// lists are a single listpack up to 8kb
+	listListpackMaxBytes = 8192
+	// bytes a listpack entry takes besides its value, at least
+	listpackEntryOverhead = 2
+	intsetMaxEntries      = 512
+	// sets, sorted sets and hashes are listpacks up to these limits
+	listpackMaxEntries     = 128
+	listpackMaxValueLength = 64
+)
+
+// ObjectEncoding returns the encoding of the value of the key, false when it does not exist
+func (s *Store) ObjectEncoding(key string) (string, bool) {
+	value, found := s.peek(key)
+
+	if !found {
+		return "", false
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return stringEncoding(typedValue), true
+
+	case []byte:
+		// strings modified in place are never shared nor converted back
+		return "raw", true
+
+	case *list.List:
+		// every entry takes at least two bytes, long lists never fit a listpack
+		if typedValue.Len() > listListpackMaxBytes/listpackEntryOverhead {
+			return "quicklist", true
+		}
+
+		size := 0
+
+		for _, item := range typedValue.GetValues() {
+			size += len(item.Value.(string)) + listpackEntryOverhead
+		}
+
+		if size > listListpackMaxBytes {
+			return "quicklist", true
+		}
+
+		return "listpack", true
+
+	case *set.Set:
+		if typedValue.Len() > intsetMaxEntries {
+			return "hashtable", true
+		}
+
+		members := typedValue.Members()
+
+		if allIntegers(members) {
+			return "intset", true
+		}
+
+		return compactEncoding(len(members), "hashtable", func() []string { return members }), true
+
+	case *zset.SortedSet:
+		return compactEncoding(typedValue.Len(), "skiplist", typedValue.Members), true
+
+	case *hash.Hash:
+		return compactEncoding(typedValue.Len(), "hashtable", func() []string {
+			values := []string{}
+
+			for field, fieldValue := range typedValue.Entries() {
+				values = append(values, field, fieldValue)
+			}
+
+			return values
+		}), true
+
+	case *stream.Stream:
+		return "stream", true
+	}
+
+	return "", false
+}
+
+// ObjectIdleTime returns the number of seconds since the key was last accessed
+func (s *Store) ObjectIdleTime(key string) (int64, bool) {
+	if _, found := s.peek(key); !found {
+		return 0, false
+	}
+
+	access, found := s.lookupAccess(key)
+
+	if !found {
+		return 0, true
+	}
+
+	return (time.Now().UnixMilli() - access.lastAccess) / 1000, true
+}
+
+// ObjectFreq returns the logarithmic access frequency counter of the key
+func (s *Store) ObjectFreq(key string) (int, bool) {
+	if _, found := s.peek(key); !found {
+		return 0, false
+	}
+
+	access, found := s.lookupAccess(key)
+
+	if !found {
+		return 0, true
+	}
+
+	return access.decayedCounter(time.Now().UnixMilli()), true
+}
+
+// ObjectRefCount returns the number of references to the value of the key,
+// always one as values are never shared between keys
+func (s *Store) ObjectRefCount(key string) (int, bool) {
+	if _, found := s.peek(key); !found {
+		return 0, false
+	}
+
+	return 1, true
+}
+
+func stringEncoding(value string) string {
+	if _, isInteger := parseInt64(value); isInteger {
+		return "int"
+	}
+
+	if len(value) <= embstrMaxLength {
+		return "embstr"
+	}
+
+	return "raw"
+}
+
+// compactEncoding returns listpack when there are few entries and their values
+// are small enough, the large encoding otherwise. The values are only looked at
+// when there are few entries.
+func compactEncoding(entries int, largeEncoding string, values func() []string) string {
+	if entries > listpackMaxEntries {
+		return largeEncoding
+	}
+
+	for _, value := range values() {
+		if len(value) > listpackMaxValueLength {
+			return largeEncoding
+		}
+	}
+
+	return "listpack"
+}
+
+func allIntegers(values []string) bool {
+	for _, value := range values {
+		if _, isInteger := parseInt64(value); !isInteger {
+			return false
+		}
+	}
+
+	return true
+}
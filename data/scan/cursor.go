@@ -0,0 +1,60 @@
+package scan
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Items are visited in the order of a hash of their name and the cursor is the
+// hash of the next item to visit. Unlike a position, the hash of an item does
+// not change when other items are added or removed, so every item present for
+// the whole iteration is returned exactly once no matter how the collection
+// changes in between calls. Items added or removed meanwhile may or may not be
+// returned.
+
+// Scan returns up to count of the items not visited yet by the iteration at
+// cursor, along with the cursor to continue from. A zero cursor starts a new
+// iteration and is returned once the iteration is complete.
+func Scan(items []string, cursor uint64, count int) ([]string, uint64) {
+	type position struct {
+		item string
+		hash uint64
+	}
+
+	pending := []position{}
+
+	for _, item := range items {
+		if itemHash := Hash(item); itemHash >= cursor {
+			pending = append(pending, position{item: item, hash: itemHash})
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].hash == pending[j].hash {
+			return pending[i].item < pending[j].item
+		}
+
+		return pending[i].hash < pending[j].hash
+	})
+
+	count = max(count, 1)
+	result := []string{}
+
+	for i, current := range pending {
+		// items sharing a hash are returned together, as the cursor can not point between them
+		if len(result) >= count && current.hash != pending[i-1].hash {
+			return result, current.hash
+		}
+
+		result = append(result, current.item)
+	}
+
+	return result, 0
+}
+
+// Hash returns the position of an item in the iteration order, never zero
+func Hash(item string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(item))
+	return hasher.Sum64()>>1 + 1
+}
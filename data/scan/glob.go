@@ -0,0 +1,149 @@
+package scan
+
+// Match reports whether str matches the redis glob style pattern.
+//
+//   - any sequence of characters, including none
+//     ?       any single character
+//     [abc]   one of the characters, [^abc] none of them, [a-z] a range
+//     \x      the character x literally
+func Match(pattern string, str string) bool {
+	return match(pattern, str, false)
+}
+
+// MatchNoCase is Match ignoring the case of ascii letters
+func MatchNoCase(pattern string, str string) bool {
+	return match(pattern, str, true)
+}
+
+func match(pattern string, str string, noCase bool) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// collapse consecutive stars
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for i := 0; i <= len(str); i++ {
+				if match(pattern[1:], str[i:], noCase) {
+					return true
+				}
+			}
+
+			return false
+
+		case '?':
+			if len(str) == 0 {
+				return false
+			}
+
+			str = str[1:]
+			pattern = pattern[1:]
+
+		case '[':
+			if len(str) == 0 {
+				return false
+			}
+
+			matched, rest := matchClass(pattern[1:], str[0], noCase)
+
+			if !matched {
+				return false
+			}
+
+			str = str[1:]
+			pattern = rest
+
+		default:
+			if pattern[0] == '\\' && len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+
+			if len(str) == 0 || !equalChar(pattern[0], str[0], noCase) {
+				return false
+			}
+
+			str = str[1:]
+			pattern = pattern[1:]
+		}
+	}
+
+	return len(str) == 0
+}
+
+// matchClass matches char against the class following an opening bracket and
+// returns the pattern remaining after the closing bracket
+func matchClass(pattern string, char byte, noCase bool) (bool, string) {
+	negate := false
+
+	if len(pattern) > 0 && pattern[0] == '^' {
+		negate = true
+		pattern = pattern[1:]
+	}
+
+	matched := false
+
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) >= 2:
+			if equalChar(pattern[1], char, noCase) {
+				matched = true
+			}
+
+			pattern = pattern[2:]
+
+		case len(pattern) >= 3 && pattern[1] == '-' && pattern[2] != ']':
+			start, end := pattern[0], pattern[2]
+
+			if start > end {
+				start, end = end, start
+			}
+
+			value := char
+
+			if noCase {
+				start, end, value = toLower(start), toLower(end), toLower(char)
+			}
+
+			if value >= start && value <= end {
+				matched = true
+			}
+
+			pattern = pattern[3:]
+
+		default:
+			if equalChar(pattern[0], char, noCase) {
+				matched = true
+			}
+
+			pattern = pattern[1:]
+		}
+	}
+
+	// skip the closing bracket, an unterminated class runs until the end of the pattern
+	if len(pattern) > 0 {
+		pattern = pattern[1:]
+	}
+
+	return matched != negate, pattern
+}
+
+func equalChar(a byte, b byte, noCase bool) bool {
+	if noCase {
+		return toLower(a) == toLower(b)
+	}
+
+	return a == b
+}
+
+func toLower(char byte) byte {
+	if char >= 'A' && char <= 'Z' {
+		return char + ('a' - 'A')
+	}
+
+	return char
+}
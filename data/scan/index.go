@@ -0,0 +1,166 @@
+package scan
+
+import (
+	"math/rand"
+)
+
+const (
+	indexMaxLevel = 32
+	// probability of a node having one more level
+	indexP = 0.25
+)
+
+type indexNode struct {
+	item    string
+	hash    uint64
+	forward []*indexNode
+}
+
+// less reports whether the node sorts before the given hash and item
+func (n *indexNode) less(hash uint64, item string) bool {
+	return n.hash < hash || (n.hash == hash && n.item < item)
+}
+
+// Index keeps items in iteration order so that a scan only walks the items it
+// returns instead of sorting the whole collection on every call
+type Index struct {
+	header *indexNode
+	length int
+	level  int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		header: &indexNode{forward: make([]*indexNode, indexMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomIndexLevel() int {
+	level := 1
+
+	for level < indexMaxLevel && rand.Float64() < indexP {
+		level++
+	}
+
+	return level
+}
+
+// Len returns the number of items in the index
+func (idx *Index) Len() int {
+	return idx.length
+}
+
+// Add inserts an item, doing nothing if it is in the index already
+func (idx *Index) Add(item string) {
+	itemHash := Hash(item)
+	update := make([]*indexNode, indexMaxLevel)
+
+	current := idx.header
+
+	for i := idx.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && current.forward[i].less(itemHash, item) {
+			current = current.forward[i]
+		}
+
+		update[i] = current
+	}
+
+	if next := current.forward[0]; next != nil && next.hash == itemHash && next.item == item {
+		return
+	}
+
+	level := randomIndexLevel()
+
+	if level > idx.level {
+		for i := idx.level; i < level; i++ {
+			update[i] = idx.header
+		}
+
+		idx.level = level
+	}
+
+	node := &indexNode{item: item, hash: itemHash, forward: make([]*indexNode, level)}
+
+	for i := 0; i < level; i++ {
+		node.forward[i] = update[i].forward[i]
+		update[i].forward[i] = node
+	}
+
+	idx.length++
+}
+
+// Remove deletes an item, doing nothing if it is not in the index
+func (idx *Index) Remove(item string) {
+	itemHash := Hash(item)
+	update := make([]*indexNode, indexMaxLevel)
+
+	current := idx.header
+
+	for i := idx.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && current.forward[i].less(itemHash, item) {
+			current = current.forward[i]
+		}
+
+		update[i] = current
+	}
+
+	node := current.forward[0]
+
+	if node == nil || node.hash != itemHash || node.item != item {
+		return
+	}
+
+	for i := 0; i < idx.level; i++ {
+		if update[i].forward[i] != node {
+			break
+		}
+
+		update[i].forward[i] = node.forward[i]
+	}
+
+	for idx.level > 1 && idx.header.forward[idx.level-1] == nil {
+		idx.level--
+	}
+
+	idx.length--
+}
+
+// Clone returns a copy of the index which shares nothing with the original
+func (idx *Index) Clone() *Index {
+	clone := NewIndex()
+
+	for node := idx.header.forward[0]; node != nil; node = node.forward[0] {
+		clone.Add(node.item)
+	}
+
+	return clone
+}
+
+// Scan works like the Scan function over the items of the index, visiting
+// only the items it returns
+func (idx *Index) Scan(cursor uint64, count int) ([]string, uint64) {
+	current := idx.header
+
+	for i := idx.level - 1; i >= 0; i-- {
+		for current.forward[i] != nil && current.forward[i].hash < cursor {
+			current = current.forward[i]
+		}
+	}
+
+	count = max(count, 1)
+	result := []string{}
+	previous := uint64(0)
+
+	for node := current.forward[0]; node != nil; node = node.forward[0] {
+		// items sharing a hash are returned together, as the cursor can not point between them
+		if len(result) >= count && node.hash != previous {
+			return result, node.hash
+		}
+
+		result = append(result, node.item)
+		previous = node.hash
+	}
+
+	return result, 0
+}
@@ -0,0 +1,106 @@
+package scan
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexAddAndRemove(t *testing.T) {
+	index := NewIndex()
+
+	index.Add("a")
+	index.Add("b")
+	index.Add("a")
+	assert.Equal(t, 2, index.Len())
+
+	index.Remove("a")
+	index.Remove("missing")
+	assert.Equal(t, 1, index.Len())
+
+	items, cursor := index.Scan(0, 10)
+	assert.Equal(t, []string{"b"}, items)
+	assert.Equal(t, uint64(0), cursor)
+
+	index.Remove("b")
+	items, cursor = index.Scan(0, 10)
+	assert.Empty(t, items)
+	assert.Equal(t, uint64(0), cursor)
+}
+
+func TestIndexScanMatchesScan(t *testing.T) {
+	index := NewIndex()
+	items := []string{}
+
+	for i := 0; i < 1000; i++ {
+		item := fmt.Sprintf("item:%d", i)
+		items = append(items, item)
+		index.Add(item)
+	}
+
+	for _, count := range []int{0, 1, 7, 100, 2000} {
+		cursor := uint64(0)
+
+		for {
+			expected, expectedCursor := Scan(items, cursor, count)
+			actual, actualCursor := index.Scan(cursor, count)
+
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, expectedCursor, actualCursor)
+
+			if cursor = actualCursor; cursor == 0 {
+				break
+			}
+		}
+	}
+}
+
+func TestIndexScanWhileChanging(t *testing.T) {
+	index := NewIndex()
+
+	for i := 0; i < 100; i++ {
+		index.Add(fmt.Sprintf("item:%d", i))
+		index.Add(fmt.Sprintf("removed:%d", i))
+	}
+
+	seen := map[string]int{}
+	cursor := uint64(0)
+
+	for {
+		var batch []string
+		batch, cursor = index.Scan(cursor, 7)
+
+		for _, item := range batch {
+			seen[item]++
+		}
+
+		// the index changes while iterating
+		index.Add(fmt.Sprintf("new:%d", len(seen)))
+		index.Remove(fmt.Sprintf("removed:%d", len(seen)%100))
+
+		if cursor == 0 {
+			break
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 1, seen[fmt.Sprintf("item:%d", i)])
+	}
+}
+
+func BenchmarkIndexScan(b *testing.B) {
+	index := NewIndex()
+
+	for i := 0; i < 300000; i++ {
+		index.Add(fmt.Sprintf("key:%d", i))
+	}
+
+	b.ResetTimer()
+
+	cursor := uint64(0)
+
+	for i := 0; i < b.N; i++ {
+		_, cursor = index.Scan(cursor, 10)
+	}
+}
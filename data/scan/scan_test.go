@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		str     string
+		matched bool
+	}{
+		{"*", "anything", true},
+		{"user:*", "user:1", true},
+		{"user:*", "session:1", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h\\*llo", "h*llo", true},
+		{"h\\*llo", "hello", false},
+		{"*:*:end", "a:b:end", true},
+		{"", "", true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, Match(c.pattern, c.str), c.matched, c.pattern+" "+c.str)
+	}
+
+	assert.True(t, MatchNoCase("HELLO*", "hello world"))
+}
+
+func TestScanReturnsEveryItem(t *testing.T) {
+	items := []string{}
+
+	for i := 0; i < 100; i++ {
+		items = append(items, fmt.Sprintf("item:%d", i))
+	}
+
+	seen := map[string]int{}
+	cursor := uint64(0)
+
+	for {
+		var batch []string
+		batch, cursor = Scan(items, cursor, 7)
+
+		for _, item := range batch {
+			seen[item]++
+		}
+
+		// the collection grows while iterating
+		items = append(items, fmt.Sprintf("new:%d", len(items)))
+
+		if cursor == 0 {
+			break
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, seen[fmt.Sprintf("item:%d", i)], 1)
+	}
+}
@@ -0,0 +1,186 @@
+package set
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+)
+
+// Set keeps its members in a slice along with their position in it, so that
+// random members are picked without going through every member
+type Set struct {
+	positions map[string]int
+	members   []string
+	// the members in the order SSCAN visits them
+	index *scan.Index
+	lock  *sync.RWMutex
+}
+
+func NewSet() *Set {
+	return &Set{
+		positions: make(map[string]int),
+		members:   []string{},
+		index:     scan.NewIndex(),
+		lock:      &sync.RWMutex{},
+	}
+}
+
+func (s *Set) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *Set) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.members)
+}
+
+// Add adds the member, returns whether it was not a member already
+func (s *Set) Add(member string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.positions[member]; exists {
+		return false
+	}
+
+	s.positions[member] = len(s.members)
+	s.members = append(s.members, member)
+	s.index.Add(member)
+
+	return true
+}
+
+// Remove removes the member, returns whether it was a member
+func (s *Set) Remove(member string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	position, exists := s.positions[member]
+
+	if exists {
+		s.removeAt(position)
+	}
+
+	return exists
+}
+
+// removeAt removes the member at position, moving the last member in its place
+func (s *Set) removeAt(position int) string {
+	member := s.members[position]
+	last := len(s.members) - 1
+
+	s.members[position] = s.members[last]
+	s.positions[s.members[position]] = position
+	s.members = s.members[:last]
+	delete(s.positions, member)
+	s.index.Remove(member)
+
+	return member
+}
+
+func (s *Set) Contains(member string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, exists := s.positions[member]
+	return exists
+}
+
+func (s *Set) Members() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	members := make([]string, len(s.members))
+	copy(members, s.members)
+
+	return members
+}
+
+// RandomMember returns a random member, false when the set is empty
+func (s *Set) RandomMember() (string, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.members) == 0 {
+		return "", false
+	}
+
+	return s.members[rand.Intn(len(s.members))], true
+}
+
+// RandomMembers returns up to count distinct random members in a random
+// order, in time proportional to the number of members returned
+func (s *Set) RandomMembers(count int) []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	size := len(s.members)
+	count = min(count, size)
+
+	// Floyd's algorithm picks count distinct positions among size
+	picked := make(map[int]struct{}, count)
+	result := make([]string, 0, count)
+
+	for i := size - count; i < size; i++ {
+		position := rand.Intn(i + 1)
+
+		if _, taken := picked[position]; taken {
+			position = i
+		}
+
+		picked[position] = struct{}{}
+		result = append(result, s.members[position])
+	}
+
+	rand.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+
+	return result
+}
+
+// Pop removes and returns up to count random members
+func (s *Set) Pop(count int) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	popped := make([]string, 0, min(count, len(s.members)))
+
+	for len(popped) < count && len(s.members) > 0 {
+		popped = append(popped, s.removeAt(rand.Intn(len(s.members))))
+	}
+
+	return popped
+}
+
+// Scan returns up to count members not visited yet by the iteration at
+// cursor, see scan.Scan for the cursor guarantees
+func (s *Set) Scan(cursor uint64, count int) ([]string, uint64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.index.Scan(cursor, count)
+}
+
+// Clone returns a copy of the set which shares nothing with the original
+func (s *Set) Clone() *Set {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	clone := &Set{
+		positions: make(map[string]int, len(s.positions)),
+		members:   make([]string, len(s.members)),
+		index:     s.index.Clone(),
+		lock:      &sync.RWMutex{},
+	}
+
+	copy(clone.members, s.members)
+
+	for member, position := range s.positions {
+		clone.positions[member] = position
+	}
+
+	return clone
+}
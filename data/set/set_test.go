@@ -0,0 +1,110 @@
+package set
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAndRemove(t *testing.T) {
+	s := NewSet()
+
+	assert.True(t, s.IsEmpty())
+	assert.True(t, s.Add("a"))
+	assert.False(t, s.Add("a"))
+	assert.True(t, s.Add("b"))
+
+	assert.True(t, s.Contains("a"))
+	assert.Equal(t, 2, s.Len())
+
+	assert.True(t, s.Remove("a"))
+	assert.False(t, s.Remove("a"))
+	assert.False(t, s.Contains("a"))
+	assert.Equal(t, []string{"b"}, s.Members())
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	s := NewSet()
+	s.Add("a")
+
+	clone := s.Clone()
+	clone.Add("b")
+	clone.Remove("a")
+
+	assert.Equal(t, []string{"a"}, s.Members())
+	assert.Equal(t, []string{"b"}, clone.Members())
+}
+
+func TestRandomMembers(t *testing.T) {
+	s := NewSet()
+
+	_, found := s.RandomMember()
+	assert.False(t, found)
+	assert.Empty(t, s.RandomMembers(3))
+
+	for i := 0; i < 100; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+
+	members := s.RandomMembers(10)
+	assert.Len(t, members, 10)
+
+	distinct := map[string]struct{}{}
+
+	for _, member := range members {
+		assert.True(t, s.Contains(member))
+		distinct[member] = struct{}{}
+	}
+
+	assert.Len(t, distinct, 10)
+	assert.ElementsMatch(t, s.Members(), s.RandomMembers(1000))
+
+	member, found := s.RandomMember()
+	assert.True(t, found)
+	assert.True(t, s.Contains(member))
+}
+
+func TestPop(t *testing.T) {
+	s := NewSet()
+
+	for i := 0; i < 10; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+
+	popped := s.Pop(4)
+	assert.Len(t, popped, 4)
+	assert.Equal(t, 6, s.Len())
+
+	for _, member := range popped {
+		assert.False(t, s.Contains(member))
+	}
+
+	// the members left are still found after being moved around
+	for _, member := range s.Members() {
+		assert.True(t, s.Remove(member))
+	}
+
+	assert.True(t, s.IsEmpty())
+	assert.Empty(t, s.Pop(1))
+}
+
+func TestScanFollowsChanges(t *testing.T) {
+	s := NewSet()
+
+	for i := 0; i < 100; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+
+	s.Pop(10)
+	clone := s.Clone()
+	clone.Remove(clone.Members()[0])
+
+	members, cursor := s.Scan(0, 1000)
+	assert.ElementsMatch(t, s.Members(), members)
+	assert.Equal(t, uint64(0), cursor)
+
+	members, _ = clone.Scan(0, 1000)
+	assert.ElementsMatch(t, clone.Members(), members)
+	assert.Len(t, members, 89)
+}
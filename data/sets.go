@@ -0,0 +1,357 @@
+package data
+
+import (
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+)
+
+// getSet returns the set stored at key, creating an empty one when create is
+// set. The returned set is nil when the key does not exist and create is not set.
+func (s *Store) getSet(key string, create bool) (*set.Set, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		if !create {
+			return nil, nil
+		}
+
+		return set.NewSet(), nil
+	}
+
+	existSet, isSetType := data.(*set.Set)
+
+	if !isSetType {
+		return nil, ErrWrongType
+	}
+
+	return s.own(key, existSet).(*set.Set), nil
+}
+
+// getSets returns the sets stored at keys, missing keys are returned as empty sets
+func (s *Store) getSets(keys ...string) ([]*set.Set, error) {
+	sets := []*set.Set{}
+
+	for _, key := range keys {
+		existSet, err := s.getSet(key, true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sets = append(sets, existSet)
+	}
+
+	return sets, nil
+}
+
+// storeSet replaces the key with the given set, an empty set deletes the key
+func (s *Store) storeSet(key string, value *set.Set) {
+	if value.IsEmpty() {
+		s.deleteWithLock(key)
+		return
+	}
+
+	s.setWithExpiry(key, value, 0)
+}
+
+// SAdd adds the members, returns the number of members which were not in the set already
+func (s *Store) SAdd(key string, members ...string) (int, error) {
+	existSet, err := s.getSet(key, true)
+
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+
+	for _, member := range members {
+		if existSet.Add(member) {
+			added++
+		}
+	}
+
+	s.setWithLock(key, existSet)
+	return added, nil
+}
+
+// SRem removes the members, deleting the key once the set is empty.
+// Returns the number of removed members.
+func (s *Store) SRem(key string, members ...string) (int, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for _, member := range members {
+		if existSet.Remove(member) {
+			removed++
+		}
+	}
+
+	if existSet.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if removed > 0 {
+		s.touch(key)
+	}
+
+	return removed, nil
+}
+
+func (s *Store) SMembers(key string) ([]string, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []string{}, err
+	}
+
+	return existSet.Members(), nil
+}
+
+func (s *Store) SIsMember(key string, member string) (bool, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return false, err
+	}
+
+	return existSet.Contains(member), nil
+}
+
+func (s *Store) SMIsMember(key string, members ...string) ([]bool, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, len(members))
+
+	if existSet == nil {
+		return result, nil
+	}
+
+	for i, member := range members {
+		result[i] = existSet.Contains(member)
+	}
+
+	return result, nil
+}
+
+func (s *Store) SCard(key string) (int, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	return existSet.Len(), nil
+}
+
+// SPop removes and returns up to count random members
+func (s *Store) SPop(key string, count int) ([]string, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []string{}, err
+	}
+
+	popped := existSet.Pop(count)
+
+	if existSet.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if len(popped) > 0 {
+		s.touch(key)
+	}
+
+	return popped, nil
+}
+
+// SRandMember returns random members. A positive count returns distinct
+// members, a negative one allows the same member multiple times.
+func (s *Store) SRandMember(key string, count int) ([]string, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []string{}, err
+	}
+
+	if count >= 0 {
+		return existSet.RandomMembers(count), nil
+	}
+
+	result := []string{}
+
+	for i := 0; i < -count; i++ {
+		member, _ := existSet.RandomMember()
+		result = append(result, member)
+	}
+
+	return result, nil
+}
+
+// SMove moves the member from source to destination, returns whether it was moved
+func (s *Store) SMove(source string, destination string, member string) (bool, error) {
+	sourceSet, err := s.getSet(source, false)
+
+	if err != nil {
+		return false, err
+	}
+
+	destinationSet, err := s.getSet(destination, true)
+
+	if err != nil {
+		return false, err
+	}
+
+	if sourceSet == nil || !sourceSet.Contains(member) {
+		return false, nil
+	}
+
+	if source == destination {
+		return true, nil
+	}
+
+	sourceSet.Remove(member)
+
+	if sourceSet.IsEmpty() {
+		s.deleteWithLock(source)
+	} else {
+		s.touch(source)
+	}
+
+	destinationSet.Add(member)
+	s.setWithLock(destination, destinationSet)
+
+	return true, nil
+}
+
+// SInter returns the members present in every set
+func (s *Store) SInter(keys ...string) ([]string, error) {
+	return s.SInterCard(0, keys...)
+}
+
+// SInterCard returns the intersection of the sets, stopping once it holds limit
+// members. A zero limit returns the whole intersection.
+func (s *Store) SInterCard(limit int, keys ...string) ([]string, error) {
+	sets, err := s.getSets(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// start from the smallest set, nothing outside of it can be in the intersection
+	smallest := sets[0]
+
+	for _, current := range sets[1:] {
+		if current.Len() < smallest.Len() {
+			smallest = current
+		}
+	}
+
+	result := []string{}
+
+	for _, member := range smallest.Members() {
+		inEvery := true
+
+		for _, current := range sets {
+			if current != smallest && !current.Contains(member) {
+				inEvery = false
+				break
+			}
+		}
+
+		if !inEvery {
+			continue
+		}
+
+		result = append(result, member)
+
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// SUnion returns the members present in any of the sets
+func (s *Store) SUnion(keys ...string) ([]string, error) {
+	sets, err := s.getSets(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	union := set.NewSet()
+
+	for _, current := range sets {
+		for _, member := range current.Members() {
+			union.Add(member)
+		}
+	}
+
+	return union.Members(), nil
+}
+
+// SDiff returns the members of the first set which are not in any of the others
+func (s *Store) SDiff(keys ...string) ([]string, error) {
+	sets, err := s.getSets(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+
+	for _, member := range sets[0].Members() {
+		inOther := false
+
+		for _, current := range sets[1:] {
+			if current.Contains(member) {
+				inOther = true
+				break
+			}
+		}
+
+		if !inOther {
+			result = append(result, member)
+		}
+	}
+
+	return result, nil
+}
+
+// SStore replaces destination with a set of the given members, returns its size
+func (s *Store) SStore(destination string, members []string) int {
+	result := set.NewSet()
+
+	for _, member := range members {
+		result.Add(member)
+	}
+
+	s.storeSet(destination, result)
+	return result.Len()
+}
+
+// SScan iterates the members of the set, see scan.Scan for the cursor guarantees
+func (s *Store) SScan(key string, cursor uint64, pattern string, count int) ([]string, uint64, error) {
+	existSet, err := s.getSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []string{}, 0, err
+	}
+
+	members, nextCursor := existSet.Scan(cursor, count)
+	result := []string{}
+
+	for _, member := range members {
+		if pattern == "" || scan.Match(pattern, member) {
+			result = append(result, member)
+		}
+	}
+
+	return result, nextCursor, nil
+}
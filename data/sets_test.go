@@ -0,0 +1,155 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSPop(t *testing.T) {
+	store := NewStore()
+	store.SAdd("set", "a", "b", "c", "d")
+
+	popped, _ := store.SPop("set", 3)
+	assert.Len(t, popped, 3)
+
+	for _, member := range popped {
+		found, _ := store.SIsMember("set", member)
+		assert.False(t, found, member)
+	}
+
+	// the set is deleted once its last member is popped
+	popped, _ = store.SPop("set", 10)
+	assert.Len(t, popped, 1)
+	assert.False(t, store.Exists("set"))
+
+	popped, _ = store.SPop("set", 1)
+	assert.Empty(t, popped)
+
+	store.SAdd("set", "a")
+	popped, _ = store.SPop("set", 0)
+	assert.Empty(t, popped)
+	assert.True(t, store.Exists("set"))
+}
+
+func TestSRandMember(t *testing.T) {
+	store := NewStore()
+	store.SAdd("set", "a", "b", "c")
+
+	// a positive count returns distinct members, at most the whole set
+	members, _ := store.SRandMember("set", 2)
+	assert.Len(t, members, 2)
+	assert.NotEqual(t, members[0], members[1])
+
+	members, _ = store.SRandMember("set", 10)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, members)
+
+	// a negative count returns exactly that many members, repeating them
+	members, _ = store.SRandMember("set", -10)
+	assert.Len(t, members, 10)
+
+	for _, member := range members {
+		assert.Contains(t, []string{"a", "b", "c"}, member)
+	}
+
+	// nothing is removed
+	size, _ := store.SCard("set")
+	assert.Equal(t, 3, size)
+
+	members, _ = store.SRandMember("missing", -5)
+	assert.Empty(t, members)
+}
+
+func TestSMove(t *testing.T) {
+	store := NewStore()
+	store.SAdd("source", "a", "b")
+
+	moved, _ := store.SMove("source", "destination", "a")
+	assert.True(t, moved)
+
+	members, _ := store.SMembers("destination")
+	assert.Equal(t, []string{"a"}, members)
+
+	moved, _ = store.SMove("source", "destination", "missing")
+	assert.False(t, moved)
+
+	// moving to the same set only checks the member
+	moved, _ = store.SMove("source", "source", "b")
+	assert.True(t, moved)
+
+	moved, _ = store.SMove("source", "destination", "b")
+	assert.True(t, moved)
+	assert.False(t, store.Exists("source"))
+
+	store.Set("name", "value", "", 0)
+	_, err := store.SMove("destination", "name", "a")
+	assert.Equal(t, ErrWrongType, err)
+
+	found, _ := store.SIsMember("destination", "a")
+	assert.True(t, found)
+}
+
+func TestSetAlgebra(t *testing.T) {
+	store := NewStore()
+	store.SAdd("a", "1", "2", "3", "4")
+	store.SAdd("b", "3", "4", "5")
+	store.SAdd("c", "4", "6")
+
+	inter, _ := store.SInter("a", "b", "c")
+	assert.ElementsMatch(t, []string{"4"}, inter)
+
+	union, _ := store.SUnion("a", "b", "c")
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4", "5", "6"}, union)
+
+	diff, _ := store.SDiff("a", "b", "c")
+	assert.ElementsMatch(t, []string{"1", "2"}, diff)
+
+	// missing keys are empty sets
+	inter, _ = store.SInter("a", "missing")
+	assert.Empty(t, inter)
+
+	union, _ = store.SUnion("missing", "c")
+	assert.ElementsMatch(t, []string{"4", "6"}, union)
+
+	diff, _ = store.SDiff("missing", "a")
+	assert.Empty(t, diff)
+
+	store.Set("name", "value", "", 0)
+	_, err := store.SInter("a", "name")
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestSStore(t *testing.T) {
+	store := NewStore()
+	store.Set("destination", "value", "EX", 100)
+
+	// the destination is replaced whatever it held, timeout included
+	assert.Equal(t, 2, store.SStore("destination", []string{"a", "b", "a"}))
+
+	members, _ := store.SMembers("destination")
+	assert.ElementsMatch(t, []string{"a", "b"}, members)
+
+	assert.Equal(t, TTL_NO_EXPIRE, store.TTL("destination"))
+
+	assert.Equal(t, 0, store.SStore("destination", []string{}))
+	assert.False(t, store.Exists("destination"))
+}
+
+func TestSInterCard(t *testing.T) {
+	store := NewStore()
+
+	for i := 0; i < 10; i++ {
+		store.SAdd("a", fmt.Sprint(i))
+		store.SAdd("b", fmt.Sprint(i*2))
+	}
+
+	members, _ := store.SInterCard(0, "a", "b")
+	assert.ElementsMatch(t, []string{"0", "2", "4", "6", "8"}, members)
+
+	members, _ = store.SInterCard(3, "a", "b")
+	assert.Len(t, members, 3)
+
+	members, _ = store.SInterCard(100, "a", "b")
+	assert.Len(t, members, 5)
+}
@@ -0,0 +1,194 @@
+package data
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+)
+
+// Entry is a key along with its value and timeout, as stored in snapshots
+type Entry struct {
+	// index of the database holding the key
+	DB    int
+	Key   string
+	Value interface{}
+	// unix time in milliseconds, zero for keys without a timeout
+	ExpireAt int64
+}
+
+// Snapshot returns a point in time view of every key. Values are not copied,
+// they are shared with the store until ReleaseSnapshot is called, and a
+// command changing one of them in the meantime changes a copy instead. Taking
+// a snapshot costs as much as listing the keys, and each value is copied at
+// most once, only when it is changed while the snapshot is still in use.
+func (s *Store) Snapshot() []Entry {
+	s.wl.RLock()
+	defer s.wl.RUnlock()
+
+	now := time.Now().UnixMilli()
+	entries := make([]Entry, 0, len(s.data))
+
+	for key, value := range s.data {
+		if s.isExpired(key, now) {
+			continue
+		}
+
+		frozen.add(value)
+
+		entries = append(entries, Entry{
+			Key:      key,
+			Value:    value,
+			ExpireAt: s.expires[key],
+		})
+	}
+
+	return entries
+}
+
+// ReleaseSnapshot lets the store change again in place the values of entries
+// returned by Snapshot, once the snapshot is not used anymore
+func ReleaseSnapshot(entries []Entry) {
+	for _, entry := range entries {
+		frozen.remove(entry.Value)
+	}
+}
+
+// frozen holds the values shared with the snapshots still in use. Values are
+// tracked by identity rather than by key, as RENAME, MOVE and SWAPDB move a
+// value to another key, or to another store, without copying it.
+var frozen = &frozenValues{values: make(map[any]int)}
+
+type frozenValues struct {
+	// number of values tracked, checked before taking the lock
+	count  atomic.Int64
+	values map[any]int
+	lock   sync.Mutex
+}
+
+// identity returns what tells a mutable value apart from any other, false for
+// immutable values which never need to be copied
+func identity(value interface{}) (any, bool) {
+	switch typedValue := value.(type) {
+	case *list.List, *hash.Hash, *set.Set, *zset.SortedSet, *stream.Stream:
+		return typedValue, true
+	case []byte:
+		// slices are not comparable, their first byte identifies their array
+		if cap(typedValue) == 0 {
+			return nil, false
+		}
+
+		return &typedValue[:1][0], true
+	}
+
+	return nil, false
+}
+
+func (f *frozenValues) add(value interface{}) {
+	id, mutable := identity(value)
+
+	if !mutable {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.values[id]++
+	f.count.Add(1)
+}
+
+func (f *frozenValues) remove(value interface{}) {
+	id, mutable := identity(value)
+
+	if !mutable {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.values[id]--; f.values[id] <= 0 {
+		delete(f.values, id)
+	}
+
+	f.count.Add(-1)
+}
+
+func (f *frozenValues) contains(value interface{}) bool {
+	if f.count.Load() == 0 {
+		return false
+	}
+
+	id, mutable := identity(value)
+
+	if !mutable {
+		return false
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	_, found := f.values[id]
+	return found
+}
+
+// own returns the value of the key which the caller may change in place. A
+// value shared with a snapshot is replaced in the store by a copy first.
+func (s *Store) own(key string, value interface{}) interface{} {
+	if !frozen.contains(value) {
+		return value
+	}
+
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	return s.ownWithoutLock(key, value)
+}
+
+// ownWithoutLock is own for callers already holding the write lock
+func (s *Store) ownWithoutLock(key string, value interface{}) interface{} {
+	if !frozen.contains(value) {
+		return value
+	}
+
+	owned := cloneValue(value)
+	s.data[key] = owned
+
+	return owned
+}
+
+// Restore adds a snapshot entry to the store, entries which expired in the
+// meantime are skipped
+func (s *Store) Restore(entry Entry) {
+	if entry.ExpireAt > 0 && entry.ExpireAt <= time.Now().UnixMilli() {
+		return
+	}
+
+	s.setWithExpiry(entry.Key, entry.Value, entry.ExpireAt)
+}
+
+func cloneValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case *list.List:
+		return typedValue.Clone()
+	case *hash.Hash:
+		return typedValue.Clone()
+	case *set.Set:
+		return typedValue.Clone()
+	case *zset.SortedSet:
+		return typedValue.Clone()
+	case *stream.Stream:
+		return typedValue.Clone()
+	case []byte:
+		return append([]byte(nil), typedValue...)
+	}
+
+	// strings are immutable and can be shared
+	return value
+}
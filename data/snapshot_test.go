@@ -0,0 +1,70 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshotValues(entries []Entry) map[string]interface{} {
+	values := map[string]interface{}{}
+
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+
+	return values
+}
+
+func TestSnapshotCopiesOnWrite(t *testing.T) {
+	store, other := NewStore(), NewStore()
+	store.Rpush("list", "a", "b")
+	store.SAdd("set", "a")
+	store.HSet("hash", "field", "value")
+	store.Append("bytes", "ab")
+	store.Rpush("untouched", "a")
+
+	entries := store.Snapshot()
+	values := snapshotValues(entries)
+
+	// the snapshot shares the values instead of copying them
+	assert.Same(t, store.data["list"], values["list"])
+
+	store.Rpush("list", "c")
+	store.SAdd("set", "b")
+	store.Append("bytes", "c")
+
+	// values moved to another key or store are still the shared ones
+	store.Rename("hash", "renamed", false)
+	store.HSet("renamed", "field", "changed")
+	store.Move("untouched", other)
+	other.Rpush("untouched", "b")
+
+	assert.Equal(t, 2, values["list"].(*list.List).Len())
+	assert.Equal(t, []string{"a"}, values["set"].(*set.Set).Members())
+	assert.Equal(t, "value", values["hash"].(*hash.Hash).Entries()["field"])
+	assert.Equal(t, "ab", string(values["bytes"].([]byte)))
+	assert.Equal(t, 1, values["untouched"].(*list.List).Len())
+
+	length, _ := store.LLen("list")
+	assert.Equal(t, 3, length)
+
+	bytes, _, _ := store.Get("bytes")
+	assert.Equal(t, "abc", bytes)
+
+	// a value is copied once, later writes change the copy in place
+	copied := store.data["list"]
+	store.Rpush("list", "d")
+	assert.Same(t, copied, store.data["list"])
+
+	ReleaseSnapshot(entries)
+
+	store.Rpush("released", "a")
+	released := store.data["released"]
+	ReleaseSnapshot(store.Snapshot())
+	store.Rpush("released", "b")
+	assert.Same(t, released, store.data["released"])
+}
@@ -0,0 +1,738 @@
+package stream
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	// the handlers name the key and the group in the reply
+	ErrNoGroup     = errors.New("NOGROUP No such consumer group")
+	ErrGroupExists = errors.New("BUSYGROUP Consumer Group name already exists")
+)
+
+// group is a consumer group, delivering the entries of the stream to its
+// consumers and keeping the entries they did not acknowledge yet pending
+type group struct {
+	// ID of the last entry delivered
+	lastID ID
+	// number of entries delivered, -1 when it is not known
+	entriesRead int64
+	// pending entries list of the group, by entry ID
+	pending   *index[*pendingEntry]
+	consumers map[string]*consumer
+}
+
+type consumer struct {
+	// unix times in milliseconds of the last interaction and of the last
+	// successful read or claim, activeTime is -1 until then
+	seenTime   int64
+	activeTime int64
+	// entries pending for the consumer, shared with the group
+	pending *index[*pendingEntry]
+}
+
+type pendingEntry struct {
+	consumer      string
+	deliveryTime  int64
+	deliveryCount int64
+}
+
+// PendingEntry is an entry delivered to a consumer and not acknowledged yet
+type PendingEntry struct {
+	ID       ID
+	Consumer string
+	// unix time in milliseconds of the last delivery
+	DeliveryTime  int64
+	DeliveryCount int64
+}
+
+// GroupInfo describes a consumer group for XINFO GROUPS
+type GroupInfo struct {
+	Name        string
+	Consumers   int
+	Pending     int
+	LastID      ID
+	EntriesRead int64
+	// number of entries not delivered yet, -1 when it is not known
+	Lag int64
+}
+
+// ConsumerInfo describes a consumer for XINFO CONSUMERS
+type ConsumerInfo struct {
+	Name       string
+	Pending    int
+	SeenTime   int64
+	ActiveTime int64
+}
+
+func newGroup(lastID ID, entriesRead int64) *group {
+	return &group{
+		lastID:      lastID,
+		entriesRead: entriesRead,
+		pending:     newIndex[*pendingEntry](),
+		consumers:   make(map[string]*consumer),
+	}
+}
+
+func (g *group) clone() *group {
+	cloned := &group{
+		lastID:      g.lastID,
+		entriesRead: g.entriesRead,
+		pending: g.pending.clone(func(entry *pendingEntry) *pendingEntry {
+			clonedEntry := *entry
+			return &clonedEntry
+		}),
+		consumers: make(map[string]*consumer, len(g.consumers)),
+	}
+
+	for name, current := range g.consumers {
+		cloned.consumers[name] = &consumer{
+			seenTime:   current.seenTime,
+			activeTime: current.activeTime,
+			pending:    newIndex[*pendingEntry](),
+		}
+	}
+
+	// the consumers share the pending entries of the cloned group
+	cloned.pending.Ascend(MinID, func(id ID, entry *pendingEntry) bool {
+		cloned.consumers[entry.consumer].pending.Set(id, entry)
+		return true
+	})
+
+	return cloned
+}
+
+// consumer returns the consumer, creating it when it does not exist
+func (g *group) consumer(name string, now int64) *consumer {
+	current, found := g.consumers[name]
+
+	if !found {
+		current = &consumer{activeTime: -1, pending: newIndex[*pendingEntry]()}
+		g.consumers[name] = current
+	}
+
+	current.seenTime = now
+	return current
+}
+
+// deliver adds the entry to the pending entries of the consumer, taking it
+// from its previous consumer
+func (g *group) deliver(id ID, name string, current *consumer, now int64) *pendingEntry {
+	entry, found := g.pending.Get(id)
+
+	if !found {
+		entry = &pendingEntry{}
+		g.pending.Set(id, entry)
+	} else if entry.consumer != name {
+		g.consumers[entry.consumer].pending.Delete(id)
+	}
+
+	entry.consumer = name
+	entry.deliveryTime = now
+	entry.deliveryCount = 1
+	current.pending.Set(id, entry)
+
+	return entry
+}
+
+func (g *group) acknowledge(id ID) bool {
+	entry, found := g.pending.Get(id)
+
+	if !found {
+		return false
+	}
+
+	g.pending.Delete(id)
+	g.consumers[entry.consumer].pending.Delete(id)
+
+	return true
+}
+
+func (s *Stream) group(name string) (*group, error) {
+	current, found := s.groups[name]
+
+	if !found {
+		return nil, ErrNoGroup
+	}
+
+	return current, nil
+}
+
+// CreateGroup creates a consumer group delivering the entries after id,
+// entriesRead is the number of entries up to id or -1 when unknown
+func (s *Stream) CreateGroup(name string, id ID, entriesRead int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, found := s.groups[name]; found {
+		return ErrGroupExists
+	}
+
+	s.groups[name] = newGroup(id, entriesRead)
+	return nil
+}
+
+// SetGroupID makes the group deliver the entries after id
+func (s *Stream) SetGroupID(name string, id ID, entriesRead int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(name)
+
+	if err != nil {
+		return err
+	}
+
+	current.lastID = id
+	current.entriesRead = entriesRead
+
+	return nil
+}
+
+func (s *Stream) DestroyGroup(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, found := s.groups[name]; !found {
+		return false
+	}
+
+	delete(s.groups, name)
+	return true
+}
+
+// CreateConsumer adds a consumer to the group, returns false when it exists
+func (s *Stream) CreateConsumer(groupName string, name string, now int64) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return false, err
+	}
+
+	_, exists := current.consumers[name]
+	current.consumer(name, now)
+
+	return !exists, nil
+}
+
+// DeleteConsumer removes a consumer from the group along with its pending
+// entries, returns the number of entries it had pending
+func (s *Stream) DeleteConsumer(groupName string, name string) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, found := current.consumers[name]
+
+	if !found {
+		return 0, nil
+	}
+
+	deleted.pending.Ascend(MinID, func(id ID, _ *pendingEntry) bool {
+		current.pending.Delete(id)
+		return true
+	})
+
+	delete(current.consumers, name)
+	return deleted.pending.Len(), nil
+}
+
+// ReadGroup delivers up to count entries to the consumer, created when it does
+// not exist. When fromLast is set the entries are the ones the group did not
+// deliver yet, they are added to the pending entries of the consumer unless
+// noAck is set. Otherwise they are the entries after id pending for the
+// consumer, delivered again. A zero count reads every entry.
+func (s *Stream) ReadGroup(groupName string, name string, id ID, fromLast bool, count int, noAck bool, now int64) ([]Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader := current.consumer(name, now)
+	entries := []Entry{}
+
+	if !fromLast {
+		start, found := id.Next()
+
+		if !found {
+			return entries, nil
+		}
+
+		reader.pending.Ascend(start, func(id ID, entry *pendingEntry) bool {
+			fields, _ := s.entries.Get(id)
+			entries = append(entries, Entry{ID: id, Fields: fields})
+
+			entry.deliveryTime = now
+			entry.deliveryCount++
+
+			return count <= 0 || len(entries) < count
+		})
+
+		return entries, nil
+	}
+
+	start, found := current.lastID.Next()
+
+	if !found {
+		return entries, nil
+	}
+
+	entries = s.rangeWithoutLock(start, MaxID, count, false)
+
+	for _, entry := range entries {
+		if current.entriesRead != -1 && !s.hasTombstones(entry.ID) {
+			current.entriesRead++
+		} else {
+			current.entriesRead = s.entriesUpTo(entry.ID)
+		}
+
+		current.lastID = entry.ID
+
+		if !noAck {
+			current.deliver(entry.ID, name, reader, now)
+		}
+	}
+
+	if len(entries) > 0 {
+		reader.activeTime = now
+	}
+
+	return entries, nil
+}
+
+// Ack removes the entries from the pending entries of the group, returns the
+// number of entries which were pending
+func (s *Stream) Ack(groupName string, ids ...ID) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	acknowledged := 0
+
+	for _, id := range ids {
+		if current.acknowledge(id) {
+			acknowledged++
+		}
+	}
+
+	return acknowledged, nil
+}
+
+// PendingSummary describes the pending entries of a group, its consumers
+// are sorted by name
+type PendingSummary struct {
+	Count     int
+	First     ID
+	Last      ID
+	Consumers []ConsumerInfo
+}
+
+func (s *Stream) PendingSummary(groupName string) (PendingSummary, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return PendingSummary{}, err
+	}
+
+	summary := PendingSummary{Count: current.pending.Len()}
+	summary.First, _, _ = current.pending.First()
+	summary.Last, _, _ = current.pending.Last()
+
+	for _, name := range sortedNames(current.consumers) {
+		if pending := current.consumers[name].pending.Len(); pending > 0 {
+			summary.Consumers = append(summary.Consumers, ConsumerInfo{Name: name, Pending: pending})
+		}
+	}
+
+	return summary, nil
+}
+
+// PendingQuery selects pending entries
+type PendingQuery struct {
+	Start ID
+	End   ID
+	// most entries returned, zero for all
+	Count int
+	// only the entries of this consumer when it is not empty
+	Consumer string
+	// only the entries delivered at least this many milliseconds before now,
+	// when it is positive
+	MinIdle int64
+	Now     int64
+}
+
+func (s *Stream) Pending(groupName string, query PendingQuery) ([]PendingEntry, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []PendingEntry{}
+	pending := current.pending
+
+	if query.Consumer != "" {
+		owner, found := current.consumers[query.Consumer]
+
+		if !found {
+			return entries, nil
+		}
+
+		pending = owner.pending
+	}
+
+	if query.End.Less(query.Start) {
+		return entries, nil
+	}
+
+	pending.Ascend(query.Start, func(id ID, entry *pendingEntry) bool {
+		if query.End.Less(id) {
+			return false
+		}
+
+		if query.MinIdle <= 0 || query.Now-entry.deliveryTime >= query.MinIdle {
+			entries = append(entries, entry.export(id))
+		}
+
+		return query.Count <= 0 || len(entries) < query.Count
+	})
+
+	return entries, nil
+}
+
+func (entry *pendingEntry) export(id ID) PendingEntry {
+	return PendingEntry{
+		ID:            id,
+		Consumer:      entry.consumer,
+		DeliveryTime:  entry.deliveryTime,
+		DeliveryCount: entry.deliveryCount,
+	}
+}
+
+// ClaimOptions are the options of XCLAIM
+type ClaimOptions struct {
+	// only claim entries delivered at least this many milliseconds before now
+	MinIdle int64
+	// delivery time set on the claimed entries, -1 for now
+	DeliveryTime int64
+	// delivery count set on the claimed entries, -1 to increment it
+	RetryCount int64
+	// claim entries which are not pending as long as they exist
+	Force bool
+	// the delivery count is not incremented, only IDs are replied
+	JustID bool
+	// the last ID of the group is moved up to this ID
+	LastID ID
+	Now    int64
+}
+
+// ClaimResult lists the entries claimed along with their pending entries, and
+// the entries found deleted which were removed from the pending entries
+type ClaimResult struct {
+	Entries []Entry
+	Pending []PendingEntry
+	Deleted []ID
+	// ID to resume XAUTOCLAIM from, zero when every entry was scanned
+	Next ID
+}
+
+// Claim gives the pending entries to the consumer, created when it does not
+// exist
+func (s *Stream) Claim(groupName string, name string, ids []ID, options ClaimOptions) (ClaimResult, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return ClaimResult{}, err
+	}
+
+	if current.lastID.Less(options.LastID) {
+		current.lastID = options.LastID
+	}
+
+	deliveryTime := options.DeliveryTime
+
+	if deliveryTime < 0 || deliveryTime > options.Now {
+		deliveryTime = options.Now
+	}
+
+	owner := current.consumer(name, options.Now)
+	result := ClaimResult{}
+
+	for _, id := range ids {
+		entry, pending := current.pending.Get(id)
+		fields, exists := s.entries.Get(id)
+
+		if !exists {
+			if pending {
+				current.acknowledge(id)
+				result.Deleted = append(result.Deleted, id)
+			}
+
+			continue
+		}
+
+		// entries forced into the pending entries count as delivered once
+		deliveryCount := int64(1)
+
+		if pending {
+			if options.MinIdle > 0 && options.Now-entry.deliveryTime < options.MinIdle {
+				continue
+			}
+
+			deliveryCount = entry.deliveryCount
+		} else if !options.Force {
+			continue
+		}
+
+		if options.RetryCount >= 0 {
+			deliveryCount = options.RetryCount
+		} else if !options.JustID {
+			deliveryCount++
+		}
+
+		entry = current.deliver(id, name, owner, deliveryTime)
+		entry.deliveryCount = deliveryCount
+		owner.activeTime = options.Now
+
+		result.Entries = append(result.Entries, Entry{ID: id, Fields: fields})
+		result.Pending = append(result.Pending, entry.export(id))
+	}
+
+	return result, nil
+}
+
+// AutoClaim gives up to count pending entries from start which are idle for at
+// least minIdle milliseconds to the consumer. At most ten times count pending
+// entries are scanned, entries found deleted are removed.
+func (s *Stream) AutoClaim(groupName string, name string, minIdle int64, start ID, count int, justID bool, now int64) (ClaimResult, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return ClaimResult{}, err
+	}
+
+	owner := current.consumer(name, now)
+	result := ClaimResult{}
+	attempts := count * 10
+	candidates := []ID{}
+
+	// the IDs are collected first as claiming modifies the pending entries,
+	// along with the one following the last attempt to resume from
+	current.pending.Ascend(start, func(id ID, _ *pendingEntry) bool {
+		candidates = append(candidates, id)
+		return len(candidates) <= attempts
+	})
+
+	for i, id := range candidates {
+		if i == attempts || len(result.Entries) == count {
+			result.Next = id
+			break
+		}
+
+		entry, _ := current.pending.Get(id)
+		fields, exists := s.entries.Get(id)
+
+		if !exists {
+			current.acknowledge(id)
+			result.Deleted = append(result.Deleted, id)
+			continue
+		}
+
+		if now-entry.deliveryTime < minIdle {
+			continue
+		}
+
+		deliveryCount := entry.deliveryCount
+
+		if !justID {
+			deliveryCount++
+		}
+
+		entry = current.deliver(id, name, owner, now)
+		entry.deliveryCount = deliveryCount
+		owner.activeTime = now
+
+		result.Entries = append(result.Entries, Entry{ID: id, Fields: fields})
+		result.Pending = append(result.Pending, entry.export(id))
+	}
+
+	return result, nil
+}
+
+// Groups describes the consumer groups, sorted by name
+func (s *Stream) Groups() []GroupInfo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	groups := []GroupInfo{}
+
+	for _, name := range sortedNames(s.groups) {
+		groups = append(groups, s.groupInfo(name, s.groups[name]))
+	}
+
+	return groups
+}
+
+func (s *Stream) Group(name string) (GroupInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, err := s.group(name)
+
+	if err != nil {
+		return GroupInfo{}, err
+	}
+
+	return s.groupInfo(name, current), nil
+}
+
+func (s *Stream) groupInfo(name string, current *group) GroupInfo {
+	return GroupInfo{
+		Name:        name,
+		Consumers:   len(current.consumers),
+		Pending:     current.pending.Len(),
+		LastID:      current.lastID,
+		EntriesRead: current.entriesRead,
+		Lag:         s.lag(current),
+	}
+}
+
+// lag returns the number of entries the group did not deliver yet, -1 when it
+// can not be known
+func (s *Stream) lag(current *group) int64 {
+	if s.entriesAdded == 0 {
+		return 0
+	}
+
+	if current.entriesRead != -1 && !s.hasTombstones(current.lastID) {
+		return s.entriesAdded - current.entriesRead
+	}
+
+	entriesRead := s.entriesUpTo(current.lastID)
+
+	if entriesRead == -1 {
+		return -1
+	}
+
+	return s.entriesAdded - entriesRead
+}
+
+// Consumers describes the consumers of the group, sorted by name
+func (s *Stream) Consumers(groupName string) ([]ConsumerInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	consumers := []ConsumerInfo{}
+
+	for _, name := range sortedNames(current.consumers) {
+		consumer := current.consumers[name]
+
+		consumers = append(consumers, ConsumerInfo{
+			Name:       name,
+			Pending:    consumer.pending.Len(),
+			SeenTime:   consumer.seenTime,
+			ActiveTime: consumer.activeTime,
+		})
+	}
+
+	return consumers, nil
+}
+
+// RestoreConsumer adds a consumer described by info to the group, when it is
+// restored from a snapshot
+func (s *Stream) RestoreConsumer(groupName string, info ConsumerInfo) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return err
+	}
+
+	current.consumers[info.Name] = &consumer{
+		seenTime:   info.SeenTime,
+		activeTime: info.ActiveTime,
+		pending:    newIndex[*pendingEntry](),
+	}
+
+	return nil
+}
+
+// RestorePending adds a pending entry to the group, when it is restored from a
+// snapshot. Its consumer must be restored first.
+func (s *Stream) RestorePending(groupName string, pending PendingEntry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, err := s.group(groupName)
+
+	if err != nil {
+		return err
+	}
+
+	owner, found := current.consumers[pending.Consumer]
+
+	if !found {
+		return errors.New("Pending entry of unknown consumer " + pending.Consumer)
+	}
+
+	entry := &pendingEntry{
+		consumer:      pending.Consumer,
+		deliveryTime:  pending.DeliveryTime,
+		deliveryCount: pending.DeliveryCount,
+	}
+
+	current.pending.Set(pending.ID, entry)
+	owner.pending.Set(pending.ID, entry)
+
+	return nil
+}
+
+// sortedNames returns the keys of the map in lexicographic order
+func sortedNames[V any](values map[string]V) []string {
+	names := make([]string, 0, len(values))
+
+	for name := range values {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
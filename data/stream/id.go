@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ID identifies a stream entry, the unix time in milliseconds it was added at
+// followed by a sequence number for entries added in the same millisecond
+type ID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+var (
+	MinID = ID{}
+	MaxID = ID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+)
+
+var ErrInvalidID = errors.New("ERR Invalid stream ID specified as stream command argument")
+
+func (id ID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+func (id ID) Less(other ID) bool {
+	return id.Ms < other.Ms || (id.Ms == other.Ms && id.Seq < other.Seq)
+}
+
+func (id ID) IsZero() bool {
+	return id == MinID
+}
+
+// Next returns the smallest ID greater than id, false when id is MaxID
+func (id ID) Next() (ID, bool) {
+	switch {
+	case id.Seq < math.MaxUint64:
+		return ID{Ms: id.Ms, Seq: id.Seq + 1}, true
+	case id.Ms < math.MaxUint64:
+		return ID{Ms: id.Ms + 1}, true
+	}
+
+	return id, false
+}
+
+// Prev returns the largest ID lower than id, false when id is MinID
+func (id ID) Prev() (ID, bool) {
+	switch {
+	case id.Seq > 0:
+		return ID{Ms: id.Ms, Seq: id.Seq - 1}, true
+	case id.Ms > 0:
+		return ID{Ms: id.Ms - 1, Seq: math.MaxUint64}, true
+	}
+
+	return id, false
+}
+
+// ParseID parses an ID written as <ms>-<seq>, or as <ms> alone in which case
+// its sequence number is missingSeq
+func ParseID(value string, missingSeq uint64) (ID, error) {
+	ms, seq, hasSeq := strings.Cut(value, "-")
+
+	id := ID{Seq: missingSeq}
+	var err error
+
+	if id.Ms, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return id, ErrInvalidID
+	}
+
+	if hasSeq {
+		if id.Seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+			return id, ErrInvalidID
+		}
+	}
+
+	return id, nil
+}
+
+// ParseRangeID parses a bound of XRANGE: - and + are the smallest and largest
+// IDs, a missing sequence number is the smallest one for the start and the
+// largest one for the end, and a bound prefixed with ( is exclusive
+func ParseRangeID(value string, end bool) (ID, bool, error) {
+	switch value {
+	case "-":
+		return MinID, false, nil
+	case "+":
+		return MaxID, false, nil
+	}
+
+	exclusive := strings.HasPrefix(value, "(")
+	missingSeq := uint64(0)
+
+	if end {
+		missingSeq = math.MaxUint64
+	}
+
+	id, err := ParseID(strings.TrimPrefix(value, "("), missingSeq)
+
+	return id, exclusive, err
+}
+
+// NewID is the ID requested for an entry, either part of which may be
+// generated by Add
+type NewID struct {
+	ID ID
+	// * was given, the whole ID is generated
+	AutoMs bool
+	// <ms>-* was given, the sequence number is generated
+	AutoSeq bool
+}
+
+// ParseNewID parses the ID of XADD: *, <ms>-* or an explicit ID
+func ParseNewID(value string) (NewID, error) {
+	if value == "*" {
+		return NewID{AutoMs: true}, nil
+	}
+
+	if ms, found := strings.CutSuffix(value, "-*"); found {
+		id, err := ParseID(ms, 0)
+		return NewID{ID: id, AutoSeq: true}, err
+	}
+
+	id, err := ParseID(value, 0)
+	return NewID{ID: id}, err
+}
@@ -0,0 +1,241 @@
+package stream
+
+import (
+	"slices"
+	"sort"
+)
+
+// nodes hold up to this many items, like the listpacks of the radix tree redis
+// keeps stream entries in
+const nodeMaxItems = 128
+
+type item[V any] struct {
+	id    ID
+	value V
+}
+
+// index is an ordered map of IDs: a sorted sequence of nodes, each holding a
+// sorted run of items. Items added past the last one fill the last node, so
+// appending never moves items, and removing the first items of a stream drops
+// whole nodes.
+type index[V any] struct {
+	nodes  [][]item[V]
+	length int
+}
+
+func newIndex[V any]() *index[V] {
+	return &index[V]{}
+}
+
+func (x *index[V]) Len() int {
+	return x.length
+}
+
+// NodeCount returns the number of nodes holding the items
+func (x *index[V]) NodeCount() int {
+	return len(x.nodes)
+}
+
+func (x *index[V]) Get(id ID) (V, bool) {
+	n, i := x.seek(id)
+
+	if n < len(x.nodes) && x.nodes[n][i].id == id {
+		return x.nodes[n][i].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set adds the item, or replaces the value of the item with the same id
+func (x *index[V]) Set(id ID, value V) {
+	n, i := x.seek(id)
+
+	if n < len(x.nodes) && x.nodes[n][i].id == id {
+		x.nodes[n][i].value = value
+		return
+	}
+
+	x.length++
+
+	if n == len(x.nodes) {
+		if n > 0 && len(x.nodes[n-1]) < nodeMaxItems {
+			x.nodes[n-1] = append(x.nodes[n-1], item[V]{id, value})
+		} else {
+			x.nodes = append(x.nodes, []item[V]{{id, value}})
+		}
+
+		return
+	}
+
+	node := slices.Insert(x.nodes[n], i, item[V]{id, value})
+
+	if len(node) > nodeMaxItems {
+		half := len(node) / 2
+		x.nodes = slices.Insert(x.nodes, n+1, slices.Clone(node[half:]))
+		node = node[:half]
+	}
+
+	x.nodes[n] = node
+}
+
+func (x *index[V]) Delete(id ID) bool {
+	n, i := x.seek(id)
+
+	if n == len(x.nodes) || x.nodes[n][i].id != id {
+		return false
+	}
+
+	x.length--
+	node := x.nodes[n]
+
+	if i == 0 {
+		node = node[1:]
+	} else {
+		node = slices.Delete(node, i, i+1)
+	}
+
+	if len(node) == 0 {
+		x.nodes = slices.Delete(x.nodes, n, n+1)
+	} else {
+		x.nodes[n] = node
+	}
+
+	return true
+}
+
+func (x *index[V]) First() (ID, V, bool) {
+	if x.length == 0 {
+		var zero V
+		return ID{}, zero, false
+	}
+
+	first := x.nodes[0][0]
+	return first.id, first.value, true
+}
+
+func (x *index[V]) Last() (ID, V, bool) {
+	if x.length == 0 {
+		var zero V
+		return ID{}, zero, false
+	}
+
+	node := x.nodes[len(x.nodes)-1]
+	last := node[len(node)-1]
+	return last.id, last.value, true
+}
+
+// FirstNode returns the number of items of the first node and the id of its
+// last item
+func (x *index[V]) FirstNode() (int, ID) {
+	if len(x.nodes) == 0 {
+		return 0, ID{}
+	}
+
+	node := x.nodes[0]
+	return len(node), node[len(node)-1].id
+}
+
+// DeleteFirstNode removes the items of the first node
+func (x *index[V]) DeleteFirstNode() {
+	x.length -= len(x.nodes[0])
+	x.nodes = x.nodes[1:]
+}
+
+// CountUpTo returns the number of items whose id is not above id
+func (x *index[V]) CountUpTo(id ID) int {
+	n, i := x.seek(id)
+	count := i
+
+	for _, node := range x.nodes[:n] {
+		count += len(node)
+	}
+
+	if n < len(x.nodes) && x.nodes[n][i].id == id {
+		count++
+	}
+
+	return count
+}
+
+// Ascend calls fn on the items from the first one whose id is not below
+// from, in order, until fn returns false
+func (x *index[V]) Ascend(from ID, fn func(ID, V) bool) {
+	n, i := x.seek(from)
+
+	for ; n < len(x.nodes); n, i = n+1, 0 {
+		for _, current := range x.nodes[n][i:] {
+			if !fn(current.id, current.value) {
+				return
+			}
+		}
+	}
+}
+
+// Descend calls fn on the items from the last one whose id is not above
+// from, in reverse order, until fn returns false
+func (x *index[V]) Descend(from ID, fn func(ID, V) bool) {
+	n, i := x.seek(from)
+
+	// seek found the first item not below from, step back unless it is from
+	if n == len(x.nodes) || x.nodes[n][i].id != from {
+		if i > 0 {
+			i--
+		} else if n--; n >= 0 {
+			i = len(x.nodes[n]) - 1
+		}
+	}
+
+	for ; n >= 0; n-- {
+		for ; i >= 0; i-- {
+			current := x.nodes[n][i]
+
+			if !fn(current.id, current.value) {
+				return
+			}
+		}
+
+		if n > 0 {
+			i = len(x.nodes[n-1]) - 1
+		}
+	}
+}
+
+// seek returns the node and the position in that node of the first item whose
+// id is not below id, the node is past the last one when there is none
+func (x *index[V]) seek(id ID) (int, int) {
+	n := sort.Search(len(x.nodes), func(n int) bool {
+		node := x.nodes[n]
+		return !node[len(node)-1].id.Less(id)
+	})
+
+	if n == len(x.nodes) {
+		return n, 0
+	}
+
+	i := sort.Search(len(x.nodes[n]), func(i int) bool {
+		return !x.nodes[n][i].id.Less(id)
+	})
+
+	return n, i
+}
+
+// clone copies the index, values are copied with cloneValue
+func (x *index[V]) clone(cloneValue func(V) V) *index[V] {
+	cloned := &index[V]{
+		nodes:  make([][]item[V], 0, len(x.nodes)),
+		length: x.length,
+	}
+
+	for _, node := range x.nodes {
+		clonedNode := make([]item[V], len(node))
+
+		for i, current := range node {
+			clonedNode[i] = item[V]{current.id, cloneValue(current.value)}
+		}
+
+		cloned.nodes = append(cloned.nodes, clonedNode)
+	}
+
+	return cloned
+}
@@ -0,0 +1,350 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+)
+
+// trim strategies
+const (
+	TRIM_MAXLEN string = "MAXLEN"
+	TRIM_MINID  string = "MINID"
+)
+
+// approximate trimming removes at most this many entries unless given a limit
+const defaultTrimLimit = 100 * nodeMaxItems
+
+var (
+	ErrIDTooSmall = errors.New("ERR The ID specified in XADD is equal or smaller than the target stream top item")
+	ErrIDZero     = errors.New("ERR The ID specified in XADD must be greater than 0-0")
+	ErrExhausted  = errors.New("ERR The stream has exhausted the last possible ID, unable to add more items")
+)
+
+// Entry is a stream entry, its fields are stored as field value pairs. Entries
+// referenced by a consumer group after being deleted have nil Fields.
+type Entry struct {
+	ID     ID
+	Fields []string
+}
+
+// TrimOptions are the trimming options of XADD and XTRIM
+type TrimOptions struct {
+	// TRIM_MAXLEN or TRIM_MINID, empty to not trim
+	Strategy string
+	MaxLen   int64
+	MinID    ID
+	// only remove whole nodes, some entries past the threshold may remain
+	Approximate bool
+	// most entries removed when trimming approximately, zero for the default
+	Limit int64
+}
+
+// Stream is an append only log of entries ordered by ID, read by consumer
+// groups tracking which entries they delivered to their consumers
+type Stream struct {
+	entries *index[[]string]
+	lastID  ID
+	// largest ID deleted by XDEL
+	maxDeletedID ID
+	// number of entries ever added
+	entriesAdded int64
+	groups       map[string]*group
+	lock         *sync.RWMutex
+}
+
+func NewStream() *Stream {
+	return &Stream{
+		entries: newIndex[[]string](),
+		groups:  make(map[string]*group),
+		lock:    &sync.RWMutex{},
+	}
+}
+
+func (s *Stream) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.entries.Len()
+}
+
+// LastID returns the ID of the last entry ever added, which may be deleted
+func (s *Stream) LastID() ID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastID
+}
+
+// Add appends an entry, generating the parts of its ID requested by id. The
+// ID must be greater than the last one. now is the unix time in milliseconds.
+func (s *Stream) Add(id NewID, fields []string, now int64) (ID, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	next, err := s.nextID(id, now)
+
+	if err != nil {
+		return ID{}, err
+	}
+
+	s.entries.Set(next, fields)
+	s.lastID = next
+	s.entriesAdded++
+
+	return next, nil
+}
+
+func (s *Stream) nextID(id NewID, now int64) (ID, error) {
+	switch {
+	case id.AutoMs:
+		if uint64(now) > s.lastID.Ms {
+			return ID{Ms: uint64(now)}, nil
+		}
+
+		next, found := s.lastID.Next()
+
+		if !found {
+			return ID{}, ErrExhausted
+		}
+
+		return next, nil
+
+	case id.AutoSeq:
+		if id.ID.Ms > s.lastID.Ms {
+			return ID{Ms: id.ID.Ms}, nil
+		}
+
+		next, found := s.lastID.Next()
+
+		if id.ID.Ms < s.lastID.Ms || !found || next.Ms != id.ID.Ms {
+			return ID{}, ErrIDTooSmall
+		}
+
+		// 0-0 is never a valid ID, the first entry of 0-* is 0-1
+		return next, nil
+	}
+
+	if id.ID.IsZero() {
+		return ID{}, ErrIDZero
+	}
+
+	if !s.lastID.Less(id.ID) {
+		return ID{}, ErrIDTooSmall
+	}
+
+	return id.ID, nil
+}
+
+// Range returns up to count entries between start and end, both inclusive,
+// from end to start when reverse is set. A zero count returns every entry.
+func (s *Stream) Range(start ID, end ID, count int, reverse bool) []Entry {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.rangeWithoutLock(start, end, count, reverse)
+}
+
+func (s *Stream) rangeWithoutLock(start ID, end ID, count int, reverse bool) []Entry {
+	entries := []Entry{}
+
+	if end.Less(start) {
+		return entries
+	}
+
+	collect := func(id ID, fields []string) bool {
+		entries = append(entries, Entry{ID: id, Fields: fields})
+		return count <= 0 || len(entries) < count
+	}
+
+	if reverse {
+		s.entries.Descend(end, func(id ID, fields []string) bool {
+			return !id.Less(start) && collect(id, fields)
+		})
+	} else {
+		s.entries.Ascend(start, func(id ID, fields []string) bool {
+			return !end.Less(id) && collect(id, fields)
+		})
+	}
+
+	return entries
+}
+
+// Delete removes the entries, returns the number of entries removed
+func (s *Stream) Delete(ids ...ID) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deleted := 0
+
+	for _, id := range ids {
+		if !s.entries.Delete(id) {
+			continue
+		}
+
+		deleted++
+
+		if s.maxDeletedID.Less(id) {
+			s.maxDeletedID = id
+		}
+	}
+
+	return deleted
+}
+
+// Trim removes the first entries according to options, returns the number of
+// entries removed
+func (s *Stream) Trim(options TrimOptions) int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if options.Strategy == "" {
+		return 0
+	}
+
+	limit := options.Limit
+
+	if options.Approximate && limit == 0 {
+		limit = defaultTrimLimit
+	}
+
+	removed := int64(0)
+
+	for s.entries.Len() > 0 {
+		first, _, _ := s.entries.First()
+
+		if options.Strategy == TRIM_MAXLEN && int64(s.entries.Len()) <= options.MaxLen {
+			break
+		}
+
+		if options.Strategy == TRIM_MINID && !first.Less(options.MinID) {
+			break
+		}
+
+		if !options.Approximate {
+			s.entries.Delete(first)
+			removed++
+			continue
+		}
+
+		nodeLength, nodeLast := s.entries.FirstNode()
+
+		if options.Strategy == TRIM_MAXLEN && int64(s.entries.Len()-nodeLength) < options.MaxLen {
+			break
+		}
+
+		if options.Strategy == TRIM_MINID && !nodeLast.Less(options.MinID) {
+			break
+		}
+
+		if removed+int64(nodeLength) > limit {
+			break
+		}
+
+		s.entries.DeleteFirstNode()
+		removed += int64(nodeLength)
+	}
+
+	return removed
+}
+
+// SetID sets the last ID of the stream along with its counters, when it is
+// restored from a snapshot
+func (s *Stream) SetID(lastID ID, entriesAdded int64, maxDeletedID ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastID = lastID
+	s.entriesAdded = entriesAdded
+	s.maxDeletedID = maxDeletedID
+}
+
+// Info describes a stream for XINFO STREAM
+type Info struct {
+	Length       int
+	Nodes        int
+	LastID       ID
+	MaxDeletedID ID
+	EntriesAdded int64
+	// ID of the first entry, zero when the stream is empty
+	FirstID ID
+	Groups  int
+	// first and last entries, nil when the stream is empty
+	FirstEntry *Entry
+	LastEntry  *Entry
+}
+
+func (s *Stream) Info() Info {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	info := Info{
+		Length:       s.entries.Len(),
+		Nodes:        s.entries.NodeCount(),
+		LastID:       s.lastID,
+		MaxDeletedID: s.maxDeletedID,
+		EntriesAdded: s.entriesAdded,
+		Groups:       len(s.groups),
+	}
+
+	if firstID, fields, found := s.entries.First(); found {
+		info.FirstID = firstID
+		info.FirstEntry = &Entry{ID: firstID, Fields: fields}
+	}
+
+	if lastID, fields, found := s.entries.Last(); found {
+		info.LastEntry = &Entry{ID: lastID, Fields: fields}
+	}
+
+	return info
+}
+
+// entriesUpTo returns the number of entries ever added up to id, -1 when it
+// can not be known because entries were deleted in the meantime
+func (s *Stream) entriesUpTo(id ID) int64 {
+	if !id.Less(s.lastID) || s.entries.Len() == 0 {
+		return s.entriesAdded
+	}
+
+	if s.hasTombstones(MinID) {
+		return -1
+	}
+
+	// entries trimmed from the front were all added before the first one
+	trimmed := s.entriesAdded - int64(s.entries.Len())
+	return trimmed + int64(s.entries.CountUpTo(id))
+}
+
+// hasTombstones reports whether entries past from, still within the stream,
+// were deleted
+func (s *Stream) hasTombstones(from ID) bool {
+	first, _, found := s.entries.First()
+
+	if !found || s.maxDeletedID.IsZero() {
+		return false
+	}
+
+	if from.Less(first) {
+		from = first
+	}
+
+	return !s.maxDeletedID.Less(from)
+}
+
+func (s *Stream) Clone() *Stream {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	cloned := &Stream{
+		// entries are never modified, their fields can be shared
+		entries:      s.entries.clone(func(fields []string) []string { return fields }),
+		lastID:       s.lastID,
+		maxDeletedID: s.maxDeletedID,
+		entriesAdded: s.entriesAdded,
+		groups:       make(map[string]*group, len(s.groups)),
+		lock:         &sync.RWMutex{},
+	}
+
+	for name, group := range s.groups {
+		cloned.groups[name] = group.clone()
+	}
+
+	return cloned
+}
@@ -0,0 +1,233 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustAdd(t *testing.T, s *Stream, id string, fields ...string) ID {
+	newID, err := ParseNewID(id)
+	assert.NoError(t, err)
+
+	added, err := s.Add(newID, fields, 1000)
+	assert.NoError(t, err)
+
+	return added
+}
+
+func ids(entries []Entry) []string {
+	result := []string{}
+
+	for _, entry := range entries {
+		result = append(result, entry.ID.String())
+	}
+
+	return result
+}
+
+func TestAdd(t *testing.T) {
+	s := NewStream()
+
+	assert.Equal(t, "1000-0", mustAdd(t, s, "*", "a", "1").String())
+	assert.Equal(t, "1000-1", mustAdd(t, s, "*", "b", "2").String())
+	assert.Equal(t, "1000-2", mustAdd(t, s, "1000-*", "c", "3").String())
+	assert.Equal(t, "2000-0", mustAdd(t, s, "2000-*", "d", "4").String())
+	assert.Equal(t, "2000-5", mustAdd(t, s, "2000-5", "e", "5").String())
+
+	_, err := s.Add(NewID{ID: ID{Ms: 2000, Seq: 5}}, []string{"f", "6"}, 1000)
+	assert.Equal(t, ErrIDTooSmall, err)
+
+	_, err = s.Add(NewID{ID: ID{Ms: 1000}, AutoSeq: true}, []string{"f", "6"}, 1000)
+	assert.Equal(t, ErrIDTooSmall, err)
+
+	_, err = NewStream().Add(NewID{}, []string{"f", "6"}, 1000)
+	assert.Equal(t, ErrIDZero, err)
+
+	assert.Equal(t, "0-1", mustAdd(t, NewStream(), "0-*", "a", "1").String())
+	assert.Equal(t, 5, s.Len())
+	assert.Equal(t, ID{Ms: 2000, Seq: 5}, s.LastID())
+}
+
+func TestParseID(t *testing.T) {
+	id, exclusive, err := ParseRangeID("(5", true)
+	assert.NoError(t, err)
+	assert.True(t, exclusive)
+	assert.Equal(t, ID{Ms: 5, Seq: MaxID.Seq}, id)
+
+	id, _, _ = ParseRangeID("5", false)
+	assert.Equal(t, ID{Ms: 5}, id)
+
+	_, _, err = ParseRangeID("5-x", false)
+	assert.Equal(t, ErrInvalidID, err)
+
+	_, err = ParseNewID("-1")
+	assert.Equal(t, ErrInvalidID, err)
+}
+
+func TestRangeAndDelete(t *testing.T) {
+	s := NewStream()
+
+	// enough entries to fill several nodes
+	for i := 0; i < 3*nodeMaxItems; i++ {
+		mustAdd(t, s, "*", "i", "v")
+	}
+
+	all := s.Range(MinID, MaxID, 0, false)
+	assert.Len(t, all, 3*nodeMaxItems)
+	assert.Equal(t, "1000-0", all[0].ID.String())
+
+	assert.Equal(t, []string{"1000-127", "1000-128", "1000-129"}, ids(s.Range(ID{Ms: 1000, Seq: 127}, MaxID, 3, false)))
+	assert.Equal(t, []string{"1000-383", "1000-382"}, ids(s.Range(MinID, MaxID, 2, true)))
+	assert.Equal(t, []string{"1000-2", "1000-1"}, ids(s.Range(ID{Ms: 1000, Seq: 1}, ID{Ms: 1000, Seq: 2}, 0, true)))
+	assert.Empty(t, s.Range(ID{Ms: 2000}, ID{Ms: 1000}, 0, false))
+
+	assert.Equal(t, 2, s.Delete(ID{Ms: 1000, Seq: 1}, ID{Ms: 1000, Seq: 200}, ID{Ms: 5000}))
+	assert.Equal(t, []string{"1000-0", "1000-2"}, ids(s.Range(MinID, ID{Ms: 1000, Seq: 2}, 0, false)))
+	assert.Equal(t, 3*nodeMaxItems-2, s.Len())
+	assert.Equal(t, ID{Ms: 1000, Seq: 200}, s.Info().MaxDeletedID)
+}
+
+func TestTrim(t *testing.T) {
+	s := NewStream()
+
+	for i := 0; i < 3*nodeMaxItems; i++ {
+		mustAdd(t, s, "*", "i", "v")
+	}
+
+	// approximate trimming keeps whole nodes
+	assert.Equal(t, int64(nodeMaxItems), s.Trim(TrimOptions{Strategy: TRIM_MAXLEN, MaxLen: 200, Approximate: true}))
+	assert.Equal(t, 2*nodeMaxItems, s.Len())
+
+	assert.Equal(t, int64(2*nodeMaxItems-10), s.Trim(TrimOptions{Strategy: TRIM_MAXLEN, MaxLen: 10}))
+	assert.Equal(t, 10, s.Len())
+
+	assert.Equal(t, int64(4), s.Trim(TrimOptions{Strategy: TRIM_MINID, MinID: ID{Ms: 1000, Seq: 378}}))
+	assert.Equal(t, "1000-378", s.Range(MinID, MaxID, 1, false)[0].ID.String())
+
+	assert.Equal(t, int64(0), s.Trim(TrimOptions{Strategy: TRIM_MAXLEN, MaxLen: 1, Approximate: true}))
+}
+
+func TestGroups(t *testing.T) {
+	s := NewStream()
+
+	for i := 0; i < 5; i++ {
+		mustAdd(t, s, "*", "i", "v")
+	}
+
+	assert.NoError(t, s.CreateGroup("g", MinID, 0))
+	assert.Equal(t, ErrGroupExists, s.CreateGroup("g", MinID, 0))
+
+	entries, err := s.ReadGroup("g", "alice", MinID, true, 2, false, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1000-0", "1000-1"}, ids(entries))
+
+	entries, _ = s.ReadGroup("g", "bob", MinID, true, 0, false, 200)
+	assert.Equal(t, []string{"1000-2", "1000-3", "1000-4"}, ids(entries))
+
+	groups := s.Groups()
+	assert.Equal(t, int64(0), groups[0].Lag)
+	assert.Equal(t, 5, groups[0].Pending)
+
+	// history redelivers pending entries
+	entries, _ = s.ReadGroup("g", "alice", MinID, false, 0, false, 300)
+	assert.Equal(t, []string{"1000-0", "1000-1"}, ids(entries))
+
+	acknowledged, _ := s.Ack("g", ID{Ms: 1000}, ID{Ms: 1000}, ID{Ms: 9000})
+	assert.Equal(t, 1, acknowledged)
+
+	summary, _ := s.PendingSummary("g")
+	assert.Equal(t, 4, summary.Count)
+	assert.Equal(t, ID{Ms: 1000, Seq: 1}, summary.First)
+	assert.Equal(t, []ConsumerInfo{{Name: "alice", Pending: 1}, {Name: "bob", Pending: 3}}, summary.Consumers)
+
+	pending, _ := s.Pending("g", PendingQuery{Start: MinID, End: MaxID, MinIdle: 150, Now: 400})
+	assert.Equal(t, []PendingEntry{
+		{ID: ID{Ms: 1000, Seq: 2}, Consumer: "bob", DeliveryTime: 200, DeliveryCount: 1},
+		{ID: ID{Ms: 1000, Seq: 3}, Consumer: "bob", DeliveryTime: 200, DeliveryCount: 1},
+		{ID: ID{Ms: 1000, Seq: 4}, Consumer: "bob", DeliveryTime: 200, DeliveryCount: 1},
+	}, pending)
+
+	_, err = s.ReadGroup("missing", "alice", MinID, true, 0, false, 0)
+	assert.Equal(t, ErrNoGroup, err)
+
+	// entries deleted before the last delivered one do not affect the lag,
+	// the ones after it make it unknown
+	s.Delete(ID{Ms: 1000, Seq: 2})
+	mustAdd(t, s, "*", "i", "v")
+	assert.Equal(t, int64(1), s.Groups()[0].Lag)
+
+	s.Delete(mustAdd(t, s, "*", "i", "v"))
+	assert.Equal(t, int64(-1), s.Groups()[0].Lag)
+
+	deleted, _ := s.DeleteConsumer("g", "bob")
+	assert.Equal(t, 3, deleted)
+
+	summary, _ = s.PendingSummary("g")
+	assert.Equal(t, 1, summary.Count)
+}
+
+func TestClaim(t *testing.T) {
+	s := NewStream()
+
+	for i := 0; i < 5; i++ {
+		mustAdd(t, s, "*", "i", "v")
+	}
+
+	s.CreateGroup("g", MinID, 0)
+	s.ReadGroup("g", "alice", MinID, true, 0, false, 100)
+
+	all := []ID{{Ms: 1000}, {Ms: 1000, Seq: 1}}
+
+	// not idle for long enough
+	result, err := s.Claim("g", "bob", all, ClaimOptions{MinIdle: 500, DeliveryTime: -1, RetryCount: -1, Now: 200})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Entries)
+
+	result, _ = s.Claim("g", "bob", all, ClaimOptions{MinIdle: 50, DeliveryTime: -1, RetryCount: -1, Now: 200})
+	assert.Equal(t, []string{"1000-0", "1000-1"}, ids(result.Entries))
+	assert.Equal(t, PendingEntry{ID: ID{Ms: 1000}, Consumer: "bob", DeliveryTime: 200, DeliveryCount: 2}, result.Pending[0])
+
+	s.Delete(ID{Ms: 1000, Seq: 2})
+
+	result, _ = s.AutoClaim("g", "carol", 0, MinID, 2, true, 300)
+	assert.Equal(t, []string{"1000-0", "1000-1"}, ids(result.Entries))
+	assert.Equal(t, int64(2), result.Pending[0].DeliveryCount)
+	assert.Equal(t, ID{Ms: 1000, Seq: 2}, result.Next)
+
+	result, _ = s.AutoClaim("g", "carol", 0, result.Next, 2, false, 300)
+	assert.Equal(t, []ID{{Ms: 1000, Seq: 2}}, result.Deleted)
+	assert.Equal(t, []string{"1000-3", "1000-4"}, ids(result.Entries))
+	assert.Equal(t, MinID, result.Next)
+
+	consumers, _ := s.Consumers("g")
+	assert.Equal(t, []ConsumerInfo{
+		{Name: "alice", Pending: 0, SeenTime: 100, ActiveTime: 100},
+		{Name: "bob", Pending: 0, SeenTime: 200, ActiveTime: 200},
+		{Name: "carol", Pending: 4, SeenTime: 300, ActiveTime: 300},
+	}, consumers)
+
+	// forced claims of entries which are not pending
+	s.Ack("g", ID{Ms: 1000, Seq: 3})
+	result, _ = s.Claim("g", "alice", []ID{{Ms: 1000, Seq: 3}}, ClaimOptions{DeliveryTime: -1, RetryCount: 7, Force: true, Now: 400})
+	assert.Equal(t, int64(7), result.Pending[0].DeliveryCount)
+}
+
+func TestClone(t *testing.T) {
+	s := NewStream()
+	mustAdd(t, s, "*", "i", "v")
+	s.CreateGroup("g", MinID, 0)
+	s.ReadGroup("g", "alice", MinID, true, 0, false, 100)
+
+	cloned := s.Clone()
+	s.Ack("g", ID{Ms: 1000})
+	mustAdd(t, s, "*", "i", "v")
+
+	assert.Equal(t, 1, cloned.Len())
+
+	summary, _ := cloned.PendingSummary("g")
+	assert.Equal(t, 1, summary.Count)
+
+	entries, _ := cloned.ReadGroup("g", "alice", MinID, false, 0, false, 200)
+	assert.Equal(t, []string{"1000-0"}, ids(entries))
+}
@@ -0,0 +1,421 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+)
+
+var ErrStreamRequired = errors.New("ERR The XGROUP subcommand requires the key to exist. Note that for CREATE you may want to use the MKSTREAM option to create an empty stream automatically.")
+
+// getStream returns the stream stored at key, creating an empty one when
+// create is set. The returned stream is nil when the key does not exist and
+// create is not set. Unlike other collections, empty streams are kept.
+func (s *Store) getStream(key string, create bool) (*stream.Stream, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		if !create {
+			return nil, nil
+		}
+
+		return stream.NewStream(), nil
+	}
+
+	existStream, isStreamType := data.(*stream.Stream)
+
+	if !isStreamType {
+		return nil, ErrWrongType
+	}
+
+	return s.own(key, existStream).(*stream.Stream), nil
+}
+
+// groupStream returns the stream stored at key, stream.ErrNoGroup when it
+// does not exist as its groups do not either
+func (s *Store) groupStream(key string) (*stream.Stream, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err == nil && existStream == nil {
+		return nil, stream.ErrNoGroup
+	}
+
+	return existStream, err
+}
+
+// XAdd appends an entry to the stream, creating it when it does not exist and
+// create is set. Returns the ID of the entry, false when nothing was added.
+func (s *Store) XAdd(key string, id stream.NewID, fields []string, create bool) (stream.ID, bool, error) {
+	existStream, err := s.getStream(key, create)
+
+	if err != nil || existStream == nil {
+		return stream.ID{}, false, err
+	}
+
+	added, err := existStream.Add(id, fields, time.Now().UnixMilli())
+
+	if err != nil {
+		return stream.ID{}, false, err
+	}
+
+	s.setWithLock(key, existStream)
+	s.signalReady(key)
+
+	return added, true, nil
+}
+
+// XTrim removes the first entries of the stream according to options, returns
+// the number of entries removed and the length of the stream
+func (s *Store) XTrim(key string, options stream.TrimOptions) (int64, int, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return 0, 0, err
+	}
+
+	removed := existStream.Trim(options)
+
+	if removed > 0 {
+		s.touch(key)
+	}
+
+	return removed, existStream.Len(), nil
+}
+
+func (s *Store) XLen(key string) (int, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return 0, err
+	}
+
+	return existStream.Len(), nil
+}
+
+// XRange returns up to count entries between start and end, both inclusive,
+// in reverse order when reverse is set. A zero count returns every entry.
+func (s *Store) XRange(key string, start stream.ID, end stream.ID, count int, reverse bool) ([]stream.Entry, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return []stream.Entry{}, err
+	}
+
+	return existStream.Range(start, end, count, reverse), nil
+}
+
+// XRead returns up to count entries added after id, nil when the stream does
+// not exist. A zero count returns every entry.
+func (s *Store) XRead(key string, id stream.ID, count int) ([]stream.Entry, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return nil, err
+	}
+
+	start, found := id.Next()
+
+	if !found {
+		return []stream.Entry{}, nil
+	}
+
+	return existStream.Range(start, stream.MaxID, count, false), nil
+}
+
+// XLastID returns the ID of the last entry added to the stream, zero when it
+// does not exist
+func (s *Store) XLastID(key string) (stream.ID, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return stream.ID{}, err
+	}
+
+	return existStream.LastID(), nil
+}
+
+// XDel removes the entries, returns the number of entries removed
+func (s *Store) XDel(key string, ids ...stream.ID) (int, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return 0, err
+	}
+
+	deleted := existStream.Delete(ids...)
+
+	if deleted > 0 {
+		s.touch(key)
+	}
+
+	return deleted, nil
+}
+
+// XGroupCreate creates a consumer group delivering the entries after id, or
+// after the last entry when last is set. The stream is created when it does
+// not exist and create is set.
+func (s *Store) XGroupCreate(key string, group string, id stream.ID, last bool, entriesRead int64, create bool) error {
+	existStream, err := s.getStream(key, create)
+
+	if err != nil {
+		return err
+	}
+
+	if existStream == nil {
+		return ErrStreamRequired
+	}
+
+	if last {
+		id = existStream.LastID()
+	}
+
+	if err := existStream.CreateGroup(group, id, entriesRead); err != nil {
+		return err
+	}
+
+	s.setWithLock(key, existStream)
+	return nil
+}
+
+// XGroupSetID makes the group deliver the entries after id, or after the last
+// entry when last is set
+func (s *Store) XGroupSetID(key string, group string, id stream.ID, last bool, entriesRead int64) error {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return err
+	}
+
+	if existStream == nil {
+		return ErrStreamRequired
+	}
+
+	if last {
+		id = existStream.LastID()
+	}
+
+	if err := existStream.SetGroupID(group, id, entriesRead); err != nil {
+		return err
+	}
+
+	s.touch(key)
+	return nil
+}
+
+func (s *Store) XGroupDestroy(key string, group string) (bool, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return false, err
+	}
+
+	if existStream == nil {
+		return false, ErrStreamRequired
+	}
+
+	destroyed := existStream.DestroyGroup(group)
+
+	if destroyed {
+		s.touch(key)
+	}
+
+	return destroyed, nil
+}
+
+// XGroupCreateConsumer adds a consumer to the group, returns false when it exists
+func (s *Store) XGroupCreateConsumer(key string, group string, consumer string) (bool, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return false, err
+	}
+
+	if existStream == nil {
+		return false, ErrStreamRequired
+	}
+
+	created, err := existStream.CreateConsumer(group, consumer, time.Now().UnixMilli())
+
+	if created {
+		s.touch(key)
+	}
+
+	return created, err
+}
+
+// XGroupDelConsumer removes a consumer from the group, returns the number of
+// entries it had pending
+func (s *Store) XGroupDelConsumer(key string, group string, consumer string) (int, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if existStream == nil {
+		return 0, ErrStreamRequired
+	}
+
+	pending, err := existStream.DeleteConsumer(group, consumer)
+
+	if err == nil {
+		s.touch(key)
+	}
+
+	return pending, err
+}
+
+// XReadGroup delivers up to count entries of the stream to the consumer of
+// the group, see stream.ReadGroup
+func (s *Store) XReadGroup(key string, group string, consumer string, id stream.ID, fromLast bool, count int, noAck bool) ([]stream.Entry, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := existStream.ReadGroup(group, consumer, id, fromLast, count, noAck, time.Now().UnixMilli())
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.touch(key)
+	return entries, nil
+}
+
+// XGroup describes the consumer group
+func (s *Store) XGroup(key string, group string) (stream.GroupInfo, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return stream.GroupInfo{}, err
+	}
+
+	return existStream.Group(group)
+}
+
+// XAck acknowledges the entries, returns the number of entries which were
+// pending. Missing streams and groups have no pending entries.
+func (s *Store) XAck(key string, group string, ids ...stream.ID) (int, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil || existStream == nil {
+		return 0, err
+	}
+
+	acknowledged, err := existStream.Ack(group, ids...)
+
+	if errors.Is(err, stream.ErrNoGroup) {
+		return 0, nil
+	}
+
+	if acknowledged > 0 {
+		s.touch(key)
+	}
+
+	return acknowledged, err
+}
+
+func (s *Store) XPendingSummary(key string, group string) (stream.PendingSummary, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return stream.PendingSummary{}, err
+	}
+
+	return existStream.PendingSummary(group)
+}
+
+// XPending returns the pending entries of the group selected by query, its
+// current time is set here
+func (s *Store) XPending(key string, group string, query stream.PendingQuery) ([]stream.PendingEntry, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query.Now = time.Now().UnixMilli()
+	return existStream.Pending(group, query)
+}
+
+// XClaim gives the pending entries to the consumer, see stream.Claim. The
+// current time of options is set here.
+func (s *Store) XClaim(key string, group string, consumer string, ids []stream.ID, options stream.ClaimOptions) (stream.ClaimResult, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return stream.ClaimResult{}, err
+	}
+
+	options.Now = time.Now().UnixMilli()
+	result, err := existStream.Claim(group, consumer, ids, options)
+
+	if err == nil {
+		s.touch(key)
+	}
+
+	return result, err
+}
+
+// XAutoClaim gives the pending entries idle for at least minIdle milliseconds
+// to the consumer, see stream.AutoClaim
+func (s *Store) XAutoClaim(key string, group string, consumer string, minIdle int64, start stream.ID, count int, justID bool) (stream.ClaimResult, error) {
+	existStream, err := s.groupStream(key)
+
+	if err != nil {
+		return stream.ClaimResult{}, err
+	}
+
+	result, err := existStream.AutoClaim(group, consumer, minIdle, start, count, justID, time.Now().UnixMilli())
+
+	if err == nil {
+		s.touch(key)
+	}
+
+	return result, err
+}
+
+func (s *Store) XInfoStream(key string) (stream.Info, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return stream.Info{}, err
+	}
+
+	if existStream == nil {
+		return stream.Info{}, ErrNoSuchKey
+	}
+
+	return existStream.Info(), nil
+}
+
+func (s *Store) XInfoGroups(key string) ([]stream.GroupInfo, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existStream == nil {
+		return nil, ErrNoSuchKey
+	}
+
+	return existStream.Groups(), nil
+}
+
+func (s *Store) XInfoConsumers(key string, group string) ([]stream.ConsumerInfo, error) {
+	existStream, err := s.getStream(key, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existStream == nil {
+		return nil, ErrNoSuchKey
+	}
+
+	return existStream.Consumers(group)
+}
@@ -0,0 +1,44 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXAdd(t *testing.T) {
+	store := NewStore()
+
+	_, added, err := store.XAdd("events", stream.NewID{AutoMs: true}, []string{"a", "1"}, false)
+	assert.NoError(t, err)
+	assert.False(t, added)
+	assert.False(t, store.Exists("events"))
+
+	id, added, _ := store.XAdd("events", stream.NewID{ID: stream.ID{Ms: 5}}, []string{"a", "1"}, true)
+	assert.True(t, added)
+	assert.Equal(t, stream.ID{Ms: 5}, id)
+
+	// streams are kept when their entries are removed
+	deleted, _ := store.XDel("events", id)
+	assert.Equal(t, 1, deleted)
+	assert.True(t, store.Exists("events"))
+
+	store.Set("name", "value", "", 0)
+	_, _, err = store.XAdd("name", stream.NewID{AutoMs: true}, []string{"a", "1"}, true)
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestXGroup(t *testing.T) {
+	store := NewStore()
+
+	assert.Equal(t, ErrStreamRequired, store.XGroupCreate("events", "g", stream.MinID, false, 0, false))
+	assert.NoError(t, store.XGroupCreate("events", "g", stream.MinID, false, 0, true))
+
+	_, err := store.XReadGroup("missing", "g", "alice", stream.MinID, true, 0, false)
+	assert.Equal(t, stream.ErrNoGroup, err)
+
+	acknowledged, err := store.XAck("events", "missing", stream.ID{Ms: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, acknowledged)
+}
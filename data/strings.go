@@ -0,0 +1,290 @@
+package data
+
+import (
+	"errors"
+	"time"
+)
+
+// set conditions
+const (
+	SET_ALWAYS string = ""
+	SET_NX     string = "NX"
+	SET_XX     string = "XX"
+)
+
+// SetOptions are the options of SET
+type SetOptions struct {
+	// SET_NX to only set missing keys, SET_XX to only set existing ones
+	Condition string
+	// unix time in milliseconds of the new timeout, zero for none
+	ExpireAt int64
+	// keep the current timeout, ExpireAt is then ignored
+	KeepTTL bool
+	// the previous value is requested, it must be a string
+	Get bool
+}
+
+// SetWithOptions sets the string value of the key according to options.
+// Returns the previous value, nil when there was none, and whether the value was set.
+func (s *Store) SetWithOptions(key string, value string, options SetOptions) (interface{}, bool, error) {
+	previous, exists := s.setLockAndGet(key)
+
+	if value, isString := stringValue(previous); isString {
+		previous = value
+	} else if options.Get && exists {
+		return nil, false, ErrWrongType
+	}
+
+	if (options.Condition == SET_NX && exists) || (options.Condition == SET_XX && !exists) {
+		return previous, false, nil
+	}
+
+	if options.KeepTTL {
+		s.setWithLock(key, value)
+	} else {
+		s.setWithExpiry(key, value, options.ExpireAt)
+	}
+
+	return previous, true, nil
+}
+
+// strings are limited to 512mb, like the default proto-max-bulk-len of redis
+const MAX_STRING_LENGTH = 512 * 1024 * 1024
+
+var (
+	ErrStringTooLong = errors.New("ERR string exceeds maximum allowed size (proto-max-bulk-len)")
+	ErrOffsetRange   = errors.New("ERR offset is out of range")
+)
+
+// stringValue returns value as a string when it holds one. Strings are stored
+// as Go strings, or as byte slices once they were modified in place.
+func stringValue(value interface{}) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case []byte:
+		return string(typedValue), true
+	}
+
+	return "", false
+}
+
+// getString returns the string stored at key, an empty string when it does not exist
+func (s *Store) getString(key string) (string, bool, error) {
+	value, found, err := s.Get(key)
+
+	if err != nil || !found {
+		return "", found, err
+	}
+
+	return value.(string), true, nil
+}
+
+// getBytes returns the string stored at key as a byte slice, nil when it does
+// not exist. The slice may be the stored value, it must not be modified.
+func (s *Store) getBytes(key string) ([]byte, bool, error) {
+	value, found := s.setLockAndGet(key)
+
+	if !found {
+		return nil, false, nil
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return []byte(typedValue), true, nil
+	case []byte:
+		return typedValue, true, nil
+	}
+
+	return nil, true, ErrWrongType
+}
+
+// updateBytes runs update on the string stored at key, grown with zero bytes
+// to at least size bytes, and stores the result unless update fails. The
+// string is modified in place while holding the write lock, its timeout is
+// kept and a missing key is created.
+func (s *Store) updateBytes(key string, size int, update func(buffer []byte) ([]byte, error)) error {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	buffer, _, err := s.bytesWithoutLock(key)
+
+	if err != nil {
+		return err
+	}
+
+	if size > len(buffer) {
+		buffer = append(buffer, make([]byte, size-len(buffer))...)
+	}
+
+	buffer, err = update(buffer)
+
+	if err != nil {
+		return err
+	}
+
+	s.putWithoutLock(key, buffer)
+	s.recordAccess(key)
+	s.touch(key)
+
+	return nil
+}
+
+// bytesWithoutLock returns the string stored at key as a byte slice which can
+// be modified in place, for callers holding the write lock. Expired keys are
+// removed first.
+func (s *Store) bytesWithoutLock(key string) ([]byte, bool, error) {
+	if s.isExpired(key, time.Now().UnixMilli()) {
+		s.removeWithoutLock(key)
+	}
+
+	switch value := s.data[key].(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []byte(value), true, nil
+	case []byte:
+		return s.ownWithoutLock(key, value).([]byte), true, nil
+	}
+
+	return nil, true, ErrWrongType
+}
+
+// Append appends value to the string, keeping its timeout. Returns the new length.
+func (s *Store) Append(key string, value string) (int, error) {
+	length := 0
+
+	err := s.updateBytes(key, 0, func(buffer []byte) ([]byte, error) {
+		if len(buffer)+len(value) > MAX_STRING_LENGTH {
+			return nil, ErrStringTooLong
+		}
+
+		buffer = append(buffer, value...)
+		length = len(buffer)
+
+		return buffer, nil
+	})
+
+	return length, err
+}
+
+func (s *Store) StrLen(key string) (int, error) {
+	current, _, err := s.getBytes(key)
+	return len(current), err
+}
+
+// GetRange returns the substring between the offsets start and end, both
+// inclusive. Negative offsets count from the end of the string.
+func (s *Store) GetRange(key string, start int, end int) (string, error) {
+	current, _, err := s.getString(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	start, end, found := normalizeRange(start, end, len(current))
+
+	if !found {
+		return "", nil
+	}
+
+	return current[start : end+1], nil
+}
+
+// normalizeRange converts the inclusive offsets start and end, negative ones
+// counting from length, to offsets within length. Returns false when the
+// range is empty.
+func normalizeRange(start int, end int, length int) (int, int, bool) {
+	if start < 0 && end < 0 && start > end {
+		return 0, 0, false
+	}
+
+	if start < 0 {
+		start = max(start+length, 0)
+	}
+
+	if end < 0 {
+		end = max(end+length, 0)
+	}
+
+	end = min(end, length-1)
+
+	if length == 0 || start > end {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// SetRange overwrites the string from offset with value, padding it with zero
+// bytes when it is shorter than offset. Returns the new length.
+func (s *Store) SetRange(key string, offset int, value string) (int, error) {
+	if offset < 0 {
+		return 0, ErrOffsetRange
+	}
+
+	// nothing to write, a missing key is not created
+	if value == "" {
+		current, _, err := s.getBytes(key)
+		return len(current), err
+	}
+
+	// written so that a huge offset can not overflow
+	if offset > MAX_STRING_LENGTH-len(value) {
+		return 0, ErrStringTooLong
+	}
+
+	length := 0
+
+	err := s.updateBytes(key, offset+len(value), func(buffer []byte) ([]byte, error) {
+		copy(buffer[offset:], value)
+		length = len(buffer)
+
+		return buffer, nil
+	})
+
+	return length, err
+}
+
+// MGet returns the values of the keys, nil for missing keys and for keys
+// which do not hold a string
+func (s *Store) MGet(keys ...string) []interface{} {
+	values := make([]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		value, _, err := s.Get(key)
+
+		if err != nil {
+			value = nil
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// MSet sets the string values of the key value pairs, removing their timeouts.
+// When onlyNew is set nothing is done if any of the keys exists. The keys are
+// checked and set under the write lock, so no other write sees some of them
+// set and not the others. Returns whether the values were set.
+func (s *Store) MSet(pairs []string, onlyNew bool) bool {
+	s.wl.Lock()
+	defer s.wl.Unlock()
+
+	now := time.Now().UnixMilli()
+
+	if onlyNew {
+		for i := 0; i < len(pairs); i += 2 {
+			if _, exists := s.data[pairs[i]]; exists && !s.isExpired(pairs[i], now) {
+				return false
+			}
+		}
+	}
+
+	for i := 0; i < len(pairs); i += 2 {
+		s.setWithoutLock(pairs[i], pairs[i+1], 0)
+	}
+
+	return true
+}
@@ -0,0 +1,214 @@
+package data
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWithOptions(t *testing.T) {
+	store := NewStore()
+	expireAt := time.Now().Add(time.Hour).UnixMilli()
+
+	_, set, _ := store.SetWithOptions("key", "a", SetOptions{Condition: SET_XX})
+	assert.False(t, set)
+
+	_, set, _ = store.SetWithOptions("key", "a", SetOptions{Condition: SET_NX, ExpireAt: expireAt})
+	assert.True(t, set)
+
+	previous, set, _ := store.SetWithOptions("key", "b", SetOptions{Condition: SET_NX, Get: true})
+	assert.False(t, set)
+	assert.Equal(t, "a", previous)
+
+	previous, _, _ = store.SetWithOptions("key", "c", SetOptions{KeepTTL: true, Get: true})
+	assert.Equal(t, "a", previous)
+	assert.Equal(t, expireAt, store.ExpireTime("key"))
+
+	store.SetWithOptions("key", "d", SetOptions{})
+	assert.Equal(t, TTL_NO_EXPIRE, store.ExpireTime("key"))
+
+	store.Rpush("list", "x")
+	_, _, err := store.SetWithOptions("list", "value", SetOptions{Get: true})
+	assert.Equal(t, ErrWrongType, err)
+	assert.Equal(t, TYPE_LIST, store.Type("list"))
+}
+
+func TestRanges(t *testing.T) {
+	store := NewStore()
+	store.Set("key", "This is a string", "", 0)
+
+	cases := []struct {
+		start    int
+		end      int
+		expected string
+	}{
+		{0, 3, "This"},
+		{-3, -1, "ing"},
+		{0, -1, "This is a string"},
+		{10, 100, "string"},
+		{5, 3, ""},
+		{-1, -5, ""},
+	}
+
+	for _, c := range cases {
+		value, _ := store.GetRange("key", c.start, c.end)
+		assert.Equal(t, c.expected, value)
+	}
+
+	length, _ := store.SetRange("padded", 3, "end")
+	value, _, _ := store.Get("padded")
+
+	assert.Equal(t, 6, length)
+	assert.Equal(t, "\x00\x00\x00end", value)
+
+	length, _ = store.SetRange("missing", 3, "")
+	assert.Equal(t, 0, length)
+	assert.False(t, store.Exists("missing"))
+
+	_, err := store.SetRange("padded", math.MaxInt, "x")
+	assert.Equal(t, ErrStringTooLong, err)
+
+	_, err = store.SetRange("padded", MAX_STRING_LENGTH, "x")
+	assert.Equal(t, ErrStringTooLong, err)
+
+	value, _, _ = store.Get("padded")
+	assert.Equal(t, "\x00\x00\x00end", value)
+}
+
+func TestLCS(t *testing.T) {
+	common, matches, err := lcs("ohmytext", "mynewtext")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "mytext", common)
+	assert.Equal(t, []LCSMatch{
+		{First: LCSRange{4, 7}, Second: LCSRange{5, 8}},
+		{First: LCSRange{2, 3}, Second: LCSRange{0, 1}},
+	}, matches)
+
+	common, matches, _ = lcs("", "abc")
+	assert.Equal(t, "", common)
+	assert.Empty(t, matches)
+}
+
+func TestIncrBy(t *testing.T) {
+	store := NewStore()
+
+	value, _ := store.IncrBy("counter", 5)
+	assert.Equal(t, int64(5), value)
+
+	store.Set("counter", strconv.FormatInt(math.MaxInt64-1, 10), "EX", 100)
+	value, _ = store.IncrBy("counter", 1)
+	assert.Equal(t, int64(math.MaxInt64), value)
+	assert.Greater(t, store.TTL("counter"), int64(0))
+
+	_, err := store.IncrBy("counter", 1)
+	assert.Equal(t, ErrOverflow, err)
+
+	for _, invalid := range []string{"01", " 1", "+1", "1.5", ""} {
+		store.Set("invalid", invalid, "", 0)
+		_, err = store.IncrBy("invalid", 1)
+		assert.Equal(t, ErrNotInteger, err, invalid)
+	}
+}
+
+func TestIncrByFloat(t *testing.T) {
+	store := NewStore()
+	store.Set("float", "10.50", "", 0)
+
+	value, _ := store.IncrByFloat("float", "0.1")
+	assert.Equal(t, "10.6", value)
+
+	store.Set("float", "5.0e3", "", 0)
+	value, _ = store.IncrByFloat("float", "2.0e2")
+	assert.Equal(t, "5200", value)
+
+	// sums are computed and rounded like redis, not in float64
+	value, _ = store.IncrByFloat("sum", "0.1")
+	assert.Equal(t, "0.1", value)
+	value, _ = store.IncrByFloat("sum", "0.2")
+	assert.Equal(t, "0.3", value)
+
+	value, _ = store.IncrByFloat("sum", "-0.3")
+	assert.Equal(t, "0", value)
+
+	value, _ = store.IncrByFloat("sum", "1e20")
+	assert.Equal(t, "100000000000000000000", value)
+
+	value, _ = store.IncrByFloat("sum", "-1e20")
+	assert.Equal(t, "0", value)
+
+	value, _ = store.IncrByFloat("sum", "3.0e-5")
+	assert.Equal(t, "0.00003", value)
+
+	_, err := store.IncrByFloat("float", "inf")
+	assert.Equal(t, ErrNaNOrInf, err)
+
+	store.Set("big", "1.7e308", "", 0)
+	_, err = store.IncrByFloat("big", "1.7e308")
+	assert.Equal(t, ErrNaNOrInf, err)
+
+	for _, invalid := range []string{"abc", "nan", " 1", "", "1_0", "0x_1p4"} {
+		_, err = store.IncrByFloat("float", invalid)
+		assert.Equal(t, ErrNotFloat, err, invalid)
+	}
+
+	store.Set("float", "abc", "", 0)
+	_, err = store.IncrByFloat("float", "1")
+	assert.Equal(t, ErrNotFloat, err)
+}
+
+func TestIncrByFloatAcceptsWhatParseFloatDoes(t *testing.T) {
+	cases := []struct {
+		increment string
+		value     string
+	}{
+		{"0x1p4", "17"},
+		{"0X1P-2", "1.25"},
+		{"0x1.8p1", "4"},
+		{"+.5", "1.5"},
+		{"5.", "6"},
+		{"1E3", "1001"},
+		{"-0", "1"},
+		{"0012", "13"},
+	}
+
+	for _, c := range cases {
+		store := NewStore()
+		store.Set("float", "1", "", 0)
+
+		value, err := store.IncrByFloat("float", c.increment)
+		assert.NoError(t, err, c.increment)
+		assert.Equal(t, c.value, value, c.increment)
+	}
+
+	// the stored value is parsed the same way as the increment
+	store := NewStore()
+	store.Set("hex", "0x1p4", "", 0)
+	value, _ := store.IncrByFloat("hex", "1")
+	assert.Equal(t, "17", value)
+}
+
+func TestMultiKey(t *testing.T) {
+	store := NewStore()
+	store.Set("a", "1", "EX", 100)
+	store.Rpush("list", "x")
+
+	assert.Equal(t, []interface{}{"1", nil, nil}, store.MGet("a", "list", "missing"))
+
+	watch := NewWatch()
+	store.Watch(watch, "b")
+
+	assert.False(t, store.MSet([]string{"b", "2", "a", "3"}, true))
+	assert.False(t, store.Exists("b"))
+	assert.False(t, watch.IsDirty())
+
+	assert.True(t, store.MSet([]string{"a", "3", "b", "2"}, false))
+	assert.Equal(t, []interface{}{"3", "2"}, store.MGet("a", "b"))
+	assert.Equal(t, int64(-1), store.TTL("a"))
+	assert.True(t, watch.IsDirty())
+
+	assert.True(t, store.MSet([]string{"c", "4", "d", "5"}, true))
+}
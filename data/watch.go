@@ -0,0 +1,101 @@
+package data
+
+import "sync/atomic"
+
+// Watch is the set of keys a client watches for its next transaction, it
+// turns dirty as soon as one of them is modified. The keys may belong to
+// several stores, as the client can watch keys of different databases.
+type Watch struct {
+	// keys by store, only used by the watching client
+	keys  map[*Store][]string
+	dirty atomic.Bool
+}
+
+func NewWatch() *Watch {
+	return &Watch{
+		keys: make(map[*Store][]string),
+	}
+}
+
+// Watch adds keys of the store to the watch
+func (s *Store) Watch(watch *Watch, keys ...string) {
+	s.wtl.Lock()
+	defer s.wtl.Unlock()
+
+	for _, key := range keys {
+		watchers, found := s.watchers[key]
+
+		if !found {
+			watchers = make(map[*Watch]struct{})
+			s.watchers[key] = watchers
+		}
+
+		if _, watched := watchers[watch]; watched {
+			continue
+		}
+
+		watchers[watch] = struct{}{}
+		watch.keys[s] = append(watch.keys[s], key)
+	}
+}
+
+// Unwatch forgets every key of the watch
+func (w *Watch) Unwatch() {
+	for store, keys := range w.keys {
+		store.unwatch(w, keys)
+	}
+
+	w.keys = make(map[*Store][]string)
+	w.dirty.Store(false)
+}
+
+func (s *Store) unwatch(watch *Watch, keys []string) {
+	s.wtl.Lock()
+	defer s.wtl.Unlock()
+
+	for _, key := range keys {
+		delete(s.watchers[key], watch)
+
+		if len(s.watchers[key]) == 0 {
+			delete(s.watchers, key)
+		}
+	}
+}
+
+// IsDirty reports whether a watched key was modified since it was watched
+func (w *Watch) IsDirty() bool {
+	return w.dirty.Load()
+}
+
+// touch marks the watches of key as dirty, it must be called by every
+// modification of a key, its value or its timeout
+func (s *Store) touch(key string) {
+	s.wtl.Lock()
+	defer s.wtl.Unlock()
+
+	for watch := range s.watchers[key] {
+		watch.dirty.Store(true)
+	}
+}
+
+// touchExisting marks the watches of the keys which exist in one of values
+// as dirty, for operations replacing the whole keyspace. It must be called
+// while holding the write lock.
+func (s *Store) touchExisting(values ...map[string]interface{}) {
+	s.wtl.Lock()
+	defer s.wtl.Unlock()
+
+	for key, watchers := range s.watchers {
+		for _, keyspace := range values {
+			if _, exists := keyspace[key]; !exists {
+				continue
+			}
+
+			for watch := range watchers {
+				watch.dirty.Store(true)
+			}
+
+			break
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	store := NewStore()
+	store.SAdd("members", "a", "b")
+
+	watch := NewWatch()
+	store.Watch(watch, "members", "missing")
+
+	// removing nothing does not modify the key
+	store.SRem("members", "c")
+	assert.False(t, watch.IsDirty())
+
+	store.SRem("members", "a")
+	assert.True(t, watch.IsDirty())
+
+	watch.Unwatch()
+	assert.False(t, watch.IsDirty())
+
+	store.Watch(watch, "missing")
+	store.Rpush("missing", "x")
+	assert.True(t, watch.IsDirty())
+}
+
+func TestUnwatch(t *testing.T) {
+	store, other := NewStore(), NewStore()
+
+	first, second := NewWatch(), NewWatch()
+	store.Watch(first, "key")
+	other.Watch(first, "key")
+	store.Watch(second, "key")
+	first.Unwatch()
+
+	store.Rpush("key", "x")
+	other.Rpush("key", "x")
+
+	assert.False(t, first.IsDirty())
+	assert.True(t, second.IsDirty())
+	assert.Empty(t, other.watchers)
+}
+
+func TestFlushAndSwap(t *testing.T) {
+	store, other := NewStore(), NewStore()
+	store.Rpush("present", "x")
+	other.Rpush("swapped", "y")
+
+	watch := NewWatch()
+	store.Watch(watch, "missing")
+
+	// flushing an empty key does not modify it
+	store.Flush()
+	assert.False(t, watch.IsDirty())
+	assert.Equal(t, 0, store.Size())
+
+	store.Watch(watch, "swapped")
+	store.Swap(other)
+
+	assert.True(t, watch.IsDirty())
+	assert.True(t, store.Exists("swapped"))
+	assert.Equal(t, 0, other.Size())
+}
+
+func TestMove(t *testing.T) {
+	store, other := NewStore(), NewStore()
+	store.Set("key", "value", "EX", 100)
+	other.Set("taken", "value", "", 0)
+	store.Set("taken", "value", "", 0)
+
+	assert.True(t, store.Move("key", other))
+	assert.False(t, store.Exists("key"))
+	assert.Greater(t, other.ExpireTime("key"), int64(0))
+
+	assert.False(t, store.Move("taken", other))
+	assert.False(t, store.Move("missing", other))
+	assert.True(t, store.Exists("taken"))
+}
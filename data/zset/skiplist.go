@@ -0,0 +1,251 @@
+package zset
+
+import (
+	"math/rand"
+)
+
+const (
+	skiplistMaxLevel = 32
+	// probability of a node having one more level
+	skiplistP = 0.25
+)
+
+type skiplistLevel struct {
+	forward *skiplistNode
+	// number of nodes the forward link jumps over, used to compute ranks
+	span int
+}
+
+type skiplistNode struct {
+	member   string
+	score    float64
+	backward *skiplistNode
+	level    []skiplistLevel
+}
+
+// skiplist keeps members ordered by score, then by member, and answers rank
+// queries in O(log n) thanks to the spans stored along the links
+type skiplist struct {
+	header *skiplistNode
+	tail   *skiplistNode
+	length int
+	level  int
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{
+		header: &skiplistNode{level: make([]skiplistLevel, skiplistMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomLevel() int {
+	level := 1
+
+	for level < skiplistMaxLevel && rand.Float64() < skiplistP {
+		level++
+	}
+
+	return level
+}
+
+// less reports whether the node sorts before the given score and member
+func (n *skiplistNode) less(score float64, member string) bool {
+	return n.score < score || (n.score == score && n.member < member)
+}
+
+// greater reports whether the node sorts after the given score and member
+func (n *skiplistNode) greater(score float64, member string) bool {
+	return n.score > score || (n.score == score && n.member > member)
+}
+
+// insert adds a member which must not be in the list already
+func (sl *skiplist) insert(score float64, member string) *skiplistNode {
+	update := make([]*skiplistNode, skiplistMaxLevel)
+	rank := make([]int, skiplistMaxLevel)
+
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
+
+		for current.level[i].forward != nil && current.level[i].forward.less(score, member) {
+			rank[i] += current.level[i].span
+			current = current.level[i].forward
+		}
+
+		update[i] = current
+	}
+
+	level := randomLevel()
+
+	if level > sl.level {
+		for i := sl.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = sl.header
+			update[i].level[i].span = sl.length
+		}
+
+		sl.level = level
+	}
+
+	node := &skiplistNode{
+		member: member,
+		score:  score,
+		level:  make([]skiplistLevel, level),
+	}
+
+	for i := 0; i < level; i++ {
+		node.level[i].forward = update[i].level[i].forward
+		update[i].level[i].forward = node
+
+		node.level[i].span = update[i].level[i].span - (rank[0] - rank[i])
+		update[i].level[i].span = (rank[0] - rank[i]) + 1
+	}
+
+	// untouched levels now jump over one more node
+	for i := level; i < sl.level; i++ {
+		update[i].level[i].span++
+	}
+
+	if update[0] != sl.header {
+		node.backward = update[0]
+	}
+
+	if node.level[0].forward != nil {
+		node.level[0].forward.backward = node
+	} else {
+		sl.tail = node
+	}
+
+	sl.length++
+	return node
+}
+
+// delete removes the member with the given score, returns whether it was found
+func (sl *skiplist) delete(score float64, member string) bool {
+	update := make([]*skiplistNode, skiplistMaxLevel)
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		for current.level[i].forward != nil && current.level[i].forward.less(score, member) {
+			current = current.level[i].forward
+		}
+
+		update[i] = current
+	}
+
+	node := current.level[0].forward
+
+	if node == nil || node.score != score || node.member != member {
+		return false
+	}
+
+	sl.deleteNode(node, update)
+	return true
+}
+
+func (sl *skiplist) deleteNode(node *skiplistNode, update []*skiplistNode) {
+	for i := 0; i < sl.level; i++ {
+		if update[i].level[i].forward == node {
+			update[i].level[i].span += node.level[i].span - 1
+			update[i].level[i].forward = node.level[i].forward
+		} else {
+			update[i].level[i].span--
+		}
+	}
+
+	if node.level[0].forward != nil {
+		node.level[0].forward.backward = node.backward
+	} else {
+		sl.tail = node.backward
+	}
+
+	for sl.level > 1 && sl.header.level[sl.level-1].forward == nil {
+		sl.level--
+	}
+
+	sl.length--
+}
+
+// rank returns the 1 based position of the member, 0 when it is not found
+func (sl *skiplist) rank(score float64, member string) int {
+	rank := 0
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		for current.level[i].forward != nil && !current.level[i].forward.greater(score, member) {
+			rank += current.level[i].span
+			current = current.level[i].forward
+		}
+
+		if current != sl.header && current.score == score && current.member == member {
+			return rank
+		}
+	}
+
+	return 0
+}
+
+// byRank returns the node at the 1 based position, nil when out of range
+func (sl *skiplist) byRank(rank int) *skiplistNode {
+	if rank < 1 || rank > sl.length {
+		return nil
+	}
+
+	traversed := 0
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		for current.level[i].forward != nil && traversed+current.level[i].span <= rank {
+			traversed += current.level[i].span
+			current = current.level[i].forward
+		}
+
+		if traversed == rank {
+			return current
+		}
+	}
+
+	return nil
+}
+
+// firstMatching returns the first node for which beforeRange is false, as long
+// as it is not past the range
+func (sl *skiplist) firstMatching(beforeRange func(*skiplistNode) bool, afterRange func(*skiplistNode) bool) *skiplistNode {
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		for current.level[i].forward != nil && beforeRange(current.level[i].forward) {
+			current = current.level[i].forward
+		}
+	}
+
+	node := current.level[0].forward
+
+	if node == nil || afterRange(node) {
+		return nil
+	}
+
+	return node
+}
+
+// lastMatching returns the last node for which afterRange is false, as long
+// as it is not before the range
+func (sl *skiplist) lastMatching(beforeRange func(*skiplistNode) bool, afterRange func(*skiplistNode) bool) *skiplistNode {
+	current := sl.header
+
+	for i := sl.level - 1; i >= 0; i-- {
+		for current.level[i].forward != nil && !afterRange(current.level[i].forward) {
+			current = current.level[i].forward
+		}
+	}
+
+	if current == sl.header || beforeRange(current) {
+		return nil
+	}
+
+	return current
+}
@@ -0,0 +1,414 @@
+package zset
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+)
+
+// Add flags
+const (
+	ADD_NX = 1 << iota
+	ADD_XX
+	ADD_GT
+	ADD_LT
+	ADD_INCR
+)
+
+// Add results
+const (
+	ADD_RESULT_NOP = iota
+	ADD_RESULT_ADDED
+	ADD_RESULT_UPDATED
+)
+
+var ErrNaN = errors.New("ERR resulting score is not a number (NaN)")
+
+type Entry struct {
+	Member string
+	Score  float64
+}
+
+// ScoreRange is a range of scores, each end being inclusive unless marked exclusive
+type ScoreRange struct {
+	Min          float64
+	Max          float64
+	MinExclusive bool
+	MaxExclusive bool
+}
+
+func (r ScoreRange) aboveMin(score float64) bool {
+	if r.MinExclusive {
+		return score > r.Min
+	}
+
+	return score >= r.Min
+}
+
+func (r ScoreRange) belowMax(score float64) bool {
+	if r.MaxExclusive {
+		return score < r.Max
+	}
+
+	return score <= r.Max
+}
+
+func (r ScoreRange) isEmpty() bool {
+	return r.Min > r.Max || (r.Min == r.Max && (r.MinExclusive || r.MaxExclusive))
+}
+
+// LexBound is an end of a lexicographical range, Infinity is -1 for the
+// lowest possible string ("-"), 1 for the highest one ("+") and 0 otherwise
+type LexBound struct {
+	Value     string
+	Exclusive bool
+	Infinity  int
+}
+
+type LexRange struct {
+	Min LexBound
+	Max LexBound
+}
+
+func (r LexRange) aboveMin(member string) bool {
+	switch {
+	case r.Min.Infinity < 0:
+		return true
+	case r.Min.Infinity > 0:
+		return false
+	case r.Min.Exclusive:
+		return member > r.Min.Value
+	}
+
+	return member >= r.Min.Value
+}
+
+func (r LexRange) belowMax(member string) bool {
+	switch {
+	case r.Max.Infinity > 0:
+		return true
+	case r.Max.Infinity < 0:
+		return false
+	case r.Max.Exclusive:
+		return member < r.Max.Value
+	}
+
+	return member <= r.Max.Value
+}
+
+// SortedSet keeps members with a score, ordered by score and then member
+type SortedSet struct {
+	scores   map[string]float64
+	skiplist *skiplist
+	// the members in the order ZSCAN visits them
+	index *scan.Index
+	lock  *sync.RWMutex
+}
+
+func NewSortedSet() *SortedSet {
+	return &SortedSet{
+		scores:   make(map[string]float64),
+		skiplist: newSkiplist(),
+		index:    scan.NewIndex(),
+		lock:     &sync.RWMutex{},
+	}
+}
+
+func (z *SortedSet) IsEmpty() bool {
+	return z.Len() == 0
+}
+
+func (z *SortedSet) Len() int {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+	return len(z.scores)
+}
+
+// Add adds the member or updates its score according to the flags. With
+// ADD_INCR the score is added to the current one. Returns the resulting score
+// and what was done.
+func (z *SortedSet) Add(member string, score float64, flags int) (float64, int, error) {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	current, exists := z.scores[member]
+
+	if exists {
+		if flags&ADD_NX != 0 {
+			return current, ADD_RESULT_NOP, nil
+		}
+
+		if flags&ADD_INCR != 0 {
+			score += current
+
+			if math.IsNaN(score) {
+				return current, ADD_RESULT_NOP, ErrNaN
+			}
+		}
+
+		if (flags&ADD_GT != 0 && score <= current) || (flags&ADD_LT != 0 && score >= current) {
+			return current, ADD_RESULT_NOP, nil
+		}
+
+		if score == current {
+			return current, ADD_RESULT_NOP, nil
+		}
+
+		z.skiplist.delete(current, member)
+		z.skiplist.insert(score, member)
+		z.scores[member] = score
+
+		return score, ADD_RESULT_UPDATED, nil
+	}
+
+	if flags&ADD_XX != 0 {
+		return 0, ADD_RESULT_NOP, nil
+	}
+
+	z.skiplist.insert(score, member)
+	z.scores[member] = score
+	z.index.Add(member)
+
+	return score, ADD_RESULT_ADDED, nil
+}
+
+// Remove removes the member, returns whether it was a member
+func (z *SortedSet) Remove(member string) bool {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	score, exists := z.scores[member]
+
+	if !exists {
+		return false
+	}
+
+	z.skiplist.delete(score, member)
+	delete(z.scores, member)
+	z.index.Remove(member)
+
+	return true
+}
+
+func (z *SortedSet) Score(member string) (float64, bool) {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	score, exists := z.scores[member]
+	return score, exists
+}
+
+// Rank returns the 0 based position of the member, from the highest score
+// when reverse is set
+func (z *SortedSet) Rank(member string, reverse bool) (int, bool) {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	score, exists := z.scores[member]
+
+	if !exists {
+		return 0, false
+	}
+
+	rank := z.skiplist.rank(score, member) - 1
+
+	if reverse {
+		return z.skiplist.length - 1 - rank, true
+	}
+
+	return rank, true
+}
+
+// RangeByRank returns the members between the 0 based positions start and
+// stop, both inclusive and already normalized to the size of the set
+func (z *SortedSet) RangeByRank(start int, stop int, reverse bool) []Entry {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	entries := []Entry{}
+
+	if start < 0 || start > stop || start >= z.skiplist.length {
+		return entries
+	}
+
+	stop = min(stop, z.skiplist.length-1)
+
+	var node *skiplistNode
+
+	if reverse {
+		node = z.skiplist.byRank(z.skiplist.length - start)
+	} else {
+		node = z.skiplist.byRank(start + 1)
+	}
+
+	for i := start; i <= stop && node != nil; i++ {
+		entries = append(entries, Entry{Member: node.member, Score: node.score})
+		node = next(node, reverse)
+	}
+
+	return entries
+}
+
+// RangeByScore returns the members within the score range, skipping the first
+// offset of them and returning at most count when count is not negative
+func (z *SortedSet) RangeByScore(scoreRange ScoreRange, reverse bool, offset int, count int) []Entry {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	if scoreRange.isEmpty() {
+		return []Entry{}
+	}
+
+	beforeRange := func(node *skiplistNode) bool { return !scoreRange.aboveMin(node.score) }
+	afterRange := func(node *skiplistNode) bool { return !scoreRange.belowMax(node.score) }
+
+	return z.collect(beforeRange, afterRange, reverse, offset, count)
+}
+
+// RangeByLex returns the members within the lexicographical range, which is
+// only meaningful when every member has the same score
+func (z *SortedSet) RangeByLex(lexRange LexRange, reverse bool, offset int, count int) []Entry {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	beforeRange := func(node *skiplistNode) bool { return !lexRange.aboveMin(node.member) }
+	afterRange := func(node *skiplistNode) bool { return !lexRange.belowMax(node.member) }
+
+	return z.collect(beforeRange, afterRange, reverse, offset, count)
+}
+
+func (z *SortedSet) collect(beforeRange func(*skiplistNode) bool, afterRange func(*skiplistNode) bool, reverse bool, offset int, count int) []Entry {
+	entries := []Entry{}
+
+	var node *skiplistNode
+
+	if reverse {
+		node = z.skiplist.lastMatching(beforeRange, afterRange)
+	} else {
+		node = z.skiplist.firstMatching(beforeRange, afterRange)
+	}
+
+	for ; node != nil && offset > 0; offset-- {
+		node = next(node, reverse)
+	}
+
+	for node != nil && count != 0 {
+		if (reverse && beforeRange(node)) || (!reverse && afterRange(node)) {
+			break
+		}
+
+		entries = append(entries, Entry{Member: node.member, Score: node.score})
+		node = next(node, reverse)
+		count--
+	}
+
+	return entries
+}
+
+// CountByScore returns the number of members within the score range
+func (z *SortedSet) CountByScore(scoreRange ScoreRange) int {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	if scoreRange.isEmpty() {
+		return 0
+	}
+
+	beforeRange := func(node *skiplistNode) bool { return !scoreRange.aboveMin(node.score) }
+	afterRange := func(node *skiplistNode) bool { return !scoreRange.belowMax(node.score) }
+
+	return z.count(beforeRange, afterRange)
+}
+
+// CountByLex returns the number of members within the lexicographical range
+func (z *SortedSet) CountByLex(lexRange LexRange) int {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	beforeRange := func(node *skiplistNode) bool { return !lexRange.aboveMin(node.member) }
+	afterRange := func(node *skiplistNode) bool { return !lexRange.belowMax(node.member) }
+
+	return z.count(beforeRange, afterRange)
+}
+
+// count uses the ranks of both ends of the range, so it does not walk the range
+func (z *SortedSet) count(beforeRange func(*skiplistNode) bool, afterRange func(*skiplistNode) bool) int {
+	first := z.skiplist.firstMatching(beforeRange, afterRange)
+
+	if first == nil {
+		return 0
+	}
+
+	last := z.skiplist.lastMatching(beforeRange, afterRange)
+
+	if last == nil {
+		return 0
+	}
+
+	return z.skiplist.rank(last.score, last.member) - z.skiplist.rank(first.score, first.member) + 1
+}
+
+// Pop removes and returns up to count members with the lowest scores, or the
+// highest ones when reverse is set
+func (z *SortedSet) Pop(count int, reverse bool) []Entry {
+	entries := z.RangeByRank(0, count-1, reverse)
+
+	for _, entry := range entries {
+		z.Remove(entry.Member)
+	}
+
+	return entries
+}
+
+// Entries returns every member in order
+func (z *SortedSet) Entries() []Entry {
+	return z.RangeByRank(0, math.MaxInt-1, false)
+}
+
+// Members returns every member in order, without scores
+func (z *SortedSet) Members() []string {
+	members := []string{}
+
+	for _, entry := range z.Entries() {
+		members = append(members, entry.Member)
+	}
+
+	return members
+}
+
+// Scan returns up to count members not visited yet by the iteration at cursor
+// along with their scores, see scan.Scan for the cursor guarantees
+func (z *SortedSet) Scan(cursor uint64, count int) ([]Entry, uint64) {
+	z.lock.RLock()
+	defer z.lock.RUnlock()
+
+	members, nextCursor := z.index.Scan(cursor, count)
+	entries := make([]Entry, 0, len(members))
+
+	for _, member := range members {
+		entries = append(entries, Entry{Member: member, Score: z.scores[member]})
+	}
+
+	return entries, nextCursor
+}
+
+// Clone returns a copy of the sorted set which shares nothing with the original
+func (z *SortedSet) Clone() *SortedSet {
+	clone := NewSortedSet()
+
+	for _, entry := range z.Entries() {
+		clone.Add(entry.Member, entry.Score, 0)
+	}
+
+	return clone
+}
+
+func next(node *skiplistNode, reverse bool) *skiplistNode {
+	if reverse {
+		return node.backward
+	}
+
+	return node.level[0].forward
+}
@@ -0,0 +1,130 @@
+package zset
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRanksStayConsistent(t *testing.T) {
+	sortedSet := NewSortedSet()
+	expected := map[string]float64{}
+
+	for i := 0; i < 1000; i++ {
+		member := fmt.Sprintf("member:%d", rand.Intn(300))
+		score := float64(rand.Intn(50))
+
+		if rand.Intn(4) == 0 {
+			sortedSet.Remove(member)
+			delete(expected, member)
+			continue
+		}
+
+		sortedSet.Add(member, score, 0)
+		expected[member] = score
+	}
+
+	ordered := []Entry{}
+
+	for member, score := range expected {
+		ordered = append(ordered, Entry{Member: member, Score: score})
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Score == ordered[j].Score {
+			return ordered[i].Member < ordered[j].Member
+		}
+
+		return ordered[i].Score < ordered[j].Score
+	})
+
+	assert.Equal(t, sortedSet.Entries(), ordered)
+
+	for position, entry := range ordered {
+		rank, found := sortedSet.Rank(entry.Member, false)
+
+		assert.True(t, found)
+		assert.Equal(t, rank, position)
+
+		rank, _ = sortedSet.Rank(entry.Member, true)
+		assert.Equal(t, rank, len(ordered)-1-position)
+	}
+}
+
+func TestRangeByScore(t *testing.T) {
+	sortedSet := NewSortedSet()
+
+	for i := 1; i <= 10; i++ {
+		sortedSet.Add(fmt.Sprintf("m%02d", i), float64(i), 0)
+	}
+
+	scoreRange := ScoreRange{Min: 3, Max: 7, MinExclusive: true}
+
+	assert.Equal(t, sortedSet.RangeByScore(scoreRange, false, 1, 2), []Entry{
+		{Member: "m05", Score: 5},
+		{Member: "m06", Score: 6},
+	})
+	assert.Equal(t, sortedSet.RangeByScore(scoreRange, true, 0, -1)[0], Entry{Member: "m07", Score: 7})
+	assert.Equal(t, sortedSet.CountByScore(scoreRange), 4)
+	assert.Equal(t, sortedSet.CountByScore(ScoreRange{Min: 11, Max: 20}), 0)
+}
+
+func TestRangeByLex(t *testing.T) {
+	sortedSet := NewSortedSet()
+
+	for _, member := range []string{"a", "b", "c", "d", "e"} {
+		sortedSet.Add(member, 0, 0)
+	}
+
+	lexRange := LexRange{
+		Min: LexBound{Value: "b"},
+		Max: LexBound{Value: "d", Exclusive: true},
+	}
+
+	assert.Equal(t, sortedSet.Members(), []string{"a", "b", "c", "d", "e"})
+	assert.Equal(t, sortedSet.RangeByLex(lexRange, false, 0, -1), []Entry{{Member: "b"}, {Member: "c"}})
+	assert.Equal(t, sortedSet.CountByLex(LexRange{Min: LexBound{Infinity: -1}, Max: LexBound{Infinity: 1}}), 5)
+}
+
+func TestAddFlags(t *testing.T) {
+	sortedSet := NewSortedSet()
+
+	_, result, _ := sortedSet.Add("a", 5, ADD_XX)
+	assert.Equal(t, result, ADD_RESULT_NOP)
+
+	_, result, _ = sortedSet.Add("a", 5, ADD_NX)
+	assert.Equal(t, result, ADD_RESULT_ADDED)
+
+	_, result, _ = sortedSet.Add("a", 3, ADD_GT)
+	assert.Equal(t, result, ADD_RESULT_NOP)
+
+	score, result, _ := sortedSet.Add("a", 2, ADD_INCR)
+	assert.Equal(t, result, ADD_RESULT_UPDATED)
+	assert.Equal(t, score, 7.0)
+}
+
+func TestScanFollowsChanges(t *testing.T) {
+	sortedSet := NewSortedSet()
+
+	for i := 0; i < 100; i++ {
+		sortedSet.Add(fmt.Sprintf("member:%d", i), float64(i), 0)
+	}
+
+	sortedSet.Add("member:0", 5, ADD_INCR)
+	sortedSet.Remove("member:99")
+	sortedSet.Pop(1, true)
+	clone := sortedSet.Clone()
+	clone.Remove("member:0")
+
+	entries, cursor := sortedSet.Scan(0, 1000)
+	assert.ElementsMatch(t, sortedSet.Entries(), entries)
+	assert.Contains(t, entries, Entry{Member: "member:0", Score: 5})
+	assert.Equal(t, uint64(0), cursor)
+
+	entries, _ = clone.Scan(0, 1000)
+	assert.ElementsMatch(t, clone.Entries(), entries)
+	assert.Len(t, entries, 97)
+}
@@ -0,0 +1,387 @@
+package data
+
+import (
+	"math"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+)
+
+// aggregate functions for ZUNIONSTORE / ZINTERSTORE
+const (
+	AGGREGATE_SUM string = "SUM"
+	AGGREGATE_MIN string = "MIN"
+	AGGREGATE_MAX string = "MAX"
+)
+
+// getSortedSet returns the sorted set stored at key, creating an empty one when
+// create is set. The returned set is nil when the key does not exist and create is not set.
+func (s *Store) getSortedSet(key string, create bool) (*zset.SortedSet, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		if !create {
+			return nil, nil
+		}
+
+		return zset.NewSortedSet(), nil
+	}
+
+	existSet, isSortedSetType := data.(*zset.SortedSet)
+
+	if !isSortedSetType {
+		return nil, ErrWrongType
+	}
+
+	return s.own(key, existSet).(*zset.SortedSet), nil
+}
+
+// ZAdd adds the members or updates their scores according to the zset.ADD_* flags.
+// Returns the number of added members and the number of updated ones.
+func (s *Store) ZAdd(key string, entries []zset.Entry, flags int) (int, int, error) {
+	existSet, err := s.getSortedSet(key, true)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	added, updated := 0, 0
+
+	for _, entry := range entries {
+		_, result, err := existSet.Add(entry.Member, entry.Score, flags)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		switch result {
+		case zset.ADD_RESULT_ADDED:
+			added++
+		case zset.ADD_RESULT_UPDATED:
+			updated++
+		}
+	}
+
+	// with XX nothing may have been added to a new key
+	if !existSet.IsEmpty() {
+		s.setWithLock(key, existSet)
+	}
+
+	return added, updated, nil
+}
+
+// ZIncrBy adds increment to the score of the member. Returns the new score, or
+// nil when the flags prevented the update.
+func (s *Store) ZIncrBy(key string, member string, increment float64, flags int) (interface{}, error) {
+	existSet, err := s.getSortedSet(key, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	score, result, err := existSet.Add(member, increment, flags|zset.ADD_INCR)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !existSet.IsEmpty() {
+		s.setWithLock(key, existSet)
+	}
+
+	if result == zset.ADD_RESULT_NOP && flags != 0 {
+		return nil, nil
+	}
+
+	return score, nil
+}
+
+// ZRem removes the members, deleting the key once the set is empty.
+// Returns the number of removed members.
+func (s *Store) ZRem(key string, members ...string) (int, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for _, member := range members {
+		if existSet.Remove(member) {
+			removed++
+		}
+	}
+
+	if existSet.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if removed > 0 {
+		s.touch(key)
+	}
+
+	return removed, nil
+}
+
+// ZScore returns the score of the member, nil when it is not a member
+func (s *Store) ZScore(key string, member string) (interface{}, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return nil, err
+	}
+
+	score, found := existSet.Score(member)
+
+	if !found {
+		return nil, nil
+	}
+
+	return score, nil
+}
+
+func (s *Store) ZCard(key string) (int, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	return existSet.Len(), nil
+}
+
+// ZRank returns the 0 based rank of the member along with its score, from the
+// highest score when reverse is set. The entry is nil when it is not a member.
+func (s *Store) ZRank(key string, member string, reverse bool) (*zset.Entry, int, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return nil, 0, err
+	}
+
+	rank, found := existSet.Rank(member, reverse)
+
+	if !found {
+		return nil, 0, nil
+	}
+
+	score, _ := existSet.Score(member)
+
+	return &zset.Entry{Member: member, Score: score}, rank, nil
+}
+
+// ZRangeByRank returns the members between the positions start and stop, both
+// inclusive. Negative positions count from the end.
+func (s *Store) ZRangeByRank(key string, start int, stop int, reverse bool) ([]zset.Entry, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []zset.Entry{}, err
+	}
+
+	length := existSet.Len()
+
+	if start < 0 {
+		start = max(start+length, 0)
+	}
+
+	if stop < 0 {
+		stop += length
+	}
+
+	return existSet.RangeByRank(start, stop, reverse), nil
+}
+
+func (s *Store) ZRangeByScore(key string, scoreRange zset.ScoreRange, reverse bool, offset int, count int) ([]zset.Entry, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil || offset < 0 {
+		return []zset.Entry{}, err
+	}
+
+	return existSet.RangeByScore(scoreRange, reverse, offset, count), nil
+}
+
+func (s *Store) ZRangeByLex(key string, lexRange zset.LexRange, reverse bool, offset int, count int) ([]zset.Entry, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil || offset < 0 {
+		return []zset.Entry{}, err
+	}
+
+	return existSet.RangeByLex(lexRange, reverse, offset, count), nil
+}
+
+func (s *Store) ZCount(key string, scoreRange zset.ScoreRange) (int, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	return existSet.CountByScore(scoreRange), nil
+}
+
+func (s *Store) ZLexCount(key string, lexRange zset.LexRange) (int, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return 0, err
+	}
+
+	return existSet.CountByLex(lexRange), nil
+}
+
+// ZPop removes and returns up to count members with the lowest scores, or the
+// highest ones when reverse is set
+func (s *Store) ZPop(key string, count int, reverse bool) ([]zset.Entry, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []zset.Entry{}, err
+	}
+
+	entries := existSet.Pop(count, reverse)
+
+	if existSet.IsEmpty() {
+		s.deleteWithLock(key)
+	} else if len(entries) > 0 {
+		s.touch(key)
+	}
+
+	return entries, nil
+}
+
+// ZStore replaces destination with a sorted set of the given entries, returns its size
+func (s *Store) ZStore(destination string, entries []zset.Entry) int {
+	result := zset.NewSortedSet()
+
+	for _, entry := range entries {
+		result.Add(entry.Member, entry.Score, 0)
+	}
+
+	if result.IsEmpty() {
+		s.deleteWithLock(destination)
+		return 0
+	}
+
+	s.setWithExpiry(destination, result, 0)
+	return result.Len()
+}
+
+// ZCombine computes the union, or the intersection, of the sorted sets at keys.
+// Plain sets are accepted too, their members having a score of 1. Scores are
+// multiplied by the weight of their set and combined with aggregate.
+func (s *Store) ZCombine(keys []string, weights []float64, aggregate string, union bool) ([]zset.Entry, error) {
+	sources := [][]zset.Entry{}
+
+	for _, key := range keys {
+		entries, err := s.zsetEntries(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, entries)
+	}
+
+	scores := map[string]float64{}
+	seenIn := map[string]int{}
+	order := []string{}
+
+	for i, entries := range sources {
+		for _, entry := range entries {
+			score := entry.Score * weights[i]
+
+			// inf * 0 is defined as 0, like redis does
+			if math.IsNaN(score) {
+				score = 0
+			}
+
+			current, exists := scores[entry.Member]
+
+			if !exists {
+				scores[entry.Member] = score
+				order = append(order, entry.Member)
+			} else {
+				scores[entry.Member] = aggregateScores(current, score, aggregate)
+			}
+
+			seenIn[entry.Member]++
+		}
+	}
+
+	result := []zset.Entry{}
+
+	for _, member := range order {
+		if !union && seenIn[member] != len(sources) {
+			continue
+		}
+
+		result = append(result, zset.Entry{Member: member, Score: scores[member]})
+	}
+
+	return result, nil
+}
+
+// zsetEntries returns the entries of a sorted set or plain set
+func (s *Store) zsetEntries(key string) ([]zset.Entry, error) {
+	data, found := s.setLockAndGet(key)
+
+	if !found {
+		return []zset.Entry{}, nil
+	}
+
+	switch value := data.(type) {
+	case *zset.SortedSet:
+		return value.Entries(), nil
+	case *set.Set:
+		entries := []zset.Entry{}
+
+		for _, member := range value.Members() {
+			entries = append(entries, zset.Entry{Member: member, Score: 1})
+		}
+
+		return entries, nil
+	}
+
+	return nil, ErrWrongType
+}
+
+func aggregateScores(current float64, score float64, aggregate string) float64 {
+	switch aggregate {
+	case AGGREGATE_MIN:
+		return min(current, score)
+	case AGGREGATE_MAX:
+		return max(current, score)
+	}
+
+	sum := current + score
+
+	// inf + -inf is defined as 0, like redis does
+	if math.IsNaN(sum) {
+		return 0
+	}
+
+	return sum
+}
+
+// ZScan iterates the members of the sorted set, see scan.Scan for the cursor guarantees
+func (s *Store) ZScan(key string, cursor uint64, pattern string, count int) ([]zset.Entry, uint64, error) {
+	existSet, err := s.getSortedSet(key, false)
+
+	if err != nil || existSet == nil {
+		return []zset.Entry{}, 0, err
+	}
+
+	entries, nextCursor := existSet.Scan(cursor, count)
+	result := []zset.Entry{}
+
+	for _, entry := range entries {
+		if pattern == "" || scan.Match(pattern, entry.Member) {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nextCursor, nil
+}
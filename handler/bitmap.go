@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+var errBitfieldType = errors.New("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+
+// SetBit sets the bit at offset of the string, replies with the previous bit
+// SETBIT key offset value
+func (h *Handler) SetBit(client *Client, args ...any) ([]byte, error) {
+	offset, err := parseBitOffset(args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	value := args[2].(string)
+
+	if value != "0" && value != "1" {
+		return nil, errors.New("ERR bit is not an integer or out of range")
+	}
+
+	previous, err := h.db(client).SetBit(args[0].(string), offset, int(value[0]-'0'))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, previous)
+	return data, err
+}
+
+// GetBit replies with the bit at offset of the string
+// GETBIT key offset
+func (h *Handler) GetBit(client *Client, args ...any) ([]byte, error) {
+	offset, err := parseBitOffset(args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	bit, err := h.db(client).GetBit(args[0].(string), offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, bit)
+	return data, err
+}
+
+// BitCount replies with the number of bits set in the string, or in a range
+// of it counted in bytes or bits
+// BITCOUNT key [start end [BYTE | BIT]]
+func (h *Handler) BitCount(client *Client, args ...any) ([]byte, error) {
+	if len(args) == 2 || len(args) > 4 {
+		return nil, errSyntax
+	}
+
+	start, end, unit, err := parseBitRange(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := h.db(client).BitCount(args[0].(string), start, end, unit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, count)
+	return data, err
+}
+
+// BitPos replies with the offset of the first bit set or clear in the string,
+// or in a range of it counted in bytes or bits
+// BITPOS key bit [start [end [BYTE | BIT]]]
+func (h *Handler) BitPos(client *Client, args ...any) ([]byte, error) {
+	if len(args) > 5 {
+		return nil, errSyntax
+	}
+
+	bit, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	if bit != 0 && bit != 1 {
+		return nil, errors.New("ERR The bit argument must be 1 or 0.")
+	}
+
+	start, end, unit, err := parseBitRange(args[2:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := h.db(client).BitPos(args[0].(string), bit, start, end, len(args) > 3, unit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, position)
+	return data, err
+}
+
+// BitOp stores the bitwise operation of the strings at destination, replies
+// with the length of the result
+// BITOP AND | OR | XOR | NOT destkey key [key ...]
+func (h *Handler) BitOp(client *Client, args ...any) ([]byte, error) {
+	operation := strings.ToUpper(args[0].(string))
+	keys := stringArgs(args[2:])
+
+	switch operation {
+	case data.BITOP_AND, data.BITOP_OR, data.BITOP_XOR:
+	case data.BITOP_NOT:
+		if len(keys) != 1 {
+			return nil, errors.New("ERR BITOP NOT must be called with a single source key.")
+		}
+	default:
+		return nil, errSyntax
+	}
+
+	length, err := h.db(client).BitOp(operation, args[1].(string), keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+	return data, err
+}
+
+// Bitfield reads and writes integers of any width up to 64 bits stored in the
+// string, replies with an array holding the result of each subcommand
+// BITFIELD key [GET encoding offset | [OVERFLOW WRAP | SAT | FAIL]
+// {SET encoding offset value | INCRBY encoding offset increment} ...]
+func (h *Handler) Bitfield(client *Client, args ...any) ([]byte, error) {
+	return h.bitfield(client, args, false)
+}
+
+// BitfieldRO is the read only variant of BITFIELD
+// BITFIELD_RO key [GET encoding offset ...]
+func (h *Handler) BitfieldRO(client *Client, args ...any) ([]byte, error) {
+	return h.bitfield(client, args, true)
+}
+
+func (h *Handler) bitfield(client *Client, args []any, readOnly bool) ([]byte, error) {
+	operations, err := parseBitfieldOps(args[1:], readOnly)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.db(client).Bitfield(args[0].(string), operations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]resp.ArrayType, 0, len(results))
+
+	for _, result := range results {
+		if result == nil {
+			items = append(items, resp.ArrayType{Value: nil, Type: resp.NULL})
+			continue
+		}
+
+		items = append(items, resp.ArrayType{Value: int(result.(int64)), Type: resp.INTEGER})
+	}
+
+	data, err := client.Serialize(resp.ARRAY, items)
+	return data, err
+}
+
+// parseBitfieldOps parses the subcommands of BITFIELD, OVERFLOW applies to the
+// following SET and INCRBY
+func parseBitfieldOps(args []any, readOnly bool) ([]data.BitfieldOp, error) {
+	operations := []data.BitfieldOp{}
+	overflow := data.OVERFLOW_WRAP
+
+	for i := 0; i < len(args); {
+		command := strings.ToUpper(args[i].(string))
+
+		if command == "OVERFLOW" {
+			if i+1 >= len(args) {
+				return nil, errSyntax
+			}
+
+			overflow = strings.ToUpper(args[i+1].(string))
+
+			if overflow != data.OVERFLOW_WRAP && overflow != data.OVERFLOW_SAT && overflow != data.OVERFLOW_FAIL {
+				return nil, errors.New("ERR Invalid OVERFLOW type specified")
+			}
+
+			i += 2
+			continue
+		}
+
+		argCount := 3
+
+		switch command {
+		case data.BITFIELD_GET:
+			argCount = 2
+		case data.BITFIELD_SET, data.BITFIELD_INCRBY:
+		default:
+			return nil, errSyntax
+		}
+
+		if i+argCount >= len(args) {
+			return nil, errSyntax
+		}
+
+		if readOnly && command != data.BITFIELD_GET {
+			return nil, errors.New("ERR BITFIELD_RO only supports the GET subcommand")
+		}
+
+		operation := data.BitfieldOp{Command: command, Overflow: overflow}
+
+		signed, bits, err := parseBitfieldType(args[i+1].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		operation.Signed, operation.Bits = signed, bits
+		operation.Offset, err = parseBitfieldOffset(args[i+2].(string), bits)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if command != data.BITFIELD_GET {
+			operation.Value, err = strconv.ParseInt(args[i+3].(string), 10, 64)
+
+			if err != nil {
+				return nil, errNotInteger
+			}
+		}
+
+		operations = append(operations, operation)
+		i += argCount + 1
+	}
+
+	return operations, nil
+}
+
+// parseBitfieldType parses an encoding like i16 or u8, unsigned integers have
+// at most 63 bits so that they fit an int64
+func parseBitfieldType(encoding string) (bool, int, error) {
+	if len(encoding) < 2 {
+		return false, 0, errBitfieldType
+	}
+
+	signed := encoding[0] == 'i' || encoding[0] == 'I'
+
+	if !signed && encoding[0] != 'u' && encoding[0] != 'U' {
+		return false, 0, errBitfieldType
+	}
+
+	bits, err := strconv.Atoi(encoding[1:])
+
+	if err != nil || bits < 1 || bits > 64 || (!signed && bits == 64) {
+		return false, 0, errBitfieldType
+	}
+
+	return signed, bits, nil
+}
+
+// parseBitfieldOffset parses an offset in bits, or in integers of the given
+// width when prefixed with #
+func parseBitfieldOffset(value string, bits int) (int, error) {
+	multiplier := 1
+
+	if strings.HasPrefix(value, "#") {
+		value, multiplier = value[1:], bits
+	}
+
+	offset, err := parseBitOffset(value)
+
+	if err != nil {
+		return 0, err
+	}
+
+	offset *= multiplier
+
+	if offset+bits-1 > data.MAX_BIT_OFFSET {
+		return 0, data.ErrBitOffset
+	}
+
+	return offset, nil
+}
+
+func parseBitOffset(value string) (int, error) {
+	offset, err := strconv.Atoi(value)
+
+	if err != nil || offset < 0 || offset > data.MAX_BIT_OFFSET {
+		return 0, data.ErrBitOffset
+	}
+
+	return offset, nil
+}
+
+// parseBitRange parses the optional start, end and unit of BITCOUNT and
+// BITPOS, the whole string counted in bytes by default
+func parseBitRange(args []any) (int, int, string, error) {
+	start, end, unit := 0, -1, data.BIT_RANGE_BYTE
+	var err error
+
+	if len(args) > 0 {
+		if start, err = strconv.Atoi(args[0].(string)); err != nil {
+			return 0, 0, "", errNotInteger
+		}
+	}
+
+	if len(args) > 1 {
+		if end, err = strconv.Atoi(args[1].(string)); err != nil {
+			return 0, 0, "", errNotInteger
+		}
+	}
+
+	if len(args) > 2 {
+		unit = strings.ToUpper(args[2].(string))
+
+		if unit != data.BIT_RANGE_BYTE && unit != data.BIT_RANGE_BIT {
+			return 0, 0, "", errSyntax
+		}
+	}
+
+	return start, end, unit, nil
+}
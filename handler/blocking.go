@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// BLPop is the blocking form of LPOP over several keys
+// BLPOP key [key ...] timeout
+func (h *Handler) BLPop(client *Client, args ...any) ([]byte, error) {
+	return h.blockingPop(client, true, args...)
+}
+
+// BRPop is the blocking form of RPOP over several keys
+// BRPOP key [key ...] timeout
+func (h *Handler) BRPop(client *Client, args ...any) ([]byte, error) {
+	return h.blockingPop(client, false, args...)
+}
+
+func (h *Handler) blockingPop(client *Client, head bool, args ...any) ([]byte, error) {
+	timeout, err := parseBlockTimeout(args[len(args)-1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := stringArgs(args[:len(args)-1])
+
+	// popping a single element is the same as LMPOP with a count of 1, except
+	// for the shape of the reply
+	key, items, err := h.popFirstNonEmpty(client, keys, head, 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return h.block(client, keys, timeout, resp.ARRAY)
+	}
+
+	reply := []resp.ArrayType{
+		{Value: key, Type: resp.BULK_STRING},
+		items[0],
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
+
+// BLMPop is the blocking form of LMPOP
+// BLMPOP timeout numkeys key [key ...] LEFT | RIGHT [COUNT count]
+func (h *Handler) BLMPop(client *Client, args ...any) ([]byte, error) {
+	timeout, err := parseBlockTimeout(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, head, count, err := parseMPopArgs(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := h.mpop(client, keys, head, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return h.block(client, keys, timeout, resp.ARRAY)
+	}
+
+	return reply, nil
+}
+
+// BLMove is the blocking form of LMOVE
+// BLMOVE source destination LEFT | RIGHT LEFT | RIGHT timeout
+func (h *Handler) BLMove(client *Client, args ...any) ([]byte, error) {
+	fromHead, err := parseListEnd(args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	toHead, err := parseListEnd(args[3].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := parseBlockTimeout(args[4].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return h.blockingMove(client, args[0].(string), args[1].(string), fromHead, toHead, timeout)
+}
+
+// BRPopLPush is the deprecated form of BLMOVE source destination RIGHT LEFT timeout
+func (h *Handler) BRPopLPush(client *Client, args ...any) ([]byte, error) {
+	timeout, err := parseBlockTimeout(args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return h.blockingMove(client, args[0].(string), args[1].(string), false, true, timeout)
+}
+
+func (h *Handler) blockingMove(client *Client, source string, destination string, fromHead bool, toHead bool, timeout time.Duration) ([]byte, error) {
+	value, err := h.db(client).LMove(source, destination, fromHead, toHead)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return h.block(client, []string{source}, timeout, resp.BULK_STRING)
+	}
+
+	client.Propagate(LMOVE, source, destination, listEnd(fromHead), listEnd(toHead))
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+
+	return data, err
+}
+
+// block parks the client on keys, replying with a null of nullType on timeout
+func (h *Handler) block(client *Client, keys []string, timeout time.Duration, nullType string) ([]byte, error) {
+	return h.blockWithArgs(client, keys, timeout, nullType, nil)
+}
+
+// blockWithArgs is block for commands running again with other arguments,
+// like XREAD resolving the IDs it reads from before blocking
+func (h *Handler) blockWithArgs(client *Client, keys []string, timeout time.Duration, nullType string, args []any) ([]byte, error) {
+	timeoutReply, err := client.Serialize(nullType, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.Block(keys, timeout, timeoutReply, args)
+	return nil, nil
+}
+
+// parseBlockTimeout parses a timeout in seconds, zero meaning forever
+func parseBlockTimeout(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, errors.New("ERR timeout is not a float or out of range")
+	}
+
+	if seconds < 0 {
+		return 0, errors.New("ERR timeout is negative")
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"io"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/pubsub"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Client holds the state of a single connection
+type Client struct {
+	ID       int64
+	Name     string
+	Protocol int
+	// index of the selected database
+	DB int
+	// replies and pushed messages are written to it, nil for clients which are
+	// not connected, like the one replaying the append only file
+	Writer io.Writer
+	// number of channels and patterns the client is subscribed to
+	subscriptions int
+	// set by blocking commands which could not be served right away
+	blockRequest *BlockRequest
+	// commands logged in place of the current one
+	propagated []Command
+	// commands queued since MULTI, nil outside of a transaction
+	transaction *transaction
+	// keys watched for the next transaction, nil when nothing is watched
+	watch *data.Watch
+}
+
+type transaction struct {
+	commands []Command
+	// set when a command could not be queued, EXEC then discards the transaction
+	failed bool
+}
+
+// BlockRequest asks the server to park the client until an element is pushed
+// to one of Keys, or until Timeout elapses when it is not zero
+type BlockRequest struct {
+	Keys         []string
+	Timeout      time.Duration
+	TimeoutReply []byte
+	// arguments the command runs again with, its own ones when nil
+	Args []any
+}
+
+// Command is a command along with its arguments
+type Command struct {
+	Name string
+	Args []any
+	// database the command is logged for
+	DB int
+}
+
+func NewClient(id int64) *Client {
+	return &Client{
+		ID:       id,
+		Protocol: resp.RESP2,
+	}
+}
+
+// Serialize encodes a reply using the protocol negotiated by the client
+func (c *Client) Serialize(dataType string, data any) ([]byte, error) {
+	return resp.SerializeWithProtocol(c.Protocol, dataType, data)
+}
+
+// Block asks the server to park the client once the current command returns,
+// the command is executed again with args, or its own arguments when nil,
+// when one of the keys receives elements
+func (c *Client) Block(keys []string, timeout time.Duration, timeoutReply []byte, args []any) {
+	c.blockRequest = &BlockRequest{
+		Keys:         keys,
+		Timeout:      timeout,
+		TimeoutReply: timeoutReply,
+		Args:         args,
+	}
+}
+
+// TakeBlockRequest returns and clears the pending block request, if any
+func (c *Client) TakeBlockRequest() *BlockRequest {
+	request := c.blockRequest
+	c.blockRequest = nil
+	return request
+}
+
+// Propagate logs command instead of the current one, for commands whose
+// effect must be replayed differently, like a blocking pop replayed as a pop
+func (c *Client) Propagate(command string, args ...any) {
+	c.propagated = append(c.propagated, Command{Name: command, Args: args, DB: c.DB})
+}
+
+// TakePropagated returns and clears the commands to log in place of the current one
+func (c *Client) TakePropagated() []Command {
+	commands := c.propagated
+	c.propagated = nil
+	return commands
+}
+
+// Write sends data to the client connection, if any
+func (c *Client) Write(data []byte) {
+	if c.Writer != nil {
+		c.Writer.Write(data)
+	}
+}
+
+// InTransaction reports whether the client is queuing commands after MULTI
+func (c *Client) InTransaction() bool {
+	return c.transaction != nil
+}
+
+// Queue adds the command to the transaction, to be run by EXEC
+func (c *Client) Queue(command string, args []any) {
+	c.transaction.commands = append(c.transaction.commands, Command{Name: command, Args: args})
+}
+
+// FailTransaction makes EXEC discard the transaction, after a command could not be queued
+func (c *Client) FailTransaction() {
+	c.transaction.failed = true
+}
+
+// Subscriptions returns the number of channels and patterns the client is subscribed to
+func (c *Client) Subscriptions() int {
+	return c.subscriptions
+}
+
+// Deliver pushes a published message to the client
+func (c *Client) Deliver(message pubsub.Message) {
+	var items []resp.ArrayType
+
+	if message.Pattern == "" {
+		items = bulkStrings([]string{"message", message.Channel, message.Payload})
+	} else {
+		items = bulkStrings([]string{"pmessage", message.Pattern, message.Channel, message.Payload})
+	}
+
+	data, err := c.Serialize(resp.PUSH, items)
+
+	if err != nil {
+		return
+	}
+
+	c.Write(data)
+}
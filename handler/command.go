@@ -0,0 +1,511 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Command flags, reported by COMMAND
+const (
+	CMD_WRITE = 1 << iota
+	CMD_READONLY
+	CMD_DENYOOM
+	CMD_ADMIN
+	CMD_PUBSUB
+	CMD_FAST
+	CMD_BLOCKING
+)
+
+// names of the flags, in the order COMMAND reports them
+var commandFlagNames = []struct {
+	flag int
+	name string
+}{
+	{CMD_WRITE, "write"},
+	{CMD_READONLY, "readonly"},
+	{CMD_DENYOOM, "denyoom"},
+	{CMD_ADMIN, "admin"},
+	{CMD_PUBSUB, "pubsub"},
+	{CMD_FAST, "fast"},
+	{CMD_BLOCKING, "blocking"},
+}
+
+// ACL categories
+const (
+	ACL_KEYSPACE    string = "@keyspace"
+	ACL_READ        string = "@read"
+	ACL_WRITE       string = "@write"
+	ACL_SET         string = "@set"
+	ACL_SORTEDSET   string = "@sortedset"
+	ACL_LIST        string = "@list"
+	ACL_HASH        string = "@hash"
+	ACL_STRING      string = "@string"
+	ACL_BITMAP      string = "@bitmap"
+	ACL_HYPERLOGLOG string = "@hyperloglog"
+	ACL_GEO         string = "@geo"
+	ACL_STREAM      string = "@stream"
+	ACL_PUBSUB      string = "@pubsub"
+	ACL_ADMIN       string = "@admin"
+	ACL_FAST        string = "@fast"
+	ACL_SLOW        string = "@slow"
+	ACL_BLOCKING    string = "@blocking"
+	ACL_DANGEROUS   string = "@dangerous"
+	ACL_CONNECTION  string = "@connection"
+	ACL_TRANSACTION string = "@transaction"
+)
+
+// Command groups, reported by COMMAND DOCS
+const (
+	GROUP_GENERIC      string = "generic"
+	GROUP_STRING       string = "string"
+	GROUP_LIST         string = "list"
+	GROUP_SET          string = "set"
+	GROUP_SORTED_SET   string = "sorted-set"
+	GROUP_HASH         string = "hash"
+	GROUP_PUBSUB       string = "pubsub"
+	GROUP_TRANSACTIONS string = "transactions"
+	GROUP_CONNECTION   string = "connection"
+	GROUP_SERVER       string = "server"
+	GROUP_HYPERLOGLOG  string = "hyperloglog"
+	GROUP_GEO          string = "geo"
+	GROUP_STREAM       string = "stream"
+	GROUP_BITMAP       string = "bitmap"
+)
+
+var (
+	errInvalidCommand   = errors.New("ERR Invalid command specified")
+	errInvalidArgsCount = errors.New("ERR Invalid number of arguments specified for command")
+	errInvalidArgs      = errors.New("ERR Invalid arguments specified for command")
+	errNoKeyArgs        = errors.New("ERR The command has no key arguments")
+)
+
+// KeySpec gives the positions of the keys of a command, the command name being
+// at position 0. Last is negative when counted from the end, -1 being the last
+// argument. A zero First means there are no keys. Commands whose keys depend on
+// other arguments, like a number of keys, find them with Find instead, First
+// to Last then only covering the keys which come first, if any.
+type KeySpec struct {
+	First int
+	Last  int
+	Step  int
+	// returns the keys among the arguments following the command name, false
+	// when they are invalid
+	Find func(args []any) ([]string, bool)
+}
+
+// CommandSpec describes a command: its handler along with the metadata the
+// dispatcher enforces and COMMAND reports
+type CommandSpec struct {
+	Name    string
+	Handler HandlerFunc
+	// number of arguments including the command name, -N meaning at least N
+	Arity int
+	// CMD_* flags
+	Flags int
+	Keys  KeySpec
+	// ACL categories of the data the command works on, the ones implied by its
+	// flags are added by AclCategories
+	Categories []string
+	Group      string
+	Summary    string
+	// set on write commands whose handler logs its effects with
+	// Client.Propagate, like the pop a blocking pop made or the ID XADD
+	// generated. They are never logged as they were called.
+	PropagatesEffects bool
+}
+
+// CheckArity returns the error replied when args, the arguments following the
+// command name, do not match the arity of the command
+func (c *CommandSpec) CheckArity(args []any) error {
+	if !c.acceptsArgs(len(args)) {
+		return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(c.Name))
+	}
+
+	return nil
+}
+
+func (c *CommandSpec) acceptsArgs(count int) bool {
+	if c.Arity < 0 {
+		return count+1 >= -c.Arity
+	}
+
+	return count+1 == c.Arity
+}
+
+// LoggedAsCalled reports whether the command is logged to the append only
+// file as it was called when it propagates nothing itself
+func (c *CommandSpec) LoggedAsCalled() bool {
+	return c.Flags&CMD_WRITE != 0 && !c.PropagatesEffects
+}
+
+// MovableKeys reports whether the keys can not be found from their positions alone
+func (c *CommandSpec) MovableKeys() bool {
+	return c.Keys.Find != nil
+}
+
+// KeysOf returns the keys among args, the arguments following the command
+// name, false when the arguments are invalid
+func (c *CommandSpec) KeysOf(args []any) ([]string, bool) {
+	if c.Keys.Find != nil {
+		return c.Keys.Find(args)
+	}
+
+	keys := []string{}
+
+	if c.Keys.First == 0 {
+		return keys, true
+	}
+
+	last := c.Keys.Last
+
+	if last < 0 {
+		last += len(args) + 1
+	}
+
+	// commands with a variable number of arguments may not have every key
+	for i := c.Keys.First; i <= last && i <= len(args); i += c.Keys.Step {
+		keys = append(keys, args[i-1].(string))
+	}
+
+	return keys, true
+}
+
+// AclCategories returns the categories of the command, adding the ones implied
+// by its flags like redis does. Commands which are not fast are slow.
+func (c *CommandSpec) AclCategories() []string {
+	categories := slices.Clone(c.Categories)
+
+	implied := []struct {
+		flag       int
+		categories []string
+	}{
+		{CMD_WRITE, []string{ACL_WRITE}},
+		{CMD_READONLY, []string{ACL_READ}},
+		{CMD_ADMIN, []string{ACL_ADMIN, ACL_DANGEROUS}},
+		{CMD_PUBSUB, []string{ACL_PUBSUB}},
+		{CMD_FAST, []string{ACL_FAST}},
+		{CMD_BLOCKING, []string{ACL_BLOCKING}},
+	}
+
+	for _, entry := range implied {
+		if c.Flags&entry.flag == 0 {
+			continue
+		}
+
+		for _, category := range entry.categories {
+			if !slices.Contains(categories, category) {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	if !slices.Contains(categories, ACL_FAST) {
+		categories = append(categories, ACL_SLOW)
+	}
+
+	return categories
+}
+
+// ErrUnknownCommand is the error replied to commands which are not registered
+func ErrUnknownCommand(command string, args []any) error {
+	quoted := ""
+
+	for _, arg := range args {
+		if len(quoted) >= 128 {
+			break
+		}
+
+		quoted += fmt.Sprintf("'%.*s' ", 128-len(quoted), arg.(string))
+	}
+
+	return fmt.Errorf("ERR unknown command '%.128s', with args beginning with: %s", command, quoted)
+}
+
+// countedKeys finds the keys of commands giving their number of keys at
+// position at of their arguments, followed by the keys. The leading arguments
+// before it are keys too when leadingKeys is set, like a destination.
+func countedKeys(at int, leadingKeys bool) func(args []any) ([]string, bool) {
+	return func(args []any) ([]string, bool) {
+		if at >= len(args) {
+			return nil, false
+		}
+
+		count, err := strconv.Atoi(args[at].(string))
+
+		// written so that a huge count can not overflow
+		if err != nil || count < 1 || count > len(args)-at-1 {
+			return nil, false
+		}
+
+		keys := []string{}
+
+		if leadingKeys {
+			keys = append(keys, stringArgs(args[:at])...)
+		}
+
+		return append(keys, stringArgs(args[at+1:at+1+count])...), true
+	}
+}
+
+// streamKeys finds the keys of XREAD and XREADGROUP, the first half of the
+// arguments following STREAMS, which is looked for from position from
+func streamKeys(from int) func(args []any) ([]string, bool) {
+	return func(args []any) ([]string, bool) {
+		for i := from; i < len(args); i++ {
+			if strings.ToUpper(args[i].(string)) != "STREAMS" {
+				continue
+			}
+
+			streams := args[i+1:]
+
+			if len(streams) == 0 || len(streams)%2 != 0 {
+				return nil, false
+			}
+
+			return stringArgs(streams[:len(streams)/2]), true
+		}
+
+		return nil, false
+	}
+}
+
+// Command describes the commands
+// COMMAND [COUNT | INFO [command-name ...] | DOCS [command-name ...] | GETKEYS command [arg ...] | HELP]
+func (h *Handler) Command(client *Client, args ...any) ([]byte, error) {
+	if len(args) == 0 {
+		return client.Serialize(resp.ARRAY, h.commandInfos(h.sortedCommands()))
+	}
+
+	subcommand := strings.ToUpper(args[0].(string))
+
+	switch {
+	case subcommand == "COUNT" && len(args) == 1:
+		return client.Serialize(resp.INTEGER, len(h.commands))
+
+	case subcommand == "INFO":
+		if len(args) == 1 {
+			return client.Serialize(resp.ARRAY, h.commandInfos(h.sortedCommands()))
+		}
+
+		commands := []*CommandSpec{}
+
+		for _, name := range stringArgs(args[1:]) {
+			commands = append(commands, h.commands[strings.ToUpper(name)])
+		}
+
+		return client.Serialize(resp.ARRAY, h.commandInfos(commands))
+
+	case subcommand == "DOCS":
+		commands := h.sortedCommands()
+
+		if len(args) > 1 {
+			commands = []*CommandSpec{}
+
+			// unknown commands are left out
+			for _, name := range stringArgs(args[1:]) {
+				if command, found := h.commands[strings.ToUpper(name)]; found {
+					commands = append(commands, command)
+				}
+			}
+		}
+
+		return client.Serialize(resp.MAP, commandDocs(commands))
+
+	case subcommand == "HELP" && len(args) == 1:
+		return helpReply(client, COMMAND,
+			"(no subcommand)",
+			"    Return details about all commands.",
+			"COUNT",
+			"    Return the total number of commands in this server.",
+			"DOCS [<command-name> ...]",
+			"    Return documentation details about multiple commands.",
+			"    If no command names are given, documentation details for all",
+			"    commands are returned.",
+			"GETKEYS <full-command>",
+			"    Return the keys from a full command.",
+			"INFO [<command-name> ...]",
+			"    Return details about multiple commands.",
+			"    If no command names are given, details for all commands are returned.",
+		)
+
+	case subcommand == "GETKEYS" && len(args) > 1:
+		keys, err := h.commandKeys(args[1].(string), args[2:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		return client.Serialize(resp.ARRAY, bulkStrings(keys))
+	}
+
+	return nil, fmt.Errorf("ERR unknown subcommand or wrong number of arguments for '%s'. Try COMMAND HELP.", args[0].(string))
+}
+
+// sortedCommands returns the registered commands ordered by name
+func (h *Handler) sortedCommands() []*CommandSpec {
+	commands := []*CommandSpec{}
+
+	for _, command := range h.commands {
+		commands = append(commands, command)
+	}
+
+	slices.SortFunc(commands, func(a *CommandSpec, b *CommandSpec) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return commands
+}
+
+// commandKeys returns the keys of the command called with args
+func (h *Handler) commandKeys(name string, args []any) ([]string, error) {
+	command, found := h.commands[strings.ToUpper(name)]
+
+	if !found {
+		return nil, errInvalidCommand
+	}
+
+	if !command.acceptsArgs(len(args)) {
+		return nil, errInvalidArgsCount
+	}
+
+	if command.Keys.First == 0 && !command.MovableKeys() {
+		return nil, errNoKeyArgs
+	}
+
+	keys, valid := command.KeysOf(args)
+
+	if !valid || len(keys) == 0 {
+		return nil, errInvalidArgs
+	}
+
+	return keys, nil
+}
+
+// commandInfos builds the replies of COMMAND INFO, nil commands are unknown
+func (h *Handler) commandInfos(commands []*CommandSpec) []resp.ArrayType {
+	reply := []resp.ArrayType{}
+
+	for _, command := range commands {
+		if command == nil {
+			reply = append(reply, resp.ArrayType{Value: nil, Type: resp.ARRAY})
+			continue
+		}
+
+		reply = append(reply, resp.ArrayType{Value: commandInfo(command), Type: resp.ARRAY})
+	}
+
+	return reply
+}
+
+// commandInfo builds the description of the command: its name, arity, flags,
+// key positions, ACL categories, tips, key specifications and subcommands.
+// Tips and subcommands are always empty: no command has tips for clients and
+// subcommands are handled by their command rather than described by specs.
+func commandInfo(command *CommandSpec) []resp.ArrayType {
+	flags := []resp.ArrayType{}
+
+	for _, entry := range commandFlagNames {
+		if command.Flags&entry.flag != 0 {
+			flags = append(flags, resp.ArrayType{Value: entry.name, Type: resp.SIMPLE_STRING})
+		}
+	}
+
+	if command.MovableKeys() {
+		flags = append(flags, resp.ArrayType{Value: "movablekeys", Type: resp.SIMPLE_STRING})
+	}
+
+	categories := []resp.ArrayType{}
+
+	for _, category := range command.AclCategories() {
+		categories = append(categories, resp.ArrayType{Value: category, Type: resp.SIMPLE_STRING})
+	}
+
+	return []resp.ArrayType{
+		{Value: strings.ToLower(command.Name), Type: resp.BULK_STRING},
+		{Value: command.Arity, Type: resp.INTEGER},
+		{Value: flags, Type: resp.SET},
+		{Value: command.Keys.First, Type: resp.INTEGER},
+		{Value: command.Keys.Last, Type: resp.INTEGER},
+		{Value: command.Keys.Step, Type: resp.INTEGER},
+		{Value: categories, Type: resp.SET},
+		{Value: []resp.ArrayType{}, Type: resp.ARRAY},
+		{Value: keySpecs(command), Type: resp.ARRAY},
+		{Value: []resp.ArrayType{}, Type: resp.ARRAY},
+	}
+}
+
+// keySpecs builds the key specifications of the command from its key
+// positions. Keys found by Find are described as unknown, like redis does for
+// the keys it can not locate from the arguments alone. Access flags are not
+// tracked and always empty.
+func keySpecs(command *CommandSpec) []resp.ArrayType {
+	specs := []resp.ArrayType{}
+
+	if command.Keys.First != 0 {
+		// the last key is relative to the first one unless counted from the end
+		lastKey := command.Keys.Last
+
+		if lastKey >= 0 {
+			lastKey -= command.Keys.First
+		}
+
+		specs = append(specs, keySpec(
+			keySearch("index", mapEntry("index", resp.INTEGER, command.Keys.First)),
+			keySearch("range",
+				mapEntry("lastkey", resp.INTEGER, lastKey),
+				mapEntry("keystep", resp.INTEGER, command.Keys.Step),
+				mapEntry("limit", resp.INTEGER, 0),
+			),
+		))
+	}
+
+	if command.MovableKeys() {
+		specs = append(specs, keySpec(keySearch("unknown"), keySearch("unknown")))
+	}
+
+	return specs
+}
+
+func keySpec(beginSearch []resp.MapType, findKeys []resp.MapType) resp.ArrayType {
+	spec := []resp.MapType{
+		mapEntry("flags", resp.SET, []resp.ArrayType{}),
+		mapEntry("begin_search", resp.MAP, beginSearch),
+		mapEntry("find_keys", resp.MAP, findKeys),
+	}
+
+	return resp.ArrayType{Value: spec, Type: resp.MAP}
+}
+
+// keySearch builds a step of a key specification, its type and arguments
+func keySearch(searchType string, spec ...resp.MapType) []resp.MapType {
+	if spec == nil {
+		spec = []resp.MapType{}
+	}
+
+	return []resp.MapType{
+		mapEntry("type", resp.BULK_STRING, searchType),
+		mapEntry("spec", resp.MAP, spec),
+	}
+}
+
+// commandDocs builds the reply of COMMAND DOCS, a map of the commands to
+// their summary and group
+func commandDocs(commands []*CommandSpec) []resp.MapType {
+	reply := []resp.MapType{}
+
+	for _, command := range commands {
+		docs := []resp.MapType{
+			mapEntry("summary", resp.BULK_STRING, command.Summary),
+			mapEntry("group", resp.BULK_STRING, command.Group),
+		}
+
+		reply = append(reply, mapEntry(strings.ToLower(command.Name), resp.MAP, docs))
+	}
+
+	return reply
+}
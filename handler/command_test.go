@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckArity(t *testing.T) {
+	cases := []struct {
+		arity int
+		args  []any
+		valid bool
+	}{
+		{2, []any{"key"}, true},
+		{2, []any{}, false},
+		{2, []any{"key", "extra"}, false},
+		{-3, []any{"key", "value"}, true},
+		{-3, []any{"key", "value", "more", "values"}, true},
+		{-3, []any{"key"}, false},
+		{-1, []any{}, true},
+		{1, []any{"extra"}, false},
+	}
+
+	for _, c := range cases {
+		command := &CommandSpec{Name: "TEST", Arity: c.arity}
+		err := command.CheckArity(c.args)
+
+		if c.valid {
+			assert.NoError(t, err, c)
+		} else {
+			assert.EqualError(t, err, "ERR wrong number of arguments for 'test' command", c)
+		}
+	}
+}
+
+func TestKeysOf(t *testing.T) {
+	h := NewHandler()
+
+	cases := []struct {
+		command string
+		args    []any
+		keys    []string
+		valid   bool
+	}{
+		{GET, []any{"key"}, []string{"key"}, true},
+		{DEL, []any{"a", "b", "c"}, []string{"a", "b", "c"}, true},
+		{MSET, []any{"a", "1", "b", "2"}, []string{"a", "b"}, true},
+		{BLPOP, []any{"a", "b", "0"}, []string{"a", "b"}, true},
+		{PING, []any{}, []string{}, true},
+		{SINTERCARD, []any{"2", "a", "b", "LIMIT", "1"}, []string{"a", "b"}, true},
+		{ZUNIONSTORE, []any{"dest", "2", "a", "b"}, []string{"dest", "a", "b"}, true},
+		{BLMPOP, []any{"0", "1", "list", "LEFT"}, []string{"list"}, true},
+		{SINTERCARD, []any{"3", "a", "b"}, nil, false},
+		{SINTERCARD, []any{"0", "a"}, nil, false},
+		{SINTERCARD, []any{"count", "a"}, nil, false},
+		{ZUNIONSTORE, []any{"dest"}, nil, false},
+		// a huge count must not overflow past the check
+		{SINTERCARD, []any{"9223372036854775807", "a"}, nil, false},
+		{ZUNIONSTORE, []any{"dest", "9223372036854775807", "a"}, nil, false},
+		{XREAD, []any{"COUNT", "1", "STREAMS", "a", "b", "0", "0"}, []string{"a", "b"}, true},
+		{XREADGROUP, []any{"GROUP", "group", "consumer", "STREAMS", "a", ">"}, []string{"a"}, true},
+		{XREAD, []any{"STREAMS", "a", "b", "0"}, nil, false},
+		{XREAD, []any{"STREAMS"}, nil, false},
+		{XREAD, []any{"COUNT", "1"}, nil, false},
+	}
+
+	for _, c := range cases {
+		command, _ := h.ResolveCommand(c.command)
+		keys, valid := command.KeysOf(c.args)
+
+		assert.Equal(t, c.valid, valid, c)
+		assert.Equal(t, c.keys, keys, c)
+	}
+}
+
+func TestLoggedAsCalled(t *testing.T) {
+	h := NewHandler()
+
+	for _, name := range []string{SET, DEL, LPOP, SREM, ZADD, XDEL, FLUSHALL} {
+		command, _ := h.ResolveCommand(name)
+		assert.True(t, command.LoggedAsCalled(), name)
+	}
+
+	// read commands and the commands logging their effects themselves
+	for _, name := range []string{GET, PING, EXEC, SPOP, INCRBYFLOAT, BLPOP, XADD, XREADGROUP} {
+		command, _ := h.ResolveCommand(name)
+		assert.False(t, command.LoggedAsCalled(), name)
+	}
+}
+
+func TestCommandSubcommands(t *testing.T) {
+	h, client := newTestHandler()
+
+	reply, err := call(h, client, "COMMAND", "COUNT")
+	assert.NoError(t, err)
+	assert.Equal(t, len(h.commands), reply)
+
+	reply, _ = call(h, client, "COMMAND")
+	assert.Len(t, reply, len(h.commands))
+
+	reply, _ = call(h, client, "COMMAND", "INFO", "get", "missing")
+	infos := reply.([]any)
+	assert.Len(t, infos, 2)
+	assert.Nil(t, infos[1])
+
+	info := infos[0].([]any)
+	assert.Equal(t, []any{"get", 2}, info[:2])
+	assert.ElementsMatch(t, []any{"readonly", "fast"}, info[2])
+	assert.Equal(t, []any{1, 1, 1}, info[3:6])
+
+	reply, _ = call(h, client, "COMMAND", "INFO", "sintercard")
+	assert.Contains(t, reply.([]any)[0].([]any)[2], "movablekeys")
+
+	// resp2 flattens the maps
+	reply, _ = call(h, client, "COMMAND", "DOCS", "get", "missing")
+	assert.Equal(t, []any{"get", []any{"summary", "Returns the string value of a key.", "group", GROUP_STRING}}, reply)
+
+	_, err = call(h, client, "COMMAND", "COUNT", "extra")
+	assert.EqualError(t, err, "ERR unknown subcommand or wrong number of arguments for 'COUNT'. Try COMMAND HELP.")
+}
+
+func TestCommandGetKeys(t *testing.T) {
+	h, client := newTestHandler()
+
+	cases := []struct {
+		args []any
+		keys any
+		err  string
+	}{
+		{[]any{"GETKEYS", "mset", "a", "1", "b", "2"}, []any{"a", "b"}, ""},
+		{[]any{"GETKEYS", "zunionstore", "dest", "2", "a", "b"}, []any{"dest", "a", "b"}, ""},
+		{[]any{"GETKEYS", "xread", "STREAMS", "s", "0"}, []any{"s"}, ""},
+		{[]any{"GETKEYS", "missing"}, nil, "ERR Invalid command specified"},
+		{[]any{"GETKEYS", "get"}, nil, "ERR Invalid number of arguments specified for command"},
+		{[]any{"GETKEYS", "ping"}, nil, "ERR The command has no key arguments"},
+		{[]any{"GETKEYS", "sintercard", "5", "a"}, nil, "ERR Invalid arguments specified for command"},
+		{[]any{"GETKEYS", "sintercard", "9223372036854775807", "a"}, nil, "ERR Invalid arguments specified for command"},
+	}
+
+	for _, c := range cases {
+		reply, err := call(h, client, "COMMAND", c.args...)
+
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, c.args)
+		} else {
+			assert.NoError(t, err, c.args)
+			assert.Equal(t, c.keys, reply, c.args)
+		}
+	}
+}
+
+func TestErrUnknownCommand(t *testing.T) {
+	h, client := newTestHandler()
+
+	_, err := call(h, client, "NOPE", "a", "b")
+	assert.EqualError(t, err, "ERR unknown command 'NOPE', with args beginning with: 'a' 'b' ")
+
+	// the name and the quoted arguments are cut to 128 bytes
+	long := strings.Repeat("x", 200)
+	err = ErrUnknownCommand(long, []any{long, long})
+	assert.Equal(t, "ERR unknown command '"+long[:128]+"', with args beginning with: '"+long[:128]+"' ", err.Error())
+}
+
+func TestCommandKeySpecs(t *testing.T) {
+	h, client := newTestHandler()
+	client.Protocol = resp.RESP3
+
+	keySpec := func(name string) any {
+		reply, _ := call(h, client, "COMMAND", "INFO", name)
+		return reply.([]any)[0].([]any)[8]
+	}
+
+	rangeSpec := func(index, lastKey, keyStep int) map[any]any {
+		return map[any]any{
+			"flags":        []any{},
+			"begin_search": map[any]any{"type": "index", "spec": map[any]any{"index": index}},
+			"find_keys": map[any]any{"type": "range", "spec": map[any]any{
+				"lastkey": lastKey, "keystep": keyStep, "limit": 0,
+			}},
+		}
+	}
+
+	unknownSpec := map[any]any{
+		"flags":        []any{},
+		"begin_search": map[any]any{"type": "unknown", "spec": map[any]any{}},
+		"find_keys":    map[any]any{"type": "unknown", "spec": map[any]any{}},
+	}
+
+	assert.Equal(t, []any{rangeSpec(1, 0, 1)}, keySpec("get"))
+	assert.Equal(t, []any{rangeSpec(1, -1, 2)}, keySpec("mset"))
+	assert.Equal(t, []any{rangeSpec(1, 1, 1)}, keySpec("lmove"))
+	assert.Equal(t, []any{unknownSpec}, keySpec("sintercard"))
+	assert.Equal(t, []any{rangeSpec(1, 0, 1), unknownSpec}, keySpec("zunionstore"))
+	assert.Equal(t, []any{}, keySpec("ping"))
+}
+
+func TestHelpSubcommands(t *testing.T) {
+	h, client := newTestHandler()
+
+	for _, command := range []string{COMMAND, OBJECT, PUBSUB, XGROUP, XINFO} {
+		reply, err := call(h, client, command, "help")
+		assert.NoError(t, err, command)
+
+		lines := reply.([]any)
+		assert.Equal(t, command+" <subcommand> [<arg> [value] [opt] ...]. Subcommands are:", lines[0])
+		assert.Equal(t, []any{"HELP", "    Print this help."}, lines[len(lines)-2:])
+	}
+
+	_, err := call(h, client, "OBJECT", "HELP", "extra")
+	assert.EqualError(t, err, "ERR unknown subcommand or wrong number of arguments for 'HELP'. Try OBJECT HELP.")
+}
@@ -0,0 +1,910 @@
+package handler
+
+// key positions shared by most commands
+var (
+	firstKey = KeySpec{First: 1, Last: 1, Step: 1}
+	allKeys  = KeySpec{First: 1, Last: -1, Step: 1}
+)
+
+// commandTable returns the specs of every command the server handles
+func (h *Handler) commandTable() []*CommandSpec {
+	return []*CommandSpec{
+		// connection
+		{
+			Name: PING, Handler: h.Ping, Arity: -1, Flags: CMD_FAST,
+			Categories: []string{ACL_CONNECTION}, Group: GROUP_CONNECTION,
+			Summary: "Returns the server's liveliness response.",
+		},
+		{
+			Name: ECHO, Handler: h.Echo, Arity: 2, Flags: CMD_FAST,
+			Categories: []string{ACL_CONNECTION}, Group: GROUP_CONNECTION,
+			Summary: "Returns the given string.",
+		},
+		{
+			Name: HELLO, Handler: h.Hello, Arity: -1, Flags: CMD_FAST,
+			Categories: []string{ACL_CONNECTION}, Group: GROUP_CONNECTION,
+			Summary: "Handshakes with the Redis server.",
+		},
+		{
+			Name: SELECT, Handler: h.Select, Arity: 2, Flags: CMD_FAST,
+			Categories: []string{ACL_CONNECTION}, Group: GROUP_CONNECTION,
+			Summary: "Changes the selected database.",
+		},
+
+		// strings
+		{
+			Name: SET, Handler: h.Set, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Sets the string value of a key, ignoring its type. The key is created if it doesn't exist.",
+		},
+		{
+			Name: GET, Handler: h.Get, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns the string value of a key.",
+		},
+		{
+			Name: SETNX, Handler: h.SetNX, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Set the string value of a key only when the key doesn't exist.",
+		},
+		{
+			Name: SETEX, Handler: h.SetEx, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Sets the string value and expiration time of a key. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: PSETEX, Handler: h.PSetEx, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Sets both string value and expiration time in milliseconds of a key. The key is created if it doesn't exist.",
+		},
+		{
+			Name: GETSET, Handler: h.GetSet, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns the previous string value of a key after setting it to a new value.",
+		},
+		{
+			Name: GETDEL, Handler: h.GetDel, Arity: 2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns the string value of a key after deleting the key.",
+		},
+		{
+			Name: GETEX, Handler: h.GetEx, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING, PropagatesEffects: true,
+			Summary: "Returns the string value of a key after setting its expiration time.",
+		},
+		{
+			Name: APPEND, Handler: h.Append, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Appends a string to the value of a key. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: STRLEN, Handler: h.StrLen, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns the length of a string value.",
+		},
+		{
+			Name: GETRANGE, Handler: h.GetRange, Arity: 4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns a substring of the string stored at a key.",
+		},
+		{
+			Name: SUBSTR, Handler: h.GetRange, Arity: 4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Returns a substring from a string value.",
+		},
+		{
+			Name: SETRANGE, Handler: h.SetRange, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Overwrites a part of a string value with another by an offset. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: LCS, Handler: h.LCS, Arity: -3, Flags: CMD_READONLY, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Finds the longest common substring.",
+		},
+		{
+			Name: INCR, Handler: h.Incr, Arity: 2, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Increments the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+		},
+		{
+			Name: DECR, Handler: h.Decr, Arity: 2, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Decrements the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+		},
+		{
+			Name: INCRBY, Handler: h.IncrBy, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Increments the integer value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+		},
+		{
+			Name: DECRBY, Handler: h.DecrBy, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Decrements a number from the integer value of a key. Uses 0 as initial value if the key doesn't exist.",
+		},
+		{
+			Name: INCRBYFLOAT, Handler: h.IncrByFloat, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING, PropagatesEffects: true,
+			Summary: "Increment the floating point value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+		},
+		{
+			Name: MGET, Handler: h.MGet, Arity: -2, Flags: CMD_READONLY | CMD_FAST, Keys: allKeys,
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Atomically returns the string values of one or more keys.",
+		},
+		{
+			Name: MSET, Handler: h.MSet, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: -1, Step: 2},
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Atomically creates or modifies the string values of one or more keys.",
+		},
+		{
+			Name: MSETNX, Handler: h.MSetNX, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: -1, Step: 2},
+			Categories: []string{ACL_STRING}, Group: GROUP_STRING,
+			Summary: "Atomically modifies the string values of one or more keys only when all keys don't exist.",
+		},
+
+		// bitmaps
+		{
+			Name: SETBIT, Handler: h.SetBit, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Sets or clears the bit at offset of the string value. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: GETBIT, Handler: h.GetBit, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Returns a bit value by offset.",
+		},
+		{
+			Name: BITCOUNT, Handler: h.BitCount, Arity: -2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Counts the number of set bits (population counting) in a string.",
+		},
+		{
+			Name: BITPOS, Handler: h.BitPos, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Finds the first set (1) or clear (0) bit in a string.",
+		},
+		{
+			Name: BITOP, Handler: h.BitOp, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 2, Last: -1, Step: 1},
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Performs bitwise operations on multiple strings, and stores the result.",
+		},
+		{
+			Name: BITFIELD, Handler: h.Bitfield, Arity: -2, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Performs arbitrary bitfield integer operations on strings.",
+		},
+		{
+			Name: BITFIELD_RO, Handler: h.BitfieldRO, Arity: -2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_BITMAP}, Group: GROUP_BITMAP,
+			Summary: "Performs arbitrary read-only bitfield integer operations on strings.",
+		},
+
+		// hyperloglogs
+		{
+			Name: PFADD, Handler: h.PFAdd, Arity: -2, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HYPERLOGLOG}, Group: GROUP_HYPERLOGLOG,
+			Summary: "Adds elements to a HyperLogLog key. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: PFCOUNT, Handler: h.PFCount, Arity: -2, Flags: CMD_READONLY, Keys: allKeys,
+			Categories: []string{ACL_HYPERLOGLOG}, Group: GROUP_HYPERLOGLOG,
+			Summary: "Returns the approximated cardinality of the set(s) observed by the HyperLogLog key(s).",
+		},
+		{
+			Name: PFMERGE, Handler: h.PFMerge, Arity: -2, Flags: CMD_WRITE | CMD_DENYOOM, Keys: allKeys,
+			Categories: []string{ACL_HYPERLOGLOG}, Group: GROUP_HYPERLOGLOG,
+			Summary: "Merges one or more HyperLogLog values into a single key.",
+		},
+
+		// keys
+		{
+			Name: EXISTS, Handler: h.Exists, Arity: -2, Flags: CMD_READONLY | CMD_FAST, Keys: allKeys,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Determines whether one or more keys exist.",
+		},
+		{
+			Name: DEL, Handler: h.Delete, Arity: -2, Flags: CMD_WRITE, Keys: allKeys,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Deletes one or more keys.",
+		},
+		{
+			Name: UNLINK, Handler: h.Unlink, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: allKeys,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Asynchronously deletes one or more keys.",
+		},
+		{
+			Name: TOUCH, Handler: h.Touch, Arity: -2, Flags: CMD_READONLY | CMD_FAST, Keys: allKeys,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the number of existing keys out of those specified after updating the time they were last accessed.",
+		},
+		{
+			Name: TYPE, Handler: h.Type, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Determines the type of value stored at a key.",
+		},
+		{
+			Name: RENAME, Handler: h.Rename, Arity: 3, Flags: CMD_WRITE, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Renames a key and overwrites the destination.",
+		},
+		{
+			Name: RENAMENX, Handler: h.RenameNX, Arity: 3, Flags: CMD_WRITE | CMD_FAST, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Renames a key only when the target key name doesn't exist.",
+		},
+		{
+			Name: COPY, Handler: h.Copy, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Copies the value of a key to a new key.",
+		},
+		{
+			Name: MOVE, Handler: h.Move, Arity: 3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Moves a key to another database.",
+		},
+		{
+			Name: KEYS, Handler: h.Keys, Arity: 2, Flags: CMD_READONLY,
+			Categories: []string{ACL_KEYSPACE, ACL_DANGEROUS}, Group: GROUP_GENERIC,
+			Summary: "Returns all key names that match a pattern.",
+		},
+		{
+			Name: SCAN, Handler: h.Scan, Arity: -2, Flags: CMD_READONLY,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Iterates over the key names in the database.",
+		},
+		{
+			Name: RANDOMKEY, Handler: h.RandomKey, Arity: 1, Flags: CMD_READONLY,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns a random key name from the database.",
+		},
+		{
+			Name: OBJECT, Handler: h.Object, Arity: -2, Flags: CMD_READONLY, Keys: KeySpec{First: 2, Last: 2, Step: 1},
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the internal encoding, idle time, frequency or reference count of a Redis object.",
+		},
+		{
+			Name: EXPIRE, Handler: h.Expire, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Sets the expiration time of a key in seconds.",
+		},
+		{
+			Name: PEXPIRE, Handler: h.PExpire, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Sets the expiration time of a key in milliseconds.",
+		},
+		{
+			Name: EXPIREAT, Handler: h.ExpireAt, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Sets the expiration time of a key to a Unix timestamp.",
+		},
+		{
+			Name: PEXPIREAT, Handler: h.PExpireAt, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Sets the expiration time of a key to a Unix milliseconds timestamp.",
+		},
+		{
+			Name: TTL, Handler: h.TTL, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the expiration time in seconds of a key.",
+		},
+		{
+			Name: PTTL, Handler: h.PTTL, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the expiration time in milliseconds of a key.",
+		},
+		{
+			Name: EXPIRETIME, Handler: h.ExpireTime, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the expiration time of a key as a Unix timestamp.",
+		},
+		{
+			Name: PEXPIRETIME, Handler: h.PExpireTime, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Returns the expiration time of a key as a Unix milliseconds timestamp.",
+		},
+		{
+			Name: PERSIST, Handler: h.Persist, Arity: 2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_GENERIC,
+			Summary: "Removes the expiration time of a key.",
+		},
+
+		// server
+		{
+			Name: DBSIZE, Handler: h.DBSize, Arity: 1, Flags: CMD_READONLY | CMD_FAST,
+			Categories: []string{ACL_KEYSPACE}, Group: GROUP_SERVER,
+			Summary: "Returns the number of keys in the database.",
+		},
+		{
+			Name: SWAPDB, Handler: h.SwapDB, Arity: 3, Flags: CMD_WRITE | CMD_FAST,
+			Categories: []string{ACL_KEYSPACE, ACL_DANGEROUS}, Group: GROUP_SERVER,
+			Summary: "Swaps two Redis databases.",
+		},
+		{
+			Name: FLUSHDB, Handler: h.FlushDB, Arity: -1, Flags: CMD_WRITE,
+			Categories: []string{ACL_KEYSPACE, ACL_DANGEROUS}, Group: GROUP_SERVER,
+			Summary: "Remove all keys from the current database.",
+		},
+		{
+			Name: FLUSHALL, Handler: h.FlushAll, Arity: -1, Flags: CMD_WRITE,
+			Categories: []string{ACL_KEYSPACE, ACL_DANGEROUS}, Group: GROUP_SERVER,
+			Summary: "Removes all keys from all databases.",
+		},
+		{
+			Name: SAVE, Handler: h.Save, Arity: 1, Flags: CMD_ADMIN,
+			Group:   GROUP_SERVER,
+			Summary: "Synchronously saves the database(s) to disk.",
+		},
+		{
+			Name: BGSAVE, Handler: h.BgSave, Arity: -1, Flags: CMD_ADMIN,
+			Group:   GROUP_SERVER,
+			Summary: "Asynchronously saves the database(s) to disk.",
+		},
+		{
+			Name: LASTSAVE, Handler: h.LastSave, Arity: 1, Flags: CMD_FAST,
+			Categories: []string{ACL_ADMIN, ACL_DANGEROUS}, Group: GROUP_SERVER,
+			Summary: "Returns the Unix timestamp of the last successful save to disk.",
+		},
+		{
+			Name: COMMAND, Handler: h.Command, Arity: -1,
+			Categories: []string{ACL_CONNECTION}, Group: GROUP_SERVER,
+			Summary: "Returns detailed information about all commands.",
+		},
+
+		// lists
+		{
+			Name: LPUSH, Handler: h.Lpush, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Prepends one or more elements to a list. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: RPUSH, Handler: h.Rpush, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Appends one or more elements to a list. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: LPUSHX, Handler: h.Lpushx, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Prepends one or more elements to a list only when the list exists.",
+		},
+		{
+			Name: RPUSHX, Handler: h.Rpushx, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Appends an element to a list only when the list exists.",
+		},
+		{
+			Name: LPOP, Handler: h.LPop, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns the first elements in a list after removing it. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: RPOP, Handler: h.RPop, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns and removes the last elements of a list. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: LRANGE, Handler: h.LRange, Arity: 4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns a range of elements from a list.",
+		},
+		{
+			Name: LLEN, Handler: h.LLen, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns the length of a list.",
+		},
+		{
+			Name: LINDEX, Handler: h.LIndex, Arity: 3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns an element from a list by its index.",
+		},
+		{
+			Name: LSET, Handler: h.LSet, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Sets the value of an element in a list by its index.",
+		},
+		{
+			Name: LINSERT, Handler: h.LInsert, Arity: 5, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Inserts an element before or after another element in a list.",
+		},
+		{
+			Name: LREM, Handler: h.LRem, Arity: 4, Flags: CMD_WRITE, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Removes elements from a list. Deletes the list if the last element was removed.",
+		},
+		{
+			Name: LTRIM, Handler: h.LTrim, Arity: 4, Flags: CMD_WRITE, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Removes elements from both ends a list. Deletes the list if all elements were trimmed.",
+		},
+		{
+			Name: LPOS, Handler: h.LPos, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns the index of matching elements in a list.",
+		},
+		{
+			Name: LMOVE, Handler: h.LMove, Arity: 5, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns an element after popping it from one list and pushing it to another. Deletes the list if the last element was moved.",
+		},
+		{
+			Name: RPOPLPUSH, Handler: h.RPopLPush, Arity: 3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST,
+			Summary: "Returns the last element of a list after removing and pushing it to another list. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: LMPOP, Handler: h.LMPop, Arity: -4, Flags: CMD_WRITE, Keys: KeySpec{Find: countedKeys(0, false)},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Returns multiple elements from a list after removing them. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: BLPOP, Handler: h.BLPop, Arity: -3, Flags: CMD_WRITE | CMD_BLOCKING, Keys: KeySpec{First: 1, Last: -2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Removes and returns the first element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: BRPOP, Handler: h.BRPop, Arity: -3, Flags: CMD_WRITE | CMD_BLOCKING, Keys: KeySpec{First: 1, Last: -2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Removes and returns the last element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: BLMPOP, Handler: h.BLMPop, Arity: -5, Flags: CMD_WRITE | CMD_BLOCKING, Keys: KeySpec{Find: countedKeys(1, false)},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Pops the first element from one of multiple lists. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+		},
+		{
+			Name: BLMOVE, Handler: h.BLMove, Arity: 6, Flags: CMD_WRITE | CMD_DENYOOM | CMD_BLOCKING, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Pops an element from a list, pushes it to another list and returns it. Blocks until an element is available otherwise. Deletes the list if the last element was moved.",
+		},
+		{
+			Name: BRPOPLPUSH, Handler: h.BRPopLPush, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_BLOCKING, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_LIST}, Group: GROUP_LIST, PropagatesEffects: true,
+			Summary: "Pops an element from a list, pushes it to another list and returns it. Block until an element is available otherwise. Deletes the list if the last element was popped.",
+		},
+
+		// hashes
+		{
+			Name: HSET, Handler: h.HSet, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Creates or modifies the value of a field in a hash.",
+		},
+		{
+			Name: HMSET, Handler: h.HMSet, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Sets the values of multiple fields.",
+		},
+		{
+			Name: HSETNX, Handler: h.HSetNX, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Sets the value of a field in a hash only when the field doesn't exist.",
+		},
+		{
+			Name: HGET, Handler: h.HGet, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns the value of a field in a hash.",
+		},
+		{
+			Name: HMGET, Handler: h.HMGet, Arity: -3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns the values of all fields in a hash.",
+		},
+		{
+			Name: HGETALL, Handler: h.HGetAll, Arity: 2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns all fields and values in a hash.",
+		},
+		{
+			Name: HDEL, Handler: h.HDel, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Deletes one or more fields and their values from a hash. Deletes the hash if no fields remain.",
+		},
+		{
+			Name: HEXISTS, Handler: h.HExists, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Determines whether a field exists in a hash.",
+		},
+		{
+			Name: HLEN, Handler: h.HLen, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns the number of fields in a hash.",
+		},
+		{
+			Name: HSTRLEN, Handler: h.HStrLen, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns the length of the value of a field.",
+		},
+		{
+			Name: HKEYS, Handler: h.HKeys, Arity: 2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns all fields in a hash.",
+		},
+		{
+			Name: HVALS, Handler: h.HVals, Arity: 2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns all values in a hash.",
+		},
+		{
+			Name: HINCRBY, Handler: h.HIncrBy, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Increments the integer value of a field in a hash by a number. Uses 0 as initial value if the field doesn't exist.",
+		},
+		{
+			Name: HINCRBYFLOAT, Handler: h.HIncrByFloat, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Increments the floating point value of a field by a number. Uses 0 as initial value if the field doesn't exist.",
+		},
+		{
+			Name: HRANDFIELD, Handler: h.HRandField, Arity: -2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Returns one or more random fields from a hash.",
+		},
+		{
+			Name: HSCAN, Handler: h.HScan, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_HASH}, Group: GROUP_HASH,
+			Summary: "Iterates over fields and values of a hash.",
+		},
+
+		// sets
+		{
+			Name: SADD, Handler: h.SAdd, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Adds one or more members to a set. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: SREM, Handler: h.SRem, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Removes one or more members from a set. Deletes the set if the last member was removed.",
+		},
+		{
+			Name: SMEMBERS, Handler: h.SMembers, Arity: 2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns all members of a set.",
+		},
+		{
+			Name: SISMEMBER, Handler: h.SIsMember, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Determines whether a member belongs to a set.",
+		},
+		{
+			Name: SMISMEMBER, Handler: h.SMIsMember, Arity: -3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Determines whether multiple members belong to a set.",
+		},
+		{
+			Name: SCARD, Handler: h.SCard, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns the number of members in a set.",
+		},
+		{
+			Name: SPOP, Handler: h.SPop, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET, PropagatesEffects: true,
+			Summary: "Returns one or more random members from a set after removing them. Deletes the set if the last member was popped.",
+		},
+		{
+			Name: SRANDMEMBER, Handler: h.SRandMember, Arity: -2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Get one or multiple random members from a set",
+		},
+		{
+			Name: SMOVE, Handler: h.SMove, Arity: 4, Flags: CMD_WRITE | CMD_FAST, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Moves a member from one set to another.",
+		},
+		{
+			Name: SINTER, Handler: h.SInter, Arity: -2, Flags: CMD_READONLY, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns the intersect of multiple sets.",
+		},
+		{
+			Name: SUNION, Handler: h.SUnion, Arity: -2, Flags: CMD_READONLY, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns the union of multiple sets.",
+		},
+		{
+			Name: SDIFF, Handler: h.SDiff, Arity: -2, Flags: CMD_READONLY, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns the difference of multiple sets.",
+		},
+		{
+			Name: SINTERSTORE, Handler: h.SInterStore, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Stores the intersect of multiple sets in a key.",
+		},
+		{
+			Name: SUNIONSTORE, Handler: h.SUnionStore, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Stores the union of multiple sets in a key.",
+		},
+		{
+			Name: SDIFFSTORE, Handler: h.SDiffStore, Arity: -3, Flags: CMD_WRITE | CMD_DENYOOM, Keys: allKeys,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Stores the difference of multiple sets in a key.",
+		},
+		{
+			Name: SINTERCARD, Handler: h.SInterCard, Arity: -3, Flags: CMD_READONLY, Keys: KeySpec{Find: countedKeys(0, false)},
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Returns the number of members of the intersect of multiple sets.",
+		},
+		{
+			Name: SSCAN, Handler: h.SScan, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SET}, Group: GROUP_SET,
+			Summary: "Iterates over members of a set.",
+		},
+
+		// sorted sets
+		{
+			Name: ZADD, Handler: h.ZAdd, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Adds one or more members to a sorted set, or updates their scores. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: ZINCRBY, Handler: h.ZIncrBy, Arity: 4, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Increments the score of a member in a sorted set.",
+		},
+		{
+			Name: ZREM, Handler: h.ZRem, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Removes one or more members from a sorted set. Deletes the sorted set if all members were removed.",
+		},
+		{
+			Name: ZSCORE, Handler: h.ZScore, Arity: 3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the score of a member in a sorted set.",
+		},
+		{
+			Name: ZMSCORE, Handler: h.ZMScore, Arity: -3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the score of one or more members in a sorted set.",
+		},
+		{
+			Name: ZCARD, Handler: h.ZCard, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the number of members in a sorted set.",
+		},
+		{
+			Name: ZRANK, Handler: h.ZRank, Arity: -3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the index of a member in a sorted set ordered by ascending scores.",
+		},
+		{
+			Name: ZREVRANK, Handler: h.ZRevRank, Arity: -3, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the index of a member in a sorted set ordered by descending scores.",
+		},
+		{
+			Name: ZRANGE, Handler: h.ZRange, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a range of indexes.",
+		},
+		{
+			Name: ZREVRANGE, Handler: h.ZRevRange, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a range of indexes in reverse order.",
+		},
+		{
+			Name: ZRANGEBYSCORE, Handler: h.ZRangeByScore, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a range of scores.",
+		},
+		{
+			Name: ZREVRANGEBYSCORE, Handler: h.ZRevRangeByScore, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a range of scores in reverse order.",
+		},
+		{
+			Name: ZRANGEBYLEX, Handler: h.ZRangeByLex, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a lexicographical range.",
+		},
+		{
+			Name: ZREVRANGEBYLEX, Handler: h.ZRevRangeByLex, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns members in a sorted set within a lexicographical range in reverse order.",
+		},
+		{
+			Name: ZRANGESTORE, Handler: h.ZRangeStore, Arity: -5, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Stores a range of members from sorted set in a key.",
+		},
+		{
+			Name: ZCOUNT, Handler: h.ZCount, Arity: 4, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the count of members in a sorted set that have scores within a range.",
+		},
+		{
+			Name: ZLEXCOUNT, Handler: h.ZLexCount, Arity: 4, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the number of members in a sorted set within a lexicographical range.",
+		},
+		{
+			Name: ZPOPMIN, Handler: h.ZPopMin, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the lowest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.",
+		},
+		{
+			Name: ZPOPMAX, Handler: h.ZPopMax, Arity: -2, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Returns the highest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.",
+		},
+		{
+			Name: ZUNIONSTORE, Handler: h.ZUnionStore, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 1, Step: 1, Find: countedKeys(1, true)},
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Stores the union of multiple sorted sets in a key.",
+		},
+		{
+			Name: ZINTERSTORE, Handler: h.ZInterStore, Arity: -4, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 1, Step: 1, Find: countedKeys(1, true)},
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Stores the intersect of multiple sorted sets in a key.",
+		},
+		{
+			Name: ZSCAN, Handler: h.ZScan, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_SORTEDSET}, Group: GROUP_SORTED_SET,
+			Summary: "Iterates over members and scores of a sorted set.",
+		},
+
+		// streams
+		{
+			Name: XADD, Handler: h.XAdd, Arity: -5, Flags: CMD_WRITE | CMD_DENYOOM | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM, PropagatesEffects: true,
+			Summary: "Appends a new message to a stream. Creates the key if it doesn't exist.",
+		},
+		{
+			Name: XLEN, Handler: h.XLen, Arity: 2, Flags: CMD_READONLY | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Return the number of messages in a stream.",
+		},
+		{
+			Name: XRANGE, Handler: h.XRange, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns the messages from a stream within a range of IDs.",
+		},
+		{
+			Name: XREVRANGE, Handler: h.XRevRange, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns the messages from a stream within a range of IDs in reverse order.",
+		},
+		{
+			Name: XDEL, Handler: h.XDel, Arity: -3, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns the number of messages after removing them from a stream.",
+		},
+		{
+			Name: XTRIM, Handler: h.XTrim, Arity: -4, Flags: CMD_WRITE, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM, PropagatesEffects: true,
+			Summary: "Deletes messages from the beginning of a stream.",
+		},
+		{
+			Name: XREAD, Handler: h.XRead, Arity: -4, Flags: CMD_READONLY | CMD_BLOCKING, Keys: KeySpec{Find: streamKeys(0)},
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns messages from multiple streams with IDs greater than the ones requested. Blocks until a message is available otherwise.",
+		},
+		{
+			Name: XGROUP, Handler: h.XGroup, Arity: -2, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 2, Last: 2, Step: 1},
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Creates, destroys or modifies the consumer groups and consumers of a stream.",
+		},
+		{
+			Name: XREADGROUP, Handler: h.XReadGroup, Arity: -7, Flags: CMD_WRITE | CMD_BLOCKING, Keys: KeySpec{Find: streamKeys(3)},
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM, PropagatesEffects: true,
+			Summary: "Returns new or historical messages from a stream for a consumer in a group. Blocks until a message is available otherwise.",
+		},
+		{
+			Name: XACK, Handler: h.XAck, Arity: -4, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns the number of messages that were successfully acknowledged by the consumer group member of a stream.",
+		},
+		{
+			Name: XPENDING, Handler: h.XPending, Arity: -3, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns the information and entries from a stream consumer group's pending entries list.",
+		},
+		{
+			Name: XCLAIM, Handler: h.XClaim, Arity: -6, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM, PropagatesEffects: true,
+			Summary: "Changes, or acquires, ownership of a message in a consumer group, as if the message was delivered a consumer group member.",
+		},
+		{
+			Name: XAUTOCLAIM, Handler: h.XAutoClaim, Arity: -6, Flags: CMD_WRITE | CMD_FAST, Keys: firstKey,
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM, PropagatesEffects: true,
+			Summary: "Changes, or acquires, ownership of messages in a consumer group, as if the messages were delivered to as consumer group member.",
+		},
+		{
+			Name: XINFO, Handler: h.XInfo, Arity: -2, Flags: CMD_READONLY, Keys: KeySpec{First: 2, Last: 2, Step: 1},
+			Categories: []string{ACL_STREAM}, Group: GROUP_STREAM,
+			Summary: "Returns information about a stream, its consumer groups or the consumers of a group.",
+		},
+
+		// geo indexes
+		{
+			Name: GEOADD, Handler: h.GeoAdd, Arity: -5, Flags: CMD_WRITE | CMD_DENYOOM, Keys: firstKey,
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Adds one or more members to a geospatial index. The key is created if it doesn't exist.",
+		},
+		{
+			Name: GEOPOS, Handler: h.GeoPos, Arity: -2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Returns the longitude and latitude of members from a geospatial index.",
+		},
+		{
+			Name: GEODIST, Handler: h.GeoDist, Arity: -4, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Returns the distance between two members of a geospatial index.",
+		},
+		{
+			Name: GEOHASH, Handler: h.GeoHash, Arity: -2, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Returns members from a geospatial index as geohash strings.",
+		},
+		{
+			Name: GEOSEARCH, Handler: h.GeoSearch, Arity: -7, Flags: CMD_READONLY, Keys: firstKey,
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Queries a geospatial index for members inside an area of a box or a circle.",
+		},
+		{
+			Name: GEOSEARCHSTORE, Handler: h.GeoSearchStore, Arity: -8, Flags: CMD_WRITE | CMD_DENYOOM, Keys: KeySpec{First: 1, Last: 2, Step: 1},
+			Categories: []string{ACL_GEO}, Group: GROUP_GEO,
+			Summary: "Queries a geospatial index for members inside an area of a box or a circle, optionally stores the result.",
+		},
+
+		// pub/sub
+		{
+			Name: SUBSCRIBE, Handler: h.Subscribe, Arity: -2, Flags: CMD_PUBSUB,
+			Group:   GROUP_PUBSUB,
+			Summary: "Listens for messages published to channels.",
+		},
+		{
+			Name: PSUBSCRIBE, Handler: h.PSubscribe, Arity: -2, Flags: CMD_PUBSUB,
+			Group:   GROUP_PUBSUB,
+			Summary: "Listens for messages published to channels that match one or more patterns.",
+		},
+		{
+			Name: UNSUBSCRIBE, Handler: h.Unsubscribe, Arity: -1, Flags: CMD_PUBSUB,
+			Group:   GROUP_PUBSUB,
+			Summary: "Stops listening to messages posted to channels.",
+		},
+		{
+			Name: PUNSUBSCRIBE, Handler: h.PUnsubscribe, Arity: -1, Flags: CMD_PUBSUB,
+			Group:   GROUP_PUBSUB,
+			Summary: "Stops listening to messages published to channels that match one or more patterns.",
+		},
+		{
+			Name: PUBLISH, Handler: h.Publish, Arity: 3, Flags: CMD_PUBSUB | CMD_FAST,
+			Group:   GROUP_PUBSUB,
+			Summary: "Posts a message to a channel.",
+		},
+		{
+			Name: PUBSUB, Handler: h.PubSub, Arity: -2, Flags: CMD_PUBSUB,
+			Group:   GROUP_PUBSUB,
+			Summary: "Inspects the state of the Pub/Sub subsystem.",
+		},
+
+		// transactions
+		{
+			Name: MULTI, Handler: h.Multi, Arity: 1, Flags: CMD_FAST,
+			Categories: []string{ACL_TRANSACTION}, Group: GROUP_TRANSACTIONS,
+			Summary: "Starts a transaction.",
+		},
+		{
+			Name: EXEC, Handler: h.Exec, Arity: 1,
+			Categories: []string{ACL_TRANSACTION}, Group: GROUP_TRANSACTIONS,
+			Summary: "Executes all commands in a transaction.",
+		},
+		{
+			Name: DISCARD, Handler: h.Discard, Arity: 1, Flags: CMD_FAST,
+			Categories: []string{ACL_TRANSACTION}, Group: GROUP_TRANSACTIONS,
+			Summary: "Discards a transaction.",
+		},
+		{
+			Name: WATCH, Handler: h.Watch, Arity: -2, Flags: CMD_FAST, Keys: allKeys,
+			Categories: []string{ACL_TRANSACTION}, Group: GROUP_TRANSACTIONS,
+			Summary: "Monitors changes to keys to determine the execution of a transaction.",
+		},
+		{
+			Name: UNWATCH, Handler: h.Unwatch, Arity: 1, Flags: CMD_FAST,
+			Categories: []string{ACL_TRANSACTION}, Group: GROUP_TRANSACTIONS,
+			Summary: "Forgets about watched keys of a transaction.",
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Hello switches the protocol of the connection and replies with the server details.
+// HELLO [protover [AUTH username password] [SETNAME clientname]]
+func (h *Handler) Hello(client *Client, args ...any) ([]byte, error) {
+	protocol := client.Protocol
+
+	if len(args) > 0 {
+		version, err := strconv.Atoi(args[0].(string))
+
+		if err != nil {
+			return nil, errors.New("ERR Protocol version is not an integer or out of range")
+		}
+
+		if version != resp.RESP2 && version != resp.RESP3 {
+			return nil, errors.New("NOPROTO unsupported protocol version")
+		}
+
+		protocol = version
+	}
+
+	var clientName string
+	setName := false
+
+	for i := 1; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+		remaining := len(args) - i - 1
+
+		switch {
+		case option == "AUTH" && remaining >= 2:
+			// there is no user management, only the default user without a password exists
+			if args[i+1].(string) != "default" {
+				return nil, errors.New("WRONGPASS invalid username-password pair or user is disabled.")
+			}
+
+			i += 2
+		case option == "SETNAME" && remaining >= 1:
+			clientName = args[i+1].(string)
+
+			if strings.ContainsAny(clientName, " \n") {
+				return nil, errors.New("ERR Client names cannot contain spaces, newlines or special characters.")
+			}
+
+			setName = true
+			i++
+		default:
+			return nil, errors.New("ERR Syntax error in HELLO option '" + args[i].(string) + "'")
+		}
+	}
+
+	client.Protocol = protocol
+
+	if setName {
+		client.Name = clientName
+	}
+
+	details := []resp.MapType{
+		mapEntry("server", resp.BULK_STRING, SERVER_NAME),
+		mapEntry("version", resp.BULK_STRING, SERVER_VERSION),
+		mapEntry("proto", resp.INTEGER, client.Protocol),
+		mapEntry("id", resp.INTEGER, int(client.ID)),
+		mapEntry("mode", resp.BULK_STRING, "standalone"),
+		mapEntry("role", resp.BULK_STRING, "master"),
+		mapEntry("modules", resp.ARRAY, []resp.ArrayType{}),
+	}
+
+	data, err := client.Serialize(resp.MAP, details)
+
+	return data, err
+}
+
+// Disconnect releases the state held for a client once its connection is closed
+func (h *Handler) Disconnect(client *Client) {
+	h.unsubscribeAll(client)
+	h.unwatch(client)
+}
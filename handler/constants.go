@@ -13,8 +13,210 @@ const (
 	LRANGE string = "LRANGE"
 	LPUSH  string = "LPUSH"
 	RPUSH  string = "RPUSH"
+	HELLO  string = "HELLO"
+
+	LPUSHX    string = "LPUSHX"
+	RPUSHX    string = "RPUSHX"
+	LPOP      string = "LPOP"
+	RPOP      string = "RPOP"
+	LLEN      string = "LLEN"
+	LINDEX    string = "LINDEX"
+	LSET      string = "LSET"
+	LINSERT   string = "LINSERT"
+	LREM      string = "LREM"
+	LTRIM     string = "LTRIM"
+	LPOS      string = "LPOS"
+	LMOVE     string = "LMOVE"
+	RPOPLPUSH string = "RPOPLPUSH"
+	LMPOP     string = "LMPOP"
+
+	BLPOP      string = "BLPOP"
+	BRPOP      string = "BRPOP"
+	BLMPOP     string = "BLMPOP"
+	BLMOVE     string = "BLMOVE"
+	BRPOPLPUSH string = "BRPOPLPUSH"
+
+	EXPIRE      string = "EXPIRE"
+	PEXPIRE     string = "PEXPIRE"
+	EXPIREAT    string = "EXPIREAT"
+	PEXPIREAT   string = "PEXPIREAT"
+	TTL         string = "TTL"
+	PTTL        string = "PTTL"
+	EXPIRETIME  string = "EXPIRETIME"
+	PEXPIRETIME string = "PEXPIRETIME"
+	PERSIST     string = "PERSIST"
+
+	SAVE     string = "SAVE"
+	BGSAVE   string = "BGSAVE"
+	LASTSAVE string = "LASTSAVE"
+
+	HSET         string = "HSET"
+	HMSET        string = "HMSET"
+	HSETNX       string = "HSETNX"
+	HGET         string = "HGET"
+	HMGET        string = "HMGET"
+	HGETALL      string = "HGETALL"
+	HDEL         string = "HDEL"
+	HEXISTS      string = "HEXISTS"
+	HLEN         string = "HLEN"
+	HSTRLEN      string = "HSTRLEN"
+	HKEYS        string = "HKEYS"
+	HVALS        string = "HVALS"
+	HINCRBY      string = "HINCRBY"
+	HINCRBYFLOAT string = "HINCRBYFLOAT"
+	HRANDFIELD   string = "HRANDFIELD"
+	HSCAN        string = "HSCAN"
+
+	SADD        string = "SADD"
+	SREM        string = "SREM"
+	SMEMBERS    string = "SMEMBERS"
+	SISMEMBER   string = "SISMEMBER"
+	SMISMEMBER  string = "SMISMEMBER"
+	SCARD       string = "SCARD"
+	SPOP        string = "SPOP"
+	SRANDMEMBER string = "SRANDMEMBER"
+	SMOVE       string = "SMOVE"
+	SINTER      string = "SINTER"
+	SUNION      string = "SUNION"
+	SDIFF       string = "SDIFF"
+	SINTERSTORE string = "SINTERSTORE"
+	SUNIONSTORE string = "SUNIONSTORE"
+	SDIFFSTORE  string = "SDIFFSTORE"
+	SINTERCARD  string = "SINTERCARD"
+	SSCAN       string = "SSCAN"
+
+	ZADD             string = "ZADD"
+	ZINCRBY          string = "ZINCRBY"
+	ZREM             string = "ZREM"
+	ZSCORE           string = "ZSCORE"
+	ZMSCORE          string = "ZMSCORE"
+	ZCARD            string = "ZCARD"
+	ZRANK            string = "ZRANK"
+	ZREVRANK         string = "ZREVRANK"
+	ZRANGE           string = "ZRANGE"
+	ZREVRANGE        string = "ZREVRANGE"
+	ZRANGEBYSCORE    string = "ZRANGEBYSCORE"
+	ZREVRANGEBYSCORE string = "ZREVRANGEBYSCORE"
+	ZRANGEBYLEX      string = "ZRANGEBYLEX"
+	ZREVRANGEBYLEX   string = "ZREVRANGEBYLEX"
+	ZRANGESTORE      string = "ZRANGESTORE"
+	ZCOUNT           string = "ZCOUNT"
+	ZLEXCOUNT        string = "ZLEXCOUNT"
+	ZPOPMIN          string = "ZPOPMIN"
+	ZPOPMAX          string = "ZPOPMAX"
+	ZUNIONSTORE      string = "ZUNIONSTORE"
+	ZINTERSTORE      string = "ZINTERSTORE"
+	ZSCAN            string = "ZSCAN"
+
+	SUBSCRIBE    string = "SUBSCRIBE"
+	PSUBSCRIBE   string = "PSUBSCRIBE"
+	UNSUBSCRIBE  string = "UNSUBSCRIBE"
+	PUNSUBSCRIBE string = "PUNSUBSCRIBE"
+	PUBLISH      string = "PUBLISH"
+	PUBSUB       string = "PUBSUB"
+
+	MULTI   string = "MULTI"
+	EXEC    string = "EXEC"
+	DISCARD string = "DISCARD"
+	WATCH   string = "WATCH"
+	UNWATCH string = "UNWATCH"
+
+	SELECT   string = "SELECT"
+	SWAPDB   string = "SWAPDB"
+	MOVE     string = "MOVE"
+	FLUSHDB  string = "FLUSHDB"
+	FLUSHALL string = "FLUSHALL"
+	DBSIZE   string = "DBSIZE"
+
+	KEYS      string = "KEYS"
+	SCAN      string = "SCAN"
+	RANDOMKEY string = "RANDOMKEY"
+
+	TYPE     string = "TYPE"
+	RENAME   string = "RENAME"
+	RENAMENX string = "RENAMENX"
+	COPY     string = "COPY"
+	UNLINK   string = "UNLINK"
+	TOUCH    string = "TOUCH"
+	OBJECT   string = "OBJECT"
+
+	SETNX  string = "SETNX"
+	SETEX  string = "SETEX"
+	PSETEX string = "PSETEX"
+	GETSET string = "GETSET"
+	GETDEL string = "GETDEL"
+	GETEX  string = "GETEX"
+
+	APPEND   string = "APPEND"
+	STRLEN   string = "STRLEN"
+	GETRANGE string = "GETRANGE"
+	SUBSTR   string = "SUBSTR"
+	SETRANGE string = "SETRANGE"
+	LCS      string = "LCS"
+
+	INCRBY      string = "INCRBY"
+	DECRBY      string = "DECRBY"
+	INCRBYFLOAT string = "INCRBYFLOAT"
+
+	MGET   string = "MGET"
+	MSET   string = "MSET"
+	MSETNX string = "MSETNX"
+
+	SETBIT      string = "SETBIT"
+	GETBIT      string = "GETBIT"
+	BITCOUNT    string = "BITCOUNT"
+	BITPOS      string = "BITPOS"
+	BITOP       string = "BITOP"
+	BITFIELD    string = "BITFIELD"
+	BITFIELD_RO string = "BITFIELD_RO"
+
+	PFADD   string = "PFADD"
+	PFCOUNT string = "PFCOUNT"
+	PFMERGE string = "PFMERGE"
+
+	XADD       string = "XADD"
+	XLEN       string = "XLEN"
+	XRANGE     string = "XRANGE"
+	XREVRANGE  string = "XREVRANGE"
+	XDEL       string = "XDEL"
+	XTRIM      string = "XTRIM"
+	XREAD      string = "XREAD"
+	XGROUP     string = "XGROUP"
+	XREADGROUP string = "XREADGROUP"
+	XACK       string = "XACK"
+	XPENDING   string = "XPENDING"
+	XCLAIM     string = "XCLAIM"
+	XAUTOCLAIM string = "XAUTOCLAIM"
+	XINFO      string = "XINFO"
+
+	GEOADD         string = "GEOADD"
+	GEOPOS         string = "GEOPOS"
+	GEODIST        string = "GEODIST"
+	GEOHASH        string = "GEOHASH"
+	GEOSEARCH      string = "GEOSEARCH"
+	GEOSEARCHSTORE string = "GEOSEARCHSTORE"
+
+	COMMAND string = "COMMAND"
 )
 
-var WRITE_COMMANDS = []string{
-	SET, DEL, INCR, DECR, LPUSH, RPUSH,
+// Server details reported to clients
+const (
+	SERVER_NAME    string = "redis"
+	SERVER_VERSION string = "7.2.0"
+)
+
+// Commands a resp2 client can send once subscribed to a channel or pattern
+var SUBSCRIBED_MODE_COMMANDS = []string{
+	SUBSCRIBE, PSUBSCRIBE, UNSUBSCRIBE, PUNSUBSCRIBE, PING,
+}
+
+// Commands run right away by a client in a transaction, any other one is queued
+var TRANSACTION_COMMANDS = []string{
+	MULTI, EXEC, DISCARD, WATCH,
+}
+
+// Write commands setting a timeout relative to the time they are executed,
+// the append only file records the resulting absolute time right after them
+var RELATIVE_EXPIRE_COMMANDS = []string{
+	SET, SETEX, PSETEX, EXPIRE, PEXPIRE,
 }
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+var errDBIndex = errors.New("ERR DB index is out of range")
+
+// Select changes the database used by the following commands of the client
+// SELECT index
+func (h *Handler) Select(client *Client, args ...any) ([]byte, error) {
+	db, err := h.parseDB(args[0], errNotInteger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.DB = db
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// SwapDB exchanges the keys of two databases, clients see the keys of the
+// other database right away
+// SWAPDB index1 index2
+func (h *Handler) SwapDB(client *Client, args ...any) ([]byte, error) {
+	first, err := h.parseDB(args[0], errors.New("ERR invalid first DB index"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := h.parseDB(args[1], errors.New("ERR invalid second DB index"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	h.stores[first].Swap(h.stores[second])
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// Move moves a key to another database, replies 1 when it was moved
+// MOVE key db
+func (h *Handler) Move(client *Client, args ...any) ([]byte, error) {
+	db, err := h.parseDB(args[1], errNotInteger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if db == client.DB {
+		return nil, errors.New("ERR source and destination objects are the same")
+	}
+
+	moved := h.db(client).Move(args[0].(string), h.stores[db])
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(moved))
+	return data, err
+}
+
+// FlushDB removes every key of the selected database. Keys are always freed
+// by the garbage collector, so ASYNC and SYNC only exist for compatibility.
+// FLUSHDB [ASYNC | SYNC]
+func (h *Handler) FlushDB(client *Client, args ...any) ([]byte, error) {
+	if err := parseFlushMode(args); err != nil {
+		return nil, err
+	}
+
+	h.db(client).Flush()
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// FlushAll removes every key of every database
+// FLUSHALL [ASYNC | SYNC]
+func (h *Handler) FlushAll(client *Client, args ...any) ([]byte, error) {
+	if err := parseFlushMode(args); err != nil {
+		return nil, err
+	}
+
+	for _, store := range h.stores {
+		store.Flush()
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// DBSize replies with the number of keys of the selected database
+// DBSIZE
+func (h *Handler) DBSize(client *Client, args ...any) ([]byte, error) {
+	data, err := client.Serialize(resp.INTEGER, h.db(client).Size())
+	return data, err
+}
+
+// parseDB parses a database index, replying with notInteger when it is not a number
+func (h *Handler) parseDB(arg any, notInteger error) (int, error) {
+	db, err := strconv.Atoi(arg.(string))
+
+	if err != nil {
+		return 0, notInteger
+	}
+
+	if db < 0 || db >= len(h.stores) {
+		return 0, errDBIndex
+	}
+
+	return db, nil
+}
+
+func parseFlushMode(args []any) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	if len(args) > 1 {
+		return errSyntax
+	}
+
+	mode := strings.ToUpper(args[0].(string))
+
+	if mode != "ASYNC" && mode != "SYNC" {
+		return errSyntax
+	}
+
+	return nil
+}
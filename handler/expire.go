@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+func (h *Handler) Expire(client *Client, args ...any) ([]byte, error) {
+	return h.expire(client, "expire", time.Second, false, args...)
+}
+
+func (h *Handler) PExpire(client *Client, args ...any) ([]byte, error) {
+	return h.expire(client, "pexpire", time.Millisecond, false, args...)
+}
+
+func (h *Handler) ExpireAt(client *Client, args ...any) ([]byte, error) {
+	return h.expire(client, "expireat", time.Second, true, args...)
+}
+
+func (h *Handler) PExpireAt(client *Client, args ...any) ([]byte, error) {
+	return h.expire(client, "pexpireat", time.Millisecond, true, args...)
+}
+
+// expire implements the EXPIRE family. The given time is measured in unit and
+// is either relative to now or an absolute unix time.
+// EXPIRE key seconds [NX | XX | GT | LT]
+func (h *Handler) expire(client *Client, command string, unit time.Duration, absolute bool, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	value, err := strconv.ParseInt(args[1].(string), 10, 64)
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	condition, err := parseExpireCondition(args[2:]...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expireAt, err := absoluteExpireTime(command, value, unit, absolute)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := h.db(client).Expire(key, expireAt, condition)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(updated))
+
+	return data, err
+}
+
+// absoluteExpireTime converts a time measured in unit, relative to now unless
+// absolute is set, to a unix time in milliseconds
+func absoluteExpireTime(command string, value int64, unit time.Duration, absolute bool) (int64, error) {
+	multiplier := int64(unit / time.Millisecond)
+
+	if value > math.MaxInt64/multiplier || value < math.MinInt64/multiplier {
+		return 0, fmt.Errorf("ERR invalid expire time in '%s' command", command)
+	}
+
+	expireAt := value * multiplier
+
+	if !absolute {
+		now := time.Now().UnixMilli()
+
+		if expireAt > math.MaxInt64-now {
+			return 0, fmt.Errorf("ERR invalid expire time in '%s' command", command)
+		}
+
+		expireAt += now
+	}
+
+	return expireAt, nil
+}
+
+func parseExpireCondition(options ...any) (string, error) {
+	nx, xx, gt, lt := false, false, false, false
+
+	for _, option := range options {
+		switch strings.ToUpper(option.(string)) {
+		case data.EXPIRE_NX:
+			nx = true
+		case data.EXPIRE_XX:
+			xx = true
+		case data.EXPIRE_GT:
+			gt = true
+		case data.EXPIRE_LT:
+			lt = true
+		default:
+			return "", fmt.Errorf("ERR Unsupported option %s", option.(string))
+		}
+	}
+
+	if nx && (xx || gt || lt) {
+		return "", errors.New("ERR NX and XX, GT or LT options at the same time are not compatible")
+	}
+
+	if gt && lt {
+		return "", errors.New("ERR GT and LT options at the same time are not compatible")
+	}
+
+	switch {
+	case nx:
+		return data.EXPIRE_NX, nil
+	case gt:
+		return data.EXPIRE_GT, nil
+	case lt:
+		return data.EXPIRE_LT, nil
+	case xx:
+		return data.EXPIRE_XX, nil
+	}
+
+	return data.EXPIRE_ALWAYS, nil
+}
+
+func (h *Handler) TTL(client *Client, args ...any) ([]byte, error) {
+	ttl := h.db(client).TTL(args[0].(string))
+
+	if ttl >= 0 {
+		// round to the closest second
+		ttl = (ttl + 500) / 1000
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(ttl))
+
+	return data, err
+}
+
+func (h *Handler) PTTL(client *Client, args ...any) ([]byte, error) {
+	ttl := h.db(client).TTL(args[0].(string))
+
+	data, err := client.Serialize(resp.INTEGER, int(ttl))
+
+	return data, err
+}
+
+func (h *Handler) ExpireTime(client *Client, args ...any) ([]byte, error) {
+	expireAt := h.db(client).ExpireTime(args[0].(string))
+
+	if expireAt >= 0 {
+		expireAt /= 1000
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(expireAt))
+
+	return data, err
+}
+
+func (h *Handler) PExpireTime(client *Client, args ...any) ([]byte, error) {
+	expireAt := h.db(client).ExpireTime(args[0].(string))
+
+	data, err := client.Serialize(resp.INTEGER, int(expireAt))
+
+	return data, err
+}
+
+func (h *Handler) Persist(client *Client, args ...any) ([]byte, error) {
+	removed := h.db(client).Persist(args[0].(string))
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(removed))
+
+	return data, err
+}
@@ -0,0 +1,439 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/geo"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// meters per distance unit
+var geoUnits = map[string]float64{
+	"M":  1,
+	"KM": 1000,
+	"FT": 0.3048,
+	"MI": 1609.34,
+}
+
+var (
+	errGeoUnit       = errors.New("ERR unsupported unit provided. please use M, KM, FT, MI")
+	errGeoRadius     = errors.New("ERR need numeric radius")
+	errGeoNegRadius  = errors.New("ERR radius cannot be negative")
+	errGeoNegBox     = errors.New("ERR height or width cannot be negative")
+	errGeoCount      = errors.New("ERR COUNT must be > 0")
+	errGeoAnyNoCount = errors.New("ERR the ANY argument requires COUNT argument")
+)
+
+// GeoAdd adds members at their position, or moves them
+// GEOADD key [NX | XX] [CH] longitude latitude member [longitude latitude member ...]
+func (h *Handler) GeoAdd(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	flags := 0
+	changed := false
+	i := 1
+
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i].(string)) {
+		case "NX":
+			flags |= zset.ADD_NX
+		case "XX":
+			flags |= zset.ADD_XX
+		case "CH":
+			changed = true
+		default:
+			break options
+		}
+	}
+
+	triples := args[i:]
+
+	if len(triples) == 0 || len(triples)%3 != 0 || (flags&zset.ADD_NX != 0 && flags&zset.ADD_XX != 0) {
+		return nil, errSyntax
+	}
+
+	locations := []data.GeoLocation{}
+
+	for j := 0; j < len(triples); j += 3 {
+		point, err := parseGeoPoint(triples[j].(string), triples[j+1].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, data.GeoLocation{Member: triples[j+2].(string), Point: point})
+	}
+
+	added, moved, err := h.db(client).GeoAdd(key, locations, flags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		added += moved
+	}
+
+	data, err := client.Serialize(resp.INTEGER, added)
+	return data, err
+}
+
+// GeoPos replies with the positions of the members
+// GEOPOS key [member [member ...]]
+func (h *Handler) GeoPos(client *Client, args ...any) ([]byte, error) {
+	positions, err := h.db(client).GeoPos(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{}
+
+	for _, position := range positions {
+		if position == nil {
+			reply = append(reply, resp.ArrayType{Value: nil, Type: resp.ARRAY})
+			continue
+		}
+
+		reply = append(reply, coordinatesItem(*position))
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+	return data, err
+}
+
+// GeoDist replies with the distance between two members
+// GEODIST key member1 member2 [M | KM | FT | MI]
+func (h *Handler) GeoDist(client *Client, args ...any) ([]byte, error) {
+	if len(args) > 4 {
+		return nil, errors.New("ERR wrong number of arguments for 'geodist' command")
+	}
+
+	unit := geoUnits["M"]
+
+	if len(args) == 4 {
+		var err error
+
+		if unit, err = parseGeoUnit(args[3].(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	distance, found, err := h.db(client).GeoDist(args[0].(string), args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, formatDistance(distance, unit))
+	return data, err
+}
+
+// GeoHash replies with the standard geohash strings of the members
+// GEOHASH key [member [member ...]]
+func (h *Handler) GeoHash(client *Client, args ...any) ([]byte, error) {
+	positions, err := h.db(client).GeoPos(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{}
+
+	for _, position := range positions {
+		if position == nil {
+			reply = append(reply, resp.ArrayType{Value: nil, Type: resp.BULK_STRING})
+			continue
+		}
+
+		reply = append(reply, resp.ArrayType{Value: geo.Hash(*position), Type: resp.BULK_STRING})
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+	return data, err
+}
+
+// geoSearchRequest holds the parsed arguments of GEOSEARCH and GEOSEARCHSTORE,
+// unit being the meters per unit of the distances given and replied
+type geoSearchRequest struct {
+	query     data.GeoQuery
+	unit      float64
+	withDist  bool
+	withHash  bool
+	withCoord bool
+	storeDist bool
+}
+
+// GeoSearch replies with the members within a circle or a box
+// GEOSEARCH key FROMMEMBER member | FROMLONLAT longitude latitude
+// BYRADIUS radius unit | BYBOX width height unit [ASC | DESC] [COUNT count [ANY]]
+// [WITHCOORD] [WITHDIST] [WITHHASH]
+func (h *Handler) GeoSearch(client *Client, args ...any) ([]byte, error) {
+	request, err := parseGeoSearch("geosearch", args[1:], false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.db(client).GeoSearch(args[0].(string), request.query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{}
+
+	for _, result := range results {
+		member := resp.ArrayType{Value: result.Member, Type: resp.BULK_STRING}
+
+		if !request.withDist && !request.withHash && !request.withCoord {
+			reply = append(reply, member)
+			continue
+		}
+
+		item := []resp.ArrayType{member}
+
+		if request.withDist {
+			item = append(item, resp.ArrayType{Value: formatDistance(result.Distance, request.unit), Type: resp.BULK_STRING})
+		}
+
+		if request.withHash {
+			item = append(item, resp.ArrayType{Value: int(result.Hash), Type: resp.INTEGER})
+		}
+
+		if request.withCoord {
+			item = append(item, coordinatesItem(result.Point))
+		}
+
+		reply = append(reply, resp.ArrayType{Value: item, Type: resp.ARRAY})
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+	return data, err
+}
+
+// GeoSearchStore stores the members within a circle or a box in destination,
+// scored by their distance with STOREDIST
+// GEOSEARCHSTORE destination source FROMMEMBER member | FROMLONLAT longitude latitude
+// BYRADIUS radius unit | BYBOX width height unit [ASC | DESC] [COUNT count [ANY]] [STOREDIST]
+func (h *Handler) GeoSearchStore(client *Client, args ...any) ([]byte, error) {
+	request, err := parseGeoSearch("geosearchstore", args[2:], true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.db(client).GeoSearch(args[1].(string), request.query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []zset.Entry{}
+
+	for _, result := range results {
+		score := float64(result.Hash)
+
+		if request.storeDist {
+			score = result.Distance / request.unit
+		}
+
+		entries = append(entries, zset.Entry{Member: result.Member, Score: score})
+	}
+
+	stored := h.db(client).ZStore(args[0].(string), entries)
+
+	data, err := client.Serialize(resp.INTEGER, stored)
+	return data, err
+}
+
+// parseGeoSearch parses the options of a search, following its key
+func parseGeoSearch(command string, args []any, store bool) (geoSearchRequest, error) {
+	request := geoSearchRequest{}
+	query := &request.query
+	from, by := 0, 0
+
+	for i := 0; i < len(args); i++ {
+		remaining := len(args) - i - 1
+
+		switch option := strings.ToUpper(args[i].(string)); {
+		case option == "FROMMEMBER" && remaining >= 1:
+			query.FromMember, query.Member = true, args[i+1].(string)
+			from++
+			i++
+
+		case option == "FROMLONLAT" && remaining >= 2:
+			point, err := parseGeoPoint(args[i+1].(string), args[i+2].(string))
+
+			if err != nil {
+				return request, err
+			}
+
+			query.Shape.Center = point
+			from++
+			i += 2
+
+		case option == "BYRADIUS" && remaining >= 2:
+			radius, err := strconv.ParseFloat(args[i+1].(string), 64)
+
+			if err != nil {
+				return request, errGeoRadius
+			}
+
+			if radius < 0 {
+				return request, errGeoNegRadius
+			}
+
+			if request.unit, err = parseGeoUnit(args[i+2].(string)); err != nil {
+				return request, err
+			}
+
+			query.Shape.Box, query.Shape.Radius = false, radius*request.unit
+			by++
+			i += 2
+
+		case option == "BYBOX" && remaining >= 3:
+			width, err := strconv.ParseFloat(args[i+1].(string), 64)
+
+			if err != nil {
+				return request, errNotFloat
+			}
+
+			height, err := strconv.ParseFloat(args[i+2].(string), 64)
+
+			if err != nil {
+				return request, errNotFloat
+			}
+
+			if width < 0 || height < 0 {
+				return request, errGeoNegBox
+			}
+
+			if request.unit, err = parseGeoUnit(args[i+3].(string)); err != nil {
+				return request, err
+			}
+
+			query.Shape.Box, query.Shape.Width, query.Shape.Height = true, width*request.unit, height*request.unit
+			by++
+			i += 3
+
+		case option == "ASC":
+			query.Sort = geo.SORT_ASC
+
+		case option == "DESC":
+			query.Sort = geo.SORT_DESC
+
+		case option == "COUNT" && remaining >= 1:
+			count, err := strconv.Atoi(args[i+1].(string))
+
+			if err != nil {
+				return request, errNotInteger
+			}
+
+			if count <= 0 {
+				return request, errGeoCount
+			}
+
+			query.Count = count
+			i++
+
+		case option == "ANY":
+			query.Any = true
+
+		case option == "WITHDIST":
+			request.withDist = true
+
+		case option == "WITHHASH":
+			request.withHash = true
+
+		case option == "WITHCOORD":
+			request.withCoord = true
+
+		case option == "STOREDIST" && store:
+			request.storeDist = true
+
+		default:
+			return request, errSyntax
+		}
+	}
+
+	if from != 1 {
+		return request, fmt.Errorf("ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for %s", command)
+	}
+
+	if by != 1 {
+		return request, fmt.Errorf("ERR exactly one of BYRADIUS and BYBOX can be specified for %s", command)
+	}
+
+	if query.Any && query.Count == 0 {
+		return request, errGeoAnyNoCount
+	}
+
+	if store && (request.withDist || request.withHash || request.withCoord) {
+		return request, errors.New("ERR GEOSEARCHSTORE is not compatible with WITHDIST, WITHHASH and WITHCOORD options")
+	}
+
+	// the closest members are the ones kept by COUNT, unless any will do
+	if query.Count > 0 && !query.Any && query.Sort == geo.SORT_NONE {
+		query.Sort = geo.SORT_ASC
+	}
+
+	return request, nil
+}
+
+func parseGeoPoint(longitude string, latitude string) (geo.Point, error) {
+	lon, err := strconv.ParseFloat(longitude, 64)
+
+	if err != nil {
+		return geo.Point{}, errNotFloat
+	}
+
+	lat, err := strconv.ParseFloat(latitude, 64)
+
+	if err != nil {
+		return geo.Point{}, errNotFloat
+	}
+
+	point := geo.Point{Longitude: lon, Latitude: lat}
+
+	if !point.Valid() {
+		return point, geo.ErrInvalidPoint(point)
+	}
+
+	return point, nil
+}
+
+// parseGeoUnit returns the meters per unit
+func parseGeoUnit(unit string) (float64, error) {
+	meters, found := geoUnits[strings.ToUpper(unit)]
+
+	if !found {
+		return 0, errGeoUnit
+	}
+
+	return meters, nil
+}
+
+// formatDistance formats the distance in meters in unit, with the 4 decimals
+// redis replies with
+func formatDistance(distance float64, unit float64) string {
+	return strconv.FormatFloat(distance/unit, 'f', 4, 64)
+}
+
+func coordinatesItem(point geo.Point) resp.ArrayType {
+	coordinates := []resp.ArrayType{
+		{Value: point.Longitude, Type: resp.DOUBLE},
+		{Value: point.Latitude, Type: resp.DOUBLE},
+	}
+
+	return resp.ArrayType{Value: coordinates, Type: resp.ARRAY}
+}
@@ -2,222 +2,188 @@ package handler
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/pubsub"
+	"github.com/iamvineettiwari/go-redis-server-lite/rdb"
 	"github.com/iamvineettiwari/go-redis-server-lite/resp"
 )
 
+type HandlerFunc func(client *Client, args ...any) ([]byte, error)
+
 type Handler struct {
-	handlers map[string]func(args ...any) ([]byte, error)
-	store    *data.Store
+	commands map[string]*CommandSpec
+	// one store per logical database, clients pick theirs with SELECT
+	stores      []*data.Store
+	snapshotter *rdb.Snapshotter
+	pubsub      *pubsub.PubSub
 }
 
 func NewHandler() *Handler {
-	return &Handler{
-		handlers: make(map[string]func(args ...any) ([]byte, error)),
+	h := &Handler{
+		commands: make(map[string]*CommandSpec),
+		pubsub:   pubsub.NewPubSub(),
+	}
+
+	for _, command := range h.commandTable() {
+		h.AddCommand(command)
 	}
-}
 
-func (h *Handler) ResolveHandler(path string) (func(args ...any) ([]byte, error), bool) {
-	handlerFunc, found := h.handlers[path]
-	return handlerFunc, found
+	return h
 }
 
-func (h *Handler) AddHandler(path string, handlerFunc func(args ...any) ([]byte, error)) {
-	h.handlers[path] = handlerFunc
+// ResolveCommand returns the spec of the command, whose name must be upper case
+func (h *Handler) ResolveCommand(name string) (*CommandSpec, bool) {
+	command, found := h.commands[name]
+	return command, found
 }
 
-func (h *Handler) ConfigureStore(store *data.Store) {
-	h.store = store
+func (h *Handler) AddCommand(command *CommandSpec) {
+	h.commands[command.Name] = command
 }
 
-func (h *Handler) Ping(args ...any) ([]byte, error) {
-	data, err := resp.Serialize(resp.SIMPLE_STRING, "PONG")
-	return data, err
+func (h *Handler) ConfigureStores(stores []*data.Store) {
+	h.stores = stores
 }
 
-func (h *Handler) Set(args ...any) ([]byte, error) {
-	if len(args) < 2 {
-		return nil, errors.New("Invalid operation")
-	}
+// db returns the store of the database selected by the client
+func (h *Handler) db(client *Client) *data.Store {
+	return h.stores[client.DB]
+}
 
-	if len(args) > 2 && len(args) < 4 {
-		return nil, errors.New("Invalid operation")
+func (h *Handler) Ping(client *Client, args ...any) ([]byte, error) {
+	// subscribed resp2 clients get a message shaped reply
+	if client.Subscriptions() > 0 && client.Protocol == resp.RESP2 {
+		data, err := client.Serialize(resp.ARRAY, bulkStrings([]string{"pong", ""}))
+		return data, err
 	}
 
-	key := args[0].(string)
-	value := args[1].(string)
-
-	var expireCommand string
-	var expireTime int
+	data, err := client.Serialize(resp.SIMPLE_STRING, "PONG")
+	return data, err
+}
 
-	if len(args) > 2 {
-		com := strings.ToUpper(args[2].(string))
+// Set sets the string value of a key, the options may come in any order
+// SET key value [NX | XX] [GET] [EX seconds | PX milliseconds |
+// EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]
+func (h *Handler) Set(client *Client, args ...any) ([]byte, error) {
+	options, _, err := parseSetOptions("set", args[2:], false)
 
-		if com != "PX" && com != "EX" {
-			return nil, errors.New("Invalid operation")
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		val, err := strconv.Atoi(args[3].(string))
+	previous, set, err := h.db(client).SetWithOptions(args[0].(string), args[1].(string), options)
 
-		if err != nil {
-			return nil, errors.New("ERR invalid expire time")
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		expireTime = val
-		expireCommand = com
+	if options.Get {
+		return client.Serialize(resp.BULK_STRING, previous)
 	}
 
-	h.store.Set(key, value, expireCommand, expireTime)
+	if !set {
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
 
-	data, err := resp.Serialize(resp.SIMPLE_STRING, "OK")
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
 
 	return data, err
 }
 
-func (h *Handler) Get(args ...any) ([]byte, error) {
-	if len(args) != 1 {
-		return nil, errors.New("Invalid Operation")
-	}
-
+func (h *Handler) Get(client *Client, args ...any) ([]byte, error) {
 	key := args[0].(string)
 
-	value, _, err := h.store.Get(key)
+	value, _, err := h.db(client).Get(key)
 
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := resp.Serialize(resp.BULK_STRING, value)
+	data, err := client.Serialize(resp.BULK_STRING, value)
 
 	return data, err
 }
 
-func (h *Handler) Echo(args ...any) ([]byte, error) {
+func (h *Handler) Echo(client *Client, args ...any) ([]byte, error) {
 	echoString := ""
 
 	for _, item := range args {
 		echoString += (item.(string))
 	}
 
-	data, err := resp.Serialize(resp.SIMPLE_STRING, echoString)
+	data, err := client.Serialize(resp.SIMPLE_STRING, echoString)
 
 	return data, err
 }
 
-func (h *Handler) Exists(args ...any) ([]byte, error) {
-	if len(args) < 1 {
-		return nil, errors.New("Invalid Operation")
-	}
-
+func (h *Handler) Exists(client *Client, args ...any) ([]byte, error) {
 	totalFound := 0
 
 	for _, key := range args {
-		if h.store.Exists(key.(string)) {
+		if h.db(client).Exists(key.(string)) {
 			totalFound++
 		}
 	}
 
-	data, err := resp.Serialize(resp.INTEGER, totalFound)
+	data, err := client.Serialize(resp.INTEGER, totalFound)
 
 	return data, err
 }
 
-func (h *Handler) Delete(args ...any) ([]byte, error) {
-	if len(args) < 1 {
-		return nil, errors.New("Invalid operation")
-	}
-
+func (h *Handler) Delete(client *Client, args ...any) ([]byte, error) {
 	totalDeleted := 0
 
 	for _, key := range args {
-		if h.store.Delete(key.(string)) {
+		if h.db(client).Delete(key.(string)) {
 			totalDeleted++
 		}
 	}
 
-	data, err := resp.Serialize(resp.INTEGER, totalDeleted)
+	data, err := client.Serialize(resp.INTEGER, totalDeleted)
 	return data, err
 }
 
-func (h *Handler) Incr(args ...any) ([]byte, error) {
-	if len(args) != 1 {
-		return nil, errors.New("Invalid operation")
-	}
-
-	increment, err := h.store.Incr(args[0].(string))
-
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := resp.Serialize(resp.INTEGER, increment)
-
-	return data, err
+func (h *Handler) Incr(client *Client, args ...any) ([]byte, error) {
+	return h.incrBy(client, args[0].(string), 1)
 }
 
-func (h *Handler) Decr(args ...any) ([]byte, error) {
-	if len(args) != 1 {
-		return nil, errors.New("Invalid operation")
-	}
-
-	increment, err := h.store.Decr(args[0].(string))
-
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := resp.Serialize(resp.INTEGER, increment)
-
-	return data, err
+func (h *Handler) Decr(client *Client, args ...any) ([]byte, error) {
+	return h.incrBy(client, args[0].(string), -1)
 }
 
-func (h *Handler) Lpush(args ...any) ([]byte, error) {
-	if len(args) < 2 {
-		return nil, errors.New("Invalid operation")
-	}
-
+func (h *Handler) Lpush(client *Client, args ...any) ([]byte, error) {
 	key := args[0].(string)
 	val := args[1:]
 
-	items, err := h.store.Lpush(key, val...)
+	length, err := h.db(client).Lpush(key, val...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := resp.Serialize(resp.ARRAY, items)
+	data, err := client.Serialize(resp.INTEGER, length)
 
 	return data, err
 }
 
-func (h *Handler) Rpush(args ...any) ([]byte, error) {
-	if len(args) < 2 {
-		return nil, errors.New("Invalid operation")
-	}
-
+func (h *Handler) Rpush(client *Client, args ...any) ([]byte, error) {
 	key := args[0].(string)
 	val := args[1:]
 
-	items, err := h.store.Rpush(key, val...)
+	length, err := h.db(client).Rpush(key, val...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := resp.Serialize(resp.ARRAY, items)
+	data, err := client.Serialize(resp.INTEGER, length)
 
 	return data, err
 }
 
-func (h *Handler) LRange(args ...any) ([]byte, error) {
-	if len(args) < 3 {
-		return nil, errors.New(fmt.Sprintf("wrong number of arguments (given %d, expected 3)", len(args)))
-	}
-
+func (h *Handler) LRange(client *Client, args ...any) ([]byte, error) {
 	key := args[0].(string)
 	start, err := strconv.Atoi(args[1].(string))
 
@@ -231,13 +197,13 @@ func (h *Handler) LRange(args ...any) ([]byte, error) {
 		return nil, errors.New("ERR value is not an integer or out of range")
 	}
 
-	items, err := h.store.LRange(key, start, end)
+	items, err := h.db(client).LRange(key, start, end)
 
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := resp.Serialize(resp.ARRAY, items)
+	data, err := client.Serialize(resp.ARRAY, items)
 
 	return data, err
 }
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// newTestHandler returns a handler over a single database along with a client
+func newTestHandler() (*Handler, *Client) {
+	h := NewHandler()
+	h.ConfigureStores([]*data.Store{data.NewStore()})
+
+	return h, NewClient(1)
+}
+
+// call runs the command like the server does and returns its decoded reply,
+// arrays as []any and maps as map[any]any
+func call(h *Handler, client *Client, command string, args ...any) (any, error) {
+	spec, found := h.ResolveCommand(strings.ToUpper(command))
+
+	if !found {
+		return nil, ErrUnknownCommand(command, args)
+	}
+
+	if err := spec.CheckArity(args); err != nil {
+		return nil, err
+	}
+
+	reply, err := spec.Handler(client, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, _, err, _ := resp.Deserialize(reply)
+	return plain(value), err
+}
+
+func plain(value any) any {
+	switch value := value.(type) {
+	case []resp.ArrayType:
+		items := []any{}
+
+		for _, item := range value {
+			items = append(items, plain(item.Value))
+		}
+
+		return items
+	case []resp.MapType:
+		entries := map[any]any{}
+
+		for _, entry := range value {
+			entries[plain(entry.Key.Value)] = plain(entry.Value.Value)
+		}
+
+		return entries
+	}
+
+	return value
+}
@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+func (h *Handler) HSet(client *Client, args ...any) ([]byte, error) {
+	if len(args)%2 != 1 {
+		return nil, errors.New("ERR wrong number of arguments for 'hset' command")
+	}
+
+	added, err := h.db(client).HSet(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, added)
+
+	return data, err
+}
+
+// HMSet is the deprecated form of HSET replying with OK
+func (h *Handler) HMSet(client *Client, args ...any) ([]byte, error) {
+	if len(args)%2 != 1 {
+		return nil, errors.New("ERR wrong number of arguments for 'hmset' command")
+	}
+
+	if _, err := h.db(client).HSet(args[0].(string), stringArgs(args[1:])...); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+
+	return data, err
+}
+
+func (h *Handler) HSetNX(client *Client, args ...any) ([]byte, error) {
+	added, err := h.db(client).HSetNX(args[0].(string), args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(added))
+
+	return data, err
+}
+
+func (h *Handler) HGet(client *Client, args ...any) ([]byte, error) {
+	value, err := h.db(client).HGet(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+
+	return data, err
+}
+
+func (h *Handler) HMGet(client *Client, args ...any) ([]byte, error) {
+	values, err := h.db(client).HMGet(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.ARRAY, bulkValues(values))
+
+	return data, err
+}
+
+func (h *Handler) HGetAll(client *Client, args ...any) ([]byte, error) {
+	entries, err := h.db(client).HGetAll(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.MapType{}
+
+	for field, value := range entries {
+		reply = append(reply, mapEntry(field, resp.BULK_STRING, value))
+	}
+
+	data, err := client.Serialize(resp.MAP, reply)
+
+	return data, err
+}
+
+func (h *Handler) HDel(client *Client, args ...any) ([]byte, error) {
+	removed, err := h.db(client).HDel(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, removed)
+
+	return data, err
+}
+
+func (h *Handler) HExists(client *Client, args ...any) ([]byte, error) {
+	exists, err := h.db(client).HExists(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(exists))
+
+	return data, err
+}
+
+func (h *Handler) HLen(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).HLen(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+func (h *Handler) HStrLen(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).HStrLen(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+func (h *Handler) HKeys(client *Client, args ...any) ([]byte, error) {
+	entries, err := h.db(client).HGetAll(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+
+	for field := range entries {
+		fields = append(fields, field)
+	}
+
+	data, err := client.Serialize(resp.ARRAY, bulkStrings(fields))
+
+	return data, err
+}
+
+func (h *Handler) HVals(client *Client, args ...any) ([]byte, error) {
+	entries, err := h.db(client).HGetAll(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+
+	for _, value := range entries {
+		values = append(values, value)
+	}
+
+	data, err := client.Serialize(resp.ARRAY, bulkStrings(values))
+
+	return data, err
+}
+
+func (h *Handler) HIncrBy(client *Client, args ...any) ([]byte, error) {
+	increment, err := strconv.ParseInt(args[2].(string), 10, 64)
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	value, err := h.db(client).HIncrBy(args[0].(string), args[1].(string), increment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(value))
+
+	return data, err
+}
+
+func (h *Handler) HIncrByFloat(client *Client, args ...any) ([]byte, error) {
+	value, err := h.db(client).HIncrByFloat(args[0].(string), args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+
+	return data, err
+}
+
+// HRandField replies with a single random field, or with count of them
+// HRANDFIELD key [count [WITHVALUES]]
+func (h *Handler) HRandField(client *Client, args ...any) ([]byte, error) {
+	if len(args) > 3 {
+		return nil, errors.New("ERR wrong number of arguments for 'hrandfield' command")
+	}
+
+	key := args[0].(string)
+
+	if len(args) == 1 {
+		entries, err := h.db(client).HRandField(key, 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var field interface{}
+
+		if len(entries) > 0 {
+			field = entries[0][0]
+		}
+
+		data, err := client.Serialize(resp.BULK_STRING, field)
+
+		return data, err
+	}
+
+	count, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	withValues := false
+
+	if len(args) == 3 {
+		if strings.ToUpper(args[2].(string)) != "WITHVALUES" {
+			return nil, errors.New("ERR syntax error")
+		}
+
+		withValues = true
+	}
+
+	entries, err := h.db(client).HRandField(key, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{}
+
+	for _, entry := range entries {
+		switch {
+		case !withValues:
+			reply = append(reply, resp.ArrayType{Value: entry[0], Type: resp.BULK_STRING})
+		case client.Protocol == resp.RESP3:
+			// resp3 clients get field value pairs
+			reply = append(reply, resp.ArrayType{Value: bulkStrings(entry[:]), Type: resp.ARRAY})
+		default:
+			reply = append(reply, bulkStrings(entry[:])...)
+		}
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
+
+// HScan iterates the fields of a hash
+// HSCAN key cursor [MATCH pattern] [COUNT count] [NOVALUES]
+func (h *Handler) HScan(client *Client, args ...any) ([]byte, error) {
+	options, err := parseScanOptions(args[1:], SCAN_NOVALUES)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, cursor, err := h.db(client).HScan(args[0].(string), options.cursor, options.pattern, options.count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []string{}
+
+	for _, entry := range entries {
+		items = append(items, entry[0])
+
+		if !options.noValues {
+			items = append(items, entry[1])
+		}
+	}
+
+	return scanReply(client, cursor, items)
+}
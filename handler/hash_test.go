@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHRandFieldCounts(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "HSET", "hash", "a", "1", "b", "2")
+
+	reply, _ := call(h, client, "HRANDFIELD", "hash")
+	assert.Contains(t, []any{"a", "b"}, reply)
+
+	reply, _ = call(h, client, "HRANDFIELD", "hash", "5")
+	assert.ElementsMatch(t, []any{"a", "b"}, reply)
+
+	reply, _ = call(h, client, "HRANDFIELD", "hash", "-3")
+	assert.Len(t, reply, 3)
+
+	// field value pairs are flattened for resp2 clients
+	reply, _ = call(h, client, "HRANDFIELD", "hash", "-3", "WITHVALUES")
+	items := reply.([]any)
+	assert.Len(t, items, 6)
+
+	for i := 0; i < len(items); i += 2 {
+		assert.Equal(t, map[any]any{"a": "1", "b": "2"}[items[i]], items[i+1])
+	}
+
+	reply, _ = call(h, client, "HRANDFIELD", "missing")
+	assert.Nil(t, reply)
+
+	_, err := call(h, client, "HRANDFIELD", "hash", "x")
+	assert.EqualError(t, err, "ERR value is not an integer or out of range")
+
+	_, err = call(h, client, "HRANDFIELD", "hash", "1", "WITHSCORES")
+	assert.EqualError(t, err, "ERR syntax error")
+
+	_, err = call(h, client, "HRANDFIELD", "hash", "1", "WITHVALUES", "x")
+	assert.EqualError(t, err, "ERR wrong number of arguments for 'hrandfield' command")
+}
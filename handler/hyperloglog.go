@@ -0,0 +1,41 @@
+package handler
+
+import "github.com/iamvineettiwari/go-redis-server-lite/resp"
+
+// PFAdd adds the elements to the HyperLogLog, replies 1 when its estimate changed
+// PFADD key [element [element ...]]
+func (h *Handler) PFAdd(client *Client, args ...any) ([]byte, error) {
+	changed, err := h.db(client).PFAdd(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(changed))
+	return data, err
+}
+
+// PFCount replies with the estimated number of distinct elements added to the
+// HyperLogLogs
+// PFCOUNT key [key ...]
+func (h *Handler) PFCount(client *Client, args ...any) ([]byte, error) {
+	count, err := h.db(client).PFCount(stringArgs(args)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(count))
+	return data, err
+}
+
+// PFMerge stores the union of the HyperLogLogs at destination
+// PFMERGE destkey [sourcekey [sourcekey ...]]
+func (h *Handler) PFMerge(client *Client, args ...any) ([]byte, error) {
+	if err := h.db(client).PFMerge(args[0].(string), stringArgs(args[1:])...); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Keys replies with every key matching pattern, SCAN is preferred on large
+// databases as this walks the whole keyspace at once
+// KEYS pattern
+func (h *Handler) Keys(client *Client, args ...any) ([]byte, error) {
+	keys := h.db(client).Keys(args[0].(string))
+
+	data, err := client.Serialize(resp.ARRAY, bulkStrings(keys))
+	return data, err
+}
+
+// Scan iterates the keys of the selected database
+// SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+func (h *Handler) Scan(client *Client, args ...any) ([]byte, error) {
+	options, err := parseScanOptions(args, SCAN_TYPE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, cursor := h.db(client).Scan(options.cursor, options.pattern, options.count, options.keyType)
+
+	return scanReply(client, cursor, keys)
+}
+
+// RandomKey replies with a random key, or nil when the database is empty
+// RANDOMKEY
+func (h *Handler) RandomKey(client *Client, args ...any) ([]byte, error) {
+	key, found := h.db(client).RandomKey()
+
+	if !found {
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, key)
+	return data, err
+}
+
+// Type replies with the type of the value of the key, none when it does not exist
+// TYPE key
+func (h *Handler) Type(client *Client, args ...any) ([]byte, error) {
+	data, err := client.Serialize(resp.SIMPLE_STRING, h.db(client).Type(args[0].(string)))
+	return data, err
+}
+
+// Rename renames a key, replacing the destination and keeping the timeout
+// RENAME key newkey
+func (h *Handler) Rename(client *Client, args ...any) ([]byte, error) {
+	if _, err := h.db(client).Rename(args[0].(string), args[1].(string), true); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// RenameNX renames a key unless the destination exists, replies 1 when renamed
+// RENAMENX key newkey
+func (h *Handler) RenameNX(client *Client, args ...any) ([]byte, error) {
+	renamed, err := h.db(client).Rename(args[0].(string), args[1].(string), false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(renamed))
+	return data, err
+}
+
+// Copy copies a key, to another database when DB is given, replies 1 when copied
+// COPY source destination [DB destination-db] [REPLACE]
+func (h *Handler) Copy(client *Client, args ...any) ([]byte, error) {
+	source, destination := args[0].(string), args[1].(string)
+	db := client.DB
+	replace := false
+
+	for i := 2; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+
+		switch {
+		case option == "REPLACE":
+			replace = true
+		case option == "DB" && i+1 < len(args):
+			var err error
+
+			if db, err = h.parseDB(args[i+1], errNotInteger); err != nil {
+				return nil, err
+			}
+
+			i++
+		default:
+			return nil, errSyntax
+		}
+	}
+
+	if db == client.DB && source == destination {
+		return nil, errors.New("ERR source and destination objects are the same")
+	}
+
+	copied := h.db(client).Copy(source, h.stores[db], destination, replace)
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(copied))
+	return data, err
+}
+
+// Unlink removes the keys like DEL does. Removed values are always freed by
+// the garbage collector in the background, nothing else is left to do.
+// UNLINK key [key ...]
+func (h *Handler) Unlink(client *Client, args ...any) ([]byte, error) {
+	return h.Delete(client, args...)
+}
+
+// Touch records an access to the keys, replies with the number of existing ones
+// TOUCH key [key ...]
+func (h *Handler) Touch(client *Client, args ...any) ([]byte, error) {
+	touched := 0
+
+	for _, key := range args {
+		if h.db(client).Exists(key.(string)) {
+			touched++
+		}
+	}
+
+	data, err := client.Serialize(resp.INTEGER, touched)
+	return data, err
+}
+
+// Object inspects the value of a key without counting as an access
+// OBJECT ENCODING | IDLETIME | FREQ | REFCOUNT key
+// OBJECT HELP
+func (h *Handler) Object(client *Client, args ...any) ([]byte, error) {
+	subcommand := strings.ToUpper(args[0].(string))
+
+	if subcommand == "HELP" && len(args) == 1 {
+		return helpReply(client, OBJECT,
+			"ENCODING <key>",
+			"    Return the kind of internal representation used in order to store the value",
+			"    associated with a <key>.",
+			"FREQ <key>",
+			"    Return the access frequency index of the <key>. The returned integer is",
+			"    proportional to the logarithm of the recent access frequency of the key.",
+			"IDLETIME <key>",
+			"    Return the idle time of the <key>, that is the approximated number of",
+			"    seconds elapsed since the last access to the key.",
+			"REFCOUNT <key>",
+			"    Return the number of references of the value associated with the specified",
+			"    <key>.",
+		)
+	}
+
+	if len(args) != 2 || !slices.Contains([]string{"ENCODING", "IDLETIME", "FREQ", "REFCOUNT"}, subcommand) {
+		return nil, fmt.Errorf("ERR unknown subcommand or wrong number of arguments for '%s'. Try OBJECT HELP.", args[0].(string))
+	}
+
+	store := h.db(client)
+	key := args[1].(string)
+	nilReply := func() ([]byte, error) { return client.Serialize(resp.BULK_STRING, nil) }
+
+	switch subcommand {
+	case "ENCODING":
+		encoding, found := store.ObjectEncoding(key)
+
+		if !found {
+			return nilReply()
+		}
+
+		return client.Serialize(resp.BULK_STRING, encoding)
+
+	case "IDLETIME":
+		idleTime, found := store.ObjectIdleTime(key)
+
+		if !found {
+			return nilReply()
+		}
+
+		return client.Serialize(resp.INTEGER, int(idleTime))
+
+	case "FREQ":
+		frequency, found := store.ObjectFreq(key)
+
+		if !found {
+			return nilReply()
+		}
+
+		return client.Serialize(resp.INTEGER, frequency)
+	}
+
+	refCount, found := store.ObjectRefCount(key)
+
+	if !found {
+		return nilReply()
+	}
+
+	return client.Serialize(resp.INTEGER, refCount)
+}
@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// list ends of LMOVE
+const (
+	LIST_LEFT  string = "LEFT"
+	LIST_RIGHT string = "RIGHT"
+)
+
+// Lpushx inserts at the head of the list, only when the list exists
+func (h *Handler) Lpushx(client *Client, args ...any) ([]byte, error) {
+	return h.pushx(client, h.db(client).Lpushx, args...)
+}
+
+// Rpushx inserts at the tail of the list, only when the list exists
+func (h *Handler) Rpushx(client *Client, args ...any) ([]byte, error) {
+	return h.pushx(client, h.db(client).Rpushx, args...)
+}
+
+func (h *Handler) pushx(client *Client, push func(key string, val ...interface{}) (int, error), args ...any) ([]byte, error) {
+	length, err := push(args[0].(string), args[1:]...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+// LPop removes elements from the head of the list
+// LPOP key [count]
+func (h *Handler) LPop(client *Client, args ...any) ([]byte, error) {
+	return h.listPop(client, "lpop", h.db(client).LPop, args...)
+}
+
+// RPop removes elements from the tail of the list
+// RPOP key [count]
+func (h *Handler) RPop(client *Client, args ...any) ([]byte, error) {
+	return h.listPop(client, "rpop", h.db(client).RPop, args...)
+}
+
+func (h *Handler) listPop(client *Client, command string, pop func(key string, count int) ([]resp.ArrayType, error), args ...any) ([]byte, error) {
+	if len(args) > 2 {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	count := 1
+
+	if len(args) == 2 {
+		var err error
+		count, err = strconv.Atoi(args[1].(string))
+
+		if err != nil || count < 0 {
+			return nil, errors.New("ERR value is out of range, must be positive")
+		}
+	}
+
+	items, err := pop(args[0].(string), count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// with a count the reply is an array, a null one when the key does not exist
+	if len(args) == 2 {
+		if items == nil {
+			return client.Serialize(resp.ARRAY, nil)
+		}
+
+		return client.Serialize(resp.ARRAY, items)
+	}
+
+	var item interface{}
+
+	if len(items) > 0 {
+		item = items[0].Value
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, item)
+
+	return data, err
+}
+
+func (h *Handler) LLen(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).LLen(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+func (h *Handler) LIndex(client *Client, args ...any) ([]byte, error) {
+	index, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	value, err := h.db(client).LIndex(args[0].(string), index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+
+	return data, err
+}
+
+func (h *Handler) LSet(client *Client, args ...any) ([]byte, error) {
+	index, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	if err := h.db(client).LSet(args[0].(string), index, args[2].(string)); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+
+	return data, err
+}
+
+// LInsert inserts an element next to the pivot
+// LINSERT key BEFORE | AFTER pivot element
+func (h *Handler) LInsert(client *Client, args ...any) ([]byte, error) {
+	var before bool
+
+	switch strings.ToUpper(args[1].(string)) {
+	case "BEFORE":
+		before = true
+	case "AFTER":
+		before = false
+	default:
+		return nil, errors.New("ERR syntax error")
+	}
+
+	length, err := h.db(client).LInsert(args[0].(string), before, args[2].(string), args[3].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+// LRem removes occurrences of an element
+// LREM key count element
+func (h *Handler) LRem(client *Client, args ...any) ([]byte, error) {
+	count, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	removed, err := h.db(client).LRem(args[0].(string), count, args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, removed)
+
+	return data, err
+}
+
+// LTrim keeps only the elements within the range
+// LTRIM key start stop
+func (h *Handler) LTrim(client *Client, args ...any) ([]byte, error) {
+	start, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	stop, err := strconv.Atoi(args[2].(string))
+
+	if err != nil {
+		return nil, errors.New("ERR value is not an integer or out of range")
+	}
+
+	if err := h.db(client).LTrim(args[0].(string), start, stop); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+
+	return data, err
+}
+
+// LPos replies with the index of matching elements
+// LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len]
+func (h *Handler) LPos(client *Client, args ...any) ([]byte, error) {
+	rank, count, maxLen := 1, 0, 0
+	withCount := false
+	options := args[2:]
+
+	for i := 0; i < len(options); i++ {
+		option := strings.ToUpper(options[i].(string))
+
+		if i+1 >= len(options) {
+			return nil, errors.New("ERR syntax error")
+		}
+
+		value, err := strconv.Atoi(options[i+1].(string))
+
+		if err != nil {
+			return nil, errors.New("ERR value is not an integer or out of range")
+		}
+
+		switch option {
+		case "RANK":
+			if value == 0 {
+				return nil, errors.New("ERR RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list")
+			}
+
+			rank = value
+
+		case "COUNT":
+			if value < 0 {
+				return nil, errors.New("ERR COUNT can't be negative")
+			}
+
+			count, withCount = value, true
+
+		case "MAXLEN":
+			if value < 0 {
+				return nil, errors.New("ERR MAXLEN can't be negative")
+			}
+
+			maxLen = value
+
+		default:
+			return nil, errors.New("ERR syntax error")
+		}
+
+		i++
+	}
+
+	// without COUNT only the first match is needed
+	limit := count
+
+	if !withCount {
+		limit = 1
+	}
+
+	positions, err := h.db(client).LPos(args[0].(string), args[1].(string), rank, limit, maxLen)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if withCount {
+		reply := []resp.ArrayType{}
+
+		for _, position := range positions {
+			reply = append(reply, resp.ArrayType{Value: position, Type: resp.INTEGER})
+		}
+
+		return client.Serialize(resp.ARRAY, reply)
+	}
+
+	if len(positions) == 0 {
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
+
+	data, err := client.Serialize(resp.INTEGER, positions[0])
+
+	return data, err
+}
+
+// LMove moves an element from one list to another
+// LMOVE source destination LEFT | RIGHT LEFT | RIGHT
+func (h *Handler) LMove(client *Client, args ...any) ([]byte, error) {
+	fromHead, err := parseListEnd(args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	toHead, err := parseListEnd(args[3].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return h.move(client, args[0].(string), args[1].(string), fromHead, toHead)
+}
+
+// RPopLPush is the deprecated form of LMOVE source destination RIGHT LEFT
+func (h *Handler) RPopLPush(client *Client, args ...any) ([]byte, error) {
+	return h.move(client, args[0].(string), args[1].(string), false, true)
+}
+
+func (h *Handler) move(client *Client, source string, destination string, fromHead bool, toHead bool) ([]byte, error) {
+	value, err := h.db(client).LMove(source, destination, fromHead, toHead)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+
+	return data, err
+}
+
+// parseListEnd returns whether the end is the head of the list
+func parseListEnd(end string) (bool, error) {
+	switch strings.ToUpper(end) {
+	case LIST_LEFT:
+		return true, nil
+	case LIST_RIGHT:
+		return false, nil
+	}
+
+	return false, errors.New("ERR syntax error")
+}
+
+// LMPop pops elements from the first non empty list
+// LMPOP numkeys key [key ...] LEFT | RIGHT [COUNT count]
+func (h *Handler) LMPop(client *Client, args ...any) ([]byte, error) {
+	keys, head, count, err := parseMPopArgs(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := h.mpop(client, keys, head, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return client.Serialize(resp.ARRAY, nil)
+	}
+
+	return reply, nil
+}
+
+// mpop replies with the key and the elements popped from the first non empty
+// list, the reply is nil when every list is empty
+func (h *Handler) mpop(client *Client, keys []string, head bool, count int) ([]byte, error) {
+	key, items, err := h.popFirstNonEmpty(client, keys, head, count)
+
+	if err != nil || items == nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{
+		{Value: key, Type: resp.BULK_STRING},
+		{Value: items, Type: resp.ARRAY},
+	}
+
+	return client.Serialize(resp.ARRAY, reply)
+}
+
+// popFirstNonEmpty pops up to count elements from the first list which exists,
+// the pop is logged as a plain LPOP or RPOP
+func (h *Handler) popFirstNonEmpty(client *Client, keys []string, head bool, count int) (string, []resp.ArrayType, error) {
+	pop, command := h.db(client).RPop, RPOP
+
+	if head {
+		pop, command = h.db(client).LPop, LPOP
+	}
+
+	for _, key := range keys {
+		items, err := pop(key, count)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(items) > 0 {
+			client.Propagate(command, key, strconv.Itoa(len(items)))
+			return key, items, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// parseMPopArgs parses "numkeys key [key ...] LEFT | RIGHT [COUNT count]"
+func parseMPopArgs(args []any) ([]string, bool, int, error) {
+	numKeys, err := strconv.Atoi(args[0].(string))
+
+	if err != nil || numKeys <= 0 {
+		return nil, false, 0, errors.New("ERR numkeys should be greater than 0")
+	}
+
+	// written so that a huge numkeys can not overflow
+	if numKeys > len(args)-2 {
+		return nil, false, 0, errors.New("ERR syntax error")
+	}
+
+	keys := stringArgs(args[1 : numKeys+1])
+
+	head, err := parseListEnd(args[numKeys+1].(string))
+
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	count := 1
+	options := args[numKeys+2:]
+
+	switch {
+	case len(options) == 0:
+	case len(options) == 2 && strings.ToUpper(options[0].(string)) == "COUNT":
+		count, err = strconv.Atoi(options[1].(string))
+
+		if err != nil || count <= 0 {
+			return nil, false, 0, errors.New("ERR count should be greater than 0")
+		}
+	default:
+		return nil, false, 0, errors.New("ERR syntax error")
+	}
+
+	return keys, head, count, nil
+}
+
+func listEnd(head bool) string {
+	if head {
+		return LIST_LEFT
+	}
+
+	return LIST_RIGHT
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMPopArgs(t *testing.T) {
+	tests := []struct {
+		args  []any
+		keys  []string
+		head  bool
+		count int
+		err   string
+	}{
+		{args: []any{"1", "a", "LEFT"}, keys: []string{"a"}, head: true, count: 1},
+		{args: []any{"2", "a", "b", "right", "COUNT", "3"}, keys: []string{"a", "b"}, count: 3},
+		{args: []any{"0", "a", "LEFT"}, err: "ERR numkeys should be greater than 0"},
+		{args: []any{"x", "a", "LEFT"}, err: "ERR numkeys should be greater than 0"},
+		{args: []any{"2", "a", "LEFT"}, err: "ERR syntax error"},
+		{args: []any{"9223372036854775807", "a", "LEFT"}, err: "ERR syntax error"},
+		{args: []any{"1", "a", "UP"}, err: "ERR syntax error"},
+		{args: []any{"1", "a", "LEFT", "COUNT", "0"}, err: "ERR count should be greater than 0"},
+		{args: []any{"1", "a", "LEFT", "COUNT"}, err: "ERR syntax error"},
+	}
+
+	for _, test := range tests {
+		keys, head, count, err := parseMPopArgs(test.args)
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.args)
+			continue
+		}
+
+		assert.NoError(t, err, test.args)
+		assert.Equal(t, test.keys, keys)
+		assert.Equal(t, test.head, head)
+		assert.Equal(t, test.count, count)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/rdb"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+func (h *Handler) ConfigureSnapshotter(snapshotter *rdb.Snapshotter) {
+	h.snapshotter = snapshotter
+}
+
+func (h *Handler) Save(client *Client, args ...any) ([]byte, error) {
+	if h.snapshotter == nil {
+		return nil, errors.New("ERR snapshots are not configured")
+	}
+
+	if err := h.snapshotter.Save(); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+
+	return data, err
+}
+
+func (h *Handler) BgSave(client *Client, args ...any) ([]byte, error) {
+	if len(args) > 1 {
+		return nil, errors.New("ERR wrong number of arguments for 'bgsave' command")
+	}
+
+	if h.snapshotter == nil {
+		return nil, errors.New("ERR snapshots are not configured")
+	}
+
+	if err := h.snapshotter.BackgroundSave(); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "Background saving started")
+
+	return data, err
+}
+
+func (h *Handler) LastSave(client *Client, args ...any) ([]byte, error) {
+	if h.snapshotter == nil {
+		return nil, errors.New("ERR snapshots are not configured")
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(h.snapshotter.LastSave()))
+
+	return data, err
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/pubsub"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Subscribe subscribes the client to channels, a confirmation is pushed for each of them
+// SUBSCRIBE channel [channel ...]
+func (h *Handler) Subscribe(client *Client, args ...any) ([]byte, error) {
+	replies := []byte{}
+
+	for _, channel := range stringArgs(args) {
+		client.subscriptions = h.pubsub.Subscribe(client, channel)
+
+		reply, err := subscriptionReply(client, "subscribe", channel)
+
+		if err != nil {
+			return nil, err
+		}
+
+		replies = append(replies, reply...)
+	}
+
+	return replies, nil
+}
+
+// PSubscribe subscribes the client to the channels matching the glob patterns
+// PSUBSCRIBE pattern [pattern ...]
+func (h *Handler) PSubscribe(client *Client, args ...any) ([]byte, error) {
+	replies := []byte{}
+
+	for _, pattern := range stringArgs(args) {
+		client.subscriptions = h.pubsub.PSubscribe(client, pattern)
+
+		reply, err := subscriptionReply(client, "psubscribe", pattern)
+
+		if err != nil {
+			return nil, err
+		}
+
+		replies = append(replies, reply...)
+	}
+
+	return replies, nil
+}
+
+// Unsubscribe unsubscribes the client from channels, from all of them when none is given
+// UNSUBSCRIBE [channel [channel ...]]
+func (h *Handler) Unsubscribe(client *Client, args ...any) ([]byte, error) {
+	channels := stringArgs(args)
+
+	if len(channels) == 0 {
+		channels = h.pubsub.SubscribedChannels(client)
+	}
+
+	return h.unsubscribe(client, "unsubscribe", channels, h.pubsub.Unsubscribe)
+}
+
+// PUnsubscribe unsubscribes the client from patterns, from all of them when none is given
+// PUNSUBSCRIBE [pattern [pattern ...]]
+func (h *Handler) PUnsubscribe(client *Client, args ...any) ([]byte, error) {
+	patterns := stringArgs(args)
+
+	if len(patterns) == 0 {
+		patterns = h.pubsub.SubscribedPatterns(client)
+	}
+
+	return h.unsubscribe(client, "punsubscribe", patterns, h.pubsub.PUnsubscribe)
+}
+
+func (h *Handler) unsubscribe(client *Client, kind string, names []string, unsubscribe func(subscriber pubsub.Subscriber, name string) int) ([]byte, error) {
+	// without any subscription there is still a confirmation, with a null name
+	if len(names) == 0 {
+		return subscriptionReply(client, kind, nil)
+	}
+
+	replies := []byte{}
+
+	for _, name := range names {
+		client.subscriptions = unsubscribe(client, name)
+
+		reply, err := subscriptionReply(client, kind, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		replies = append(replies, reply...)
+	}
+
+	return replies, nil
+}
+
+// unsubscribeAll drops every subscription of a client
+func (h *Handler) unsubscribeAll(client *Client) {
+	h.pubsub.UnsubscribeAll(client)
+	client.subscriptions = 0
+}
+
+// Publish replies with the number of clients which received the message
+// PUBLISH channel message
+func (h *Handler) Publish(client *Client, args ...any) ([]byte, error) {
+	receivers := h.pubsub.Publish(args[0].(string), args[1].(string))
+
+	data, err := client.Serialize(resp.INTEGER, receivers)
+
+	return data, err
+}
+
+// PubSub introspects the subscriptions
+// PUBSUB CHANNELS [pattern] | NUMSUB [channel [channel ...]] | NUMPAT | HELP
+func (h *Handler) PubSub(client *Client, args ...any) ([]byte, error) {
+	subcommand := strings.ToUpper(args[0].(string))
+
+	switch {
+	case subcommand == "CHANNELS" && len(args) <= 2:
+		pattern := ""
+
+		if len(args) == 2 {
+			pattern = args[1].(string)
+		}
+
+		return client.Serialize(resp.ARRAY, bulkStrings(h.pubsub.Channels(pattern)))
+
+	case subcommand == "NUMSUB":
+		reply := []resp.MapType{}
+
+		for _, channel := range stringArgs(args[1:]) {
+			reply = append(reply, mapEntry(channel, resp.INTEGER, h.pubsub.NumSub(channel)))
+		}
+
+		return client.Serialize(resp.MAP, reply)
+
+	case subcommand == "NUMPAT" && len(args) == 1:
+		return client.Serialize(resp.INTEGER, h.pubsub.NumPat())
+
+	case subcommand == "HELP" && len(args) == 1:
+		return helpReply(client, PUBSUB,
+			"CHANNELS [<pattern>]",
+			"    Return the currently active channels matching a <pattern> (default: '*').",
+			"NUMPAT",
+			"    Return number of subscriptions to patterns.",
+			"NUMSUB [<channel> ...]",
+			"    Return the number of subscribers for the specified channels, excluding",
+			"    pattern subscriptions(default: no channels).",
+		)
+	}
+
+	return nil, fmt.Errorf("ERR unknown subcommand or wrong number of arguments for '%s'. Try PUBSUB HELP.", args[0].(string))
+}
+
+// subscriptionReply builds the confirmation of a subscription change, along
+// with the number of subscriptions the client is left with
+func subscriptionReply(client *Client, kind string, name any) ([]byte, error) {
+	reply := []resp.ArrayType{
+		{Value: kind, Type: resp.BULK_STRING},
+		{Value: name, Type: resp.BULK_STRING},
+		{Value: client.subscriptions, Type: resp.INTEGER},
+	}
+
+	return client.Serialize(resp.PUSH, reply)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// mapEntry builds a map entry with a bulk string key
+func mapEntry(key string, valueType string, value any) resp.MapType {
+	return resp.MapType{
+		Key:   resp.ArrayType{Value: key, Type: resp.BULK_STRING},
+		Value: resp.ArrayType{Value: value, Type: valueType},
+	}
+}
+
+// bulkStrings builds an array of bulk strings
+func bulkStrings(values []string) []resp.ArrayType {
+	items := make([]resp.ArrayType, 0, len(values))
+
+	for _, value := range values {
+		items = append(items, resp.ArrayType{Value: value, Type: resp.BULK_STRING})
+	}
+
+	return items
+}
+
+// bulkValues builds an array of bulk strings, nil values become null replies
+func bulkValues(values []interface{}) []resp.ArrayType {
+	items := make([]resp.ArrayType, 0, len(values))
+
+	for _, value := range values {
+		items = append(items, resp.ArrayType{Value: value, Type: resp.BULK_STRING})
+	}
+
+	return items
+}
+
+// helpReply builds the reply of the HELP subcommand of the command, the lines
+// describing its subcommands framed like redis does
+func helpReply(client *Client, command string, lines ...string) ([]byte, error) {
+	items := []resp.ArrayType{
+		{Value: fmt.Sprintf("%s <subcommand> [<arg> [value] [opt] ...]. Subcommands are:", command), Type: resp.SIMPLE_STRING},
+	}
+
+	for _, line := range append(lines, "HELP", "    Print this help.") {
+		items = append(items, resp.ArrayType{Value: line, Type: resp.SIMPLE_STRING})
+	}
+
+	return client.Serialize(resp.ARRAY, items)
+}
+
+// stringArgs converts command arguments to strings
+func stringArgs(args []any) []string {
+	values := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		values = append(values, arg.(string))
+	}
+
+	return values
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
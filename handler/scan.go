@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// scan options
+const (
+	SCAN_MATCH    string = "MATCH"
+	SCAN_COUNT    string = "COUNT"
+	SCAN_TYPE     string = "TYPE"
+	SCAN_NOVALUES string = "NOVALUES"
+)
+
+const defaultScanCount int = 10
+
+type scanOptions struct {
+	cursor   uint64
+	pattern  string
+	count    int
+	keyType  string
+	noValues bool
+}
+
+// parseScanOptions parses "cursor [MATCH pattern] [COUNT count]" followed by
+// any of the extra options the command supports
+func parseScanOptions(args []any, extraOptions ...string) (scanOptions, error) {
+	options := scanOptions{count: defaultScanCount}
+
+	if len(args) < 1 {
+		return options, errors.New("ERR syntax error")
+	}
+
+	cursor, err := strconv.ParseUint(args[0].(string), 10, 64)
+
+	if err != nil {
+		return options, errors.New("ERR invalid cursor")
+	}
+
+	options.cursor = cursor
+
+	for i := 1; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+		hasValue := i+1 < len(args)
+
+		switch {
+		case option == SCAN_MATCH && hasValue:
+			options.pattern = args[i+1].(string)
+
+			// a lone star matches everything, skip the matching altogether
+			if options.pattern == "*" {
+				options.pattern = ""
+			}
+
+			i++
+
+		case option == SCAN_COUNT && hasValue:
+			count, err := strconv.Atoi(args[i+1].(string))
+
+			if err != nil {
+				return options, errors.New("ERR value is not an integer or out of range")
+			}
+
+			if count < 1 {
+				return options, errors.New("ERR syntax error")
+			}
+
+			options.count = count
+			i++
+
+		case option == SCAN_TYPE && hasValue && slices.Contains(extraOptions, SCAN_TYPE):
+			options.keyType = args[i+1].(string)
+			i++
+
+		case option == SCAN_NOVALUES && slices.Contains(extraOptions, SCAN_NOVALUES):
+			options.noValues = true
+
+		default:
+			return options, errors.New("ERR syntax error")
+		}
+	}
+
+	return options, nil
+}
+
+// scanReply replies with the next cursor and the items of the current batch
+func scanReply(client *Client, cursor uint64, items []string) ([]byte, error) {
+	reply := []resp.ArrayType{
+		{Value: strconv.FormatUint(cursor, 10), Type: resp.BULK_STRING},
+		{Value: bulkStrings(items), Type: resp.ARRAY},
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
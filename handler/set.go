@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+func (h *Handler) SAdd(client *Client, args ...any) ([]byte, error) {
+	added, err := h.db(client).SAdd(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, added)
+
+	return data, err
+}
+
+func (h *Handler) SRem(client *Client, args ...any) ([]byte, error) {
+	removed, err := h.db(client).SRem(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, removed)
+
+	return data, err
+}
+
+func (h *Handler) SMembers(client *Client, args ...any) ([]byte, error) {
+	members, err := h.db(client).SMembers(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SET, bulkStrings(members))
+
+	return data, err
+}
+
+func (h *Handler) SIsMember(client *Client, args ...any) ([]byte, error) {
+	isMember, err := h.db(client).SIsMember(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(isMember))
+
+	return data, err
+}
+
+func (h *Handler) SMIsMember(client *Client, args ...any) ([]byte, error) {
+	areMembers, err := h.db(client).SMIsMember(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reply := []resp.ArrayType{}
+
+	for _, isMember := range areMembers {
+		reply = append(reply, resp.ArrayType{Value: boolToInt(isMember), Type: resp.INTEGER})
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
+
+func (h *Handler) SCard(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).SCard(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+// SPop removes a random member, or count of them
+// SPOP key [count]
+func (h *Handler) SPop(client *Client, args ...any) ([]byte, error) {
+	return h.randomMembers(client, "spop", true, args...)
+}
+
+// SRandMember replies with a random member, or count of them
+// SRANDMEMBER key [count]
+func (h *Handler) SRandMember(client *Client, args ...any) ([]byte, error) {
+	return h.randomMembers(client, "srandmember", false, args...)
+}
+
+func (h *Handler) randomMembers(client *Client, command string, remove bool, args ...any) ([]byte, error) {
+	if len(args) > 2 {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	key := args[0].(string)
+	count := 1
+
+	if len(args) == 2 {
+		var err error
+		count, err = strconv.Atoi(args[1].(string))
+
+		// only SRANDMEMBER accepts a negative count
+		if err != nil || (remove && count < 0) {
+			return nil, errors.New("ERR value is out of range, must be positive")
+		}
+	}
+
+	var members []string
+	var err error
+
+	if remove {
+		members, err = h.db(client).SPop(key, count)
+	} else {
+		members, err = h.db(client).SRandMember(key, count)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the members are random, so the ones popped are logged
+	if remove && len(members) > 0 {
+		propagated := []any{key}
+
+		for _, member := range members {
+			propagated = append(propagated, member)
+		}
+
+		client.Propagate(SREM, propagated...)
+	}
+
+	if len(args) == 2 {
+		data, err := client.Serialize(resp.ARRAY, bulkStrings(members))
+		return data, err
+	}
+
+	var member interface{}
+
+	if len(members) > 0 {
+		member = members[0]
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, member)
+
+	return data, err
+}
+
+func (h *Handler) SMove(client *Client, args ...any) ([]byte, error) {
+	moved, err := h.db(client).SMove(args[0].(string), args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(moved))
+
+	return data, err
+}
+
+func (h *Handler) SInter(client *Client, args ...any) ([]byte, error) {
+	return h.setOperation(client, h.db(client).SInter, args...)
+}
+
+func (h *Handler) SUnion(client *Client, args ...any) ([]byte, error) {
+	return h.setOperation(client, h.db(client).SUnion, args...)
+}
+
+func (h *Handler) SDiff(client *Client, args ...any) ([]byte, error) {
+	return h.setOperation(client, h.db(client).SDiff, args...)
+}
+
+func (h *Handler) SInterStore(client *Client, args ...any) ([]byte, error) {
+	return h.setOperationStore(client, h.db(client).SInter, args...)
+}
+
+func (h *Handler) SUnionStore(client *Client, args ...any) ([]byte, error) {
+	return h.setOperationStore(client, h.db(client).SUnion, args...)
+}
+
+func (h *Handler) SDiffStore(client *Client, args ...any) ([]byte, error) {
+	return h.setOperationStore(client, h.db(client).SDiff, args...)
+}
+
+// setOperation replies with the result of operation over the given keys
+// SINTER key [key ...]
+func (h *Handler) setOperation(client *Client, operation func(keys ...string) ([]string, error), args ...any) ([]byte, error) {
+	members, err := operation(stringArgs(args)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SET, bulkStrings(members))
+
+	return data, err
+}
+
+// setOperationStore stores the result of operation over the given keys in destination
+// SINTERSTORE destination key [key ...]
+func (h *Handler) setOperationStore(client *Client, operation func(keys ...string) ([]string, error), args ...any) ([]byte, error) {
+	members, err := operation(stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := h.db(client).SStore(args[0].(string), members)
+
+	data, err := client.Serialize(resp.INTEGER, stored)
+
+	return data, err
+}
+
+// SInterCard replies with the size of the intersection
+// SINTERCARD numkeys key [key ...] [LIMIT limit]
+func (h *Handler) SInterCard(client *Client, args ...any) ([]byte, error) {
+	numKeys, err := strconv.Atoi(args[0].(string))
+
+	if err != nil || numKeys <= 0 {
+		return nil, errors.New("ERR numkeys should be greater than 0")
+	}
+
+	if numKeys > len(args)-1 {
+		return nil, errors.New("ERR Number of keys can't be greater than number of args")
+	}
+
+	keys := stringArgs(args[1 : numKeys+1])
+	limit := 0
+	options := args[numKeys+1:]
+
+	for i := 0; i < len(options); i++ {
+		if strings.ToUpper(options[i].(string)) != "LIMIT" || i+1 >= len(options) {
+			return nil, errors.New("ERR syntax error")
+		}
+
+		limit, err = strconv.Atoi(options[i+1].(string))
+
+		if err != nil || limit < 0 {
+			return nil, errors.New("ERR LIMIT can't be negative")
+		}
+
+		i++
+	}
+
+	members, err := h.db(client).SInterCard(limit, keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, len(members))
+
+	return data, err
+}
+
+// SScan iterates the members of a set
+// SSCAN key cursor [MATCH pattern] [COUNT count]
+func (h *Handler) SScan(client *Client, args ...any) ([]byte, error) {
+	options, err := parseScanOptions(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	members, cursor, err := h.db(client).SScan(args[0].(string), options.cursor, options.pattern, options.count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReply(client, cursor, members)
+}
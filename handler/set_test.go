@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSPopPropagatesRemovedMembers(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "SADD", "set", "a", "b", "c")
+
+	reply, _ := call(h, client, "SPOP", "set", "2")
+	popped := reply.([]any)
+	assert.Len(t, popped, 2)
+
+	// replaying SPOP would remove other random members
+	assert.Equal(t, []Command{{Name: SREM, Args: append([]any{"set"}, popped...), DB: client.DB}}, client.TakePropagated())
+
+	reply, _ = call(h, client, "SPOP", "set")
+	assert.Equal(t, []Command{{Name: SREM, Args: []any{"set", reply}, DB: client.DB}}, client.TakePropagated())
+
+	// nothing is logged when nothing was popped
+	reply, _ = call(h, client, "SPOP", "set")
+	assert.Nil(t, reply)
+	assert.Empty(t, client.TakePropagated())
+
+	_, err := call(h, client, "SPOP", "set", "-1")
+	assert.EqualError(t, err, "ERR value is out of range, must be positive")
+}
+
+func TestSRandMemberCounts(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "SADD", "set", "a", "b")
+
+	reply, _ := call(h, client, "SRANDMEMBER", "set", "-5")
+	assert.Len(t, reply, 5)
+
+	reply, _ = call(h, client, "SRANDMEMBER", "set", "5")
+	assert.ElementsMatch(t, []any{"a", "b"}, reply)
+
+	reply, _ = call(h, client, "SRANDMEMBER", "missing")
+	assert.Nil(t, reply)
+
+	assert.Empty(t, client.TakePropagated())
+}
+
+func TestSetOperationStore(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "SADD", "a", "1", "2", "3")
+	call(h, client, "SADD", "b", "2", "3", "4")
+
+	reply, _ := call(h, client, "SINTERSTORE", "inter", "a", "b")
+	assert.Equal(t, 2, reply)
+
+	reply, _ = call(h, client, "SUNIONSTORE", "union", "a", "b")
+	assert.Equal(t, 4, reply)
+
+	reply, _ = call(h, client, "SDIFFSTORE", "diff", "a", "b")
+	assert.Equal(t, 1, reply)
+
+	reply, _ = call(h, client, "SMEMBERS", "diff")
+	assert.Equal(t, []any{"1"}, reply)
+
+	// an empty result deletes the destination
+	reply, _ = call(h, client, "SINTERSTORE", "diff", "a", "missing")
+	assert.Equal(t, 0, reply)
+
+	reply, _ = call(h, client, "EXISTS", "diff")
+	assert.Equal(t, 0, reply)
+}
+
+func TestSInterCardLimit(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "SADD", "a", "1", "2", "3")
+	call(h, client, "SADD", "b", "1", "2", "3", "4")
+
+	tests := []struct {
+		args  []any
+		reply any
+		err   string
+	}{
+		{args: []any{"2", "a", "b"}, reply: 3},
+		{args: []any{"2", "a", "b", "LIMIT", "2"}, reply: 2},
+		{args: []any{"2", "a", "b", "LIMIT", "0"}, reply: 3},
+		{args: []any{"1", "missing"}, reply: 0},
+		{args: []any{"2", "a", "b", "LIMIT", "-1"}, err: "ERR LIMIT can't be negative"},
+		{args: []any{"2", "a", "b", "LIMIT"}, err: "ERR syntax error"},
+		{args: []any{"0", "a"}, err: "ERR numkeys should be greater than 0"},
+		{args: []any{"3", "a", "b"}, err: "ERR Number of keys can't be greater than number of args"},
+		{args: []any{"9223372036854775807", "a"}, err: "ERR Number of keys can't be greater than number of args"},
+	}
+
+	for _, test := range tests {
+		reply, err := call(h, client, "SINTERCARD", test.args...)
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.args)
+			continue
+		}
+
+		assert.NoError(t, err, test.args)
+		assert.Equal(t, test.reply, reply, test.args)
+	}
+}
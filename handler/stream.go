@@ -0,0 +1,1181 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+var (
+	errStreamLimit     = errors.New("ERR syntax error, LIMIT cannot be used without the special ~ option")
+	errStreamMaxLen    = errors.New("ERR The MAXLEN argument must be >= 0.")
+	errStreamLimitSign = errors.New("ERR The LIMIT argument must be >= 0.")
+	errStreamStartID   = errors.New("ERR invalid start ID for the interval")
+	errStreamEndID     = errors.New("ERR invalid end ID for the interval")
+	errEntriesRead     = errors.New("ERR value for ENTRIESREAD must be positive or -1")
+	errStreamTimeout   = errors.New("ERR timeout is not an integer or out of range")
+)
+
+// XAdd appends an entry to the stream, replies with its ID
+// XADD key [NOMKSTREAM] [MAXLEN | MINID [= | ~] threshold [LIMIT count]] * | id field value [field value ...]
+func (h *Handler) XAdd(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	create := true
+	trim := stream.TrimOptions{}
+	i := 1
+
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i].(string)) {
+		case "NOMKSTREAM":
+			create = false
+		case stream.TRIM_MAXLEN, stream.TRIM_MINID:
+			var err error
+
+			if trim, i, err = parseStreamTrim(args, i); err != nil {
+				return nil, err
+			}
+		default:
+			break options
+		}
+	}
+
+	fields := stringArgs(args[min(i+1, len(args)):])
+
+	if i >= len(args) || len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, errors.New("ERR wrong number of arguments for 'xadd' command")
+	}
+
+	id, err := stream.ParseNewID(args[i].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	store := h.db(client)
+	added, found, err := store.XAdd(key, id, fields, create)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
+
+	removed, length, err := store.XTrim(key, trim)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// generated IDs and approximate trimming are replayed as their outcome
+	propagated := []any{key}
+
+	if removed > 0 {
+		propagated = append(propagated, stream.TRIM_MAXLEN, "=", strconv.Itoa(length))
+	}
+
+	propagated = append(propagated, added.String())
+
+	for _, field := range fields {
+		propagated = append(propagated, field)
+	}
+
+	client.Propagate(XADD, propagated...)
+
+	return client.Serialize(resp.BULK_STRING, added.String())
+}
+
+// parseStreamTrim parses "MAXLEN | MINID [= | ~] threshold [LIMIT count]"
+// starting at args[i], returns the index of its last argument
+func parseStreamTrim(args []any, i int) (stream.TrimOptions, int, error) {
+	options := stream.TrimOptions{Strategy: strings.ToUpper(args[i].(string))}
+
+	if i+1 < len(args) && (args[i+1] == "=" || args[i+1] == "~") {
+		options.Approximate = args[i+1] == "~"
+		i++
+	}
+
+	if i+1 >= len(args) {
+		return options, i, errSyntax
+	}
+
+	i++
+	threshold := args[i].(string)
+
+	if options.Strategy == stream.TRIM_MAXLEN {
+		maxLen, err := strconv.ParseInt(threshold, 10, 64)
+
+		if err != nil {
+			return options, i, errNotInteger
+		}
+
+		if maxLen < 0 {
+			return options, i, errStreamMaxLen
+		}
+
+		options.MaxLen = maxLen
+	} else {
+		minID, err := stream.ParseID(threshold, 0)
+
+		if err != nil {
+			return options, i, err
+		}
+
+		options.MinID = minID
+	}
+
+	if i+1 < len(args) && strings.ToUpper(args[i+1].(string)) == "LIMIT" {
+		if i+2 >= len(args) {
+			return options, i, errSyntax
+		}
+
+		limit, err := strconv.ParseInt(args[i+2].(string), 10, 64)
+
+		if err != nil {
+			return options, i, errNotInteger
+		}
+
+		if limit < 0 {
+			return options, i, errStreamLimitSign
+		}
+
+		if !options.Approximate {
+			return options, i, errStreamLimit
+		}
+
+		// a zero limit removes as many entries as needed
+		if limit == 0 {
+			limit = math.MaxInt64
+		}
+
+		options.Limit = limit
+		i += 2
+	}
+
+	return options, i, nil
+}
+
+// XTrim removes the first entries of the stream, replies with their number
+// XTRIM key MAXLEN | MINID [= | ~] threshold [LIMIT count]
+func (h *Handler) XTrim(client *Client, args ...any) ([]byte, error) {
+	strategy := strings.ToUpper(args[1].(string))
+
+	if strategy != stream.TRIM_MAXLEN && strategy != stream.TRIM_MINID {
+		return nil, errSyntax
+	}
+
+	trim, last, err := parseStreamTrim(args, 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if last != len(args)-1 {
+		return nil, errSyntax
+	}
+
+	key := args[0].(string)
+	removed, length, err := h.db(client).XTrim(key, trim)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if removed > 0 {
+		client.Propagate(XTRIM, key, stream.TRIM_MAXLEN, "=", strconv.Itoa(length))
+	}
+
+	return client.Serialize(resp.INTEGER, int(removed))
+}
+
+// XLen replies with the number of entries of the stream
+// XLEN key
+func (h *Handler) XLen(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).XLen(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Serialize(resp.INTEGER, length)
+}
+
+// XRange replies with the entries between start and end
+// XRANGE key start end [COUNT count]
+func (h *Handler) XRange(client *Client, args ...any) ([]byte, error) {
+	return h.xrange(client, "xrange", false, args...)
+}
+
+// XRevRange replies with the entries between end and start, in reverse order
+// XREVRANGE key end start [COUNT count]
+func (h *Handler) XRevRange(client *Client, args ...any) ([]byte, error) {
+	return h.xrange(client, "xrevrange", true, args...)
+}
+
+func (h *Handler) xrange(client *Client, command string, reverse bool, args ...any) ([]byte, error) {
+	if len(args) != 3 && len(args) != 5 {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	startArg, endArg := args[1].(string), args[2].(string)
+
+	if reverse {
+		startArg, endArg = endArg, startArg
+	}
+
+	start, end, err := parseStreamRange(startArg, endArg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+
+	if len(args) == 5 {
+		if strings.ToUpper(args[3].(string)) != "COUNT" {
+			return nil, errSyntax
+		}
+
+		if count, err = strconv.Atoi(args[4].(string)); err != nil {
+			return nil, errNotInteger
+		}
+
+		if count <= 0 {
+			return client.Serialize(resp.ARRAY, []resp.ArrayType{})
+		}
+	}
+
+	entries, err := h.db(client).XRange(args[0].(string), start, end, count, reverse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Serialize(resp.ARRAY, streamEntries(entries))
+}
+
+// parseStreamRange parses the bounds of XRANGE, exclusive bounds are moved to
+// the next ID within the range
+func parseStreamRange(startArg string, endArg string) (stream.ID, stream.ID, error) {
+	start, exclusive, err := stream.ParseRangeID(startArg, false)
+
+	if err != nil {
+		return start, start, err
+	}
+
+	if exclusive {
+		if start, exclusive = start.Next(); !exclusive {
+			return start, start, errStreamStartID
+		}
+	}
+
+	end, exclusive, err := stream.ParseRangeID(endArg, true)
+
+	if err != nil {
+		return start, end, err
+	}
+
+	if exclusive {
+		if end, exclusive = end.Prev(); !exclusive {
+			return start, end, errStreamEndID
+		}
+	}
+
+	return start, end, nil
+}
+
+// XDel removes the entries, replies with the number of entries removed
+// XDEL key id [id ...]
+func (h *Handler) XDel(client *Client, args ...any) ([]byte, error) {
+	ids, err := parseStreamIDs(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := h.db(client).XDel(args[0].(string), ids...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Serialize(resp.INTEGER, deleted)
+}
+
+func parseStreamIDs(args []any) ([]stream.ID, error) {
+	ids := make([]stream.ID, 0, len(args))
+
+	for _, arg := range args {
+		id, err := stream.ParseID(arg.(string), 0)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// streamReadOptions are the options of XREAD and XREADGROUP
+type streamReadOptions struct {
+	count   int
+	block   bool
+	timeout time.Duration
+	noAck   bool
+	group   string
+	// consumer of the group reading the entries
+	consumer string
+	keys     []string
+	ids      []string
+	// index of the first ID in the arguments
+	idsStart int
+}
+
+// parseStreamRead parses the arguments of XREAD, or of XREADGROUP when group is set
+func parseStreamRead(command string, args []any, group bool) (streamReadOptions, error) {
+	options := streamReadOptions{}
+	i := 0
+
+	for ; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+		hasValue := i+1 < len(args)
+
+		switch {
+		case option == "STREAMS":
+			remaining := args[i+1:]
+
+			if len(remaining) == 0 || len(remaining)%2 != 0 {
+				return options, fmt.Errorf("ERR Unbalanced '%s' list of streams: for each stream key an ID or '$' must be specified.", command)
+			}
+
+			half := len(remaining) / 2
+			options.keys = stringArgs(remaining[:half])
+			options.ids = stringArgs(remaining[half:])
+			options.idsStart = i + 1 + half
+
+			if group && options.group == "" {
+				return options, errors.New("ERR Missing GROUP option for XREADGROUP")
+			}
+
+			return options, nil
+
+		case option == "COUNT" && hasValue:
+			count, err := strconv.Atoi(args[i+1].(string))
+
+			if err != nil {
+				return options, errNotInteger
+			}
+
+			options.count = max(count, 0)
+			i++
+
+		case option == "BLOCK" && hasValue:
+			milliseconds, err := strconv.ParseInt(args[i+1].(string), 10, 64)
+
+			if err != nil {
+				return options, errStreamTimeout
+			}
+
+			if milliseconds < 0 {
+				return options, errors.New("ERR timeout is negative")
+			}
+
+			options.block = true
+			options.timeout = time.Duration(milliseconds) * time.Millisecond
+			i++
+
+		case option == "GROUP" && group && i+2 < len(args):
+			options.group = args[i+1].(string)
+			options.consumer = args[i+2].(string)
+			i += 2
+
+		case option == "NOACK" && group:
+			options.noAck = true
+
+		default:
+			return options, errSyntax
+		}
+	}
+
+	return options, errSyntax
+}
+
+// XRead replies with the entries added after the IDs, blocking until one is
+// added when BLOCK is given. $ reads the entries added after the command.
+// XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]
+func (h *Handler) XRead(client *Client, args ...any) ([]byte, error) {
+	options, err := parseStreamRead("xread", args, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	store := h.db(client)
+	ids := make([]stream.ID, len(options.keys))
+	resolved := false
+
+	for i, key := range options.keys {
+		if options.ids[i] == "$" {
+			if ids[i], err = store.XLastID(key); err != nil {
+				return nil, err
+			}
+
+			resolved = true
+		} else if ids[i], err = stream.ParseID(options.ids[i], 0); err != nil {
+			return nil, err
+		}
+	}
+
+	reply := streamReads{}
+
+	for i, key := range options.keys {
+		entries, err := store.XRead(key, ids[i], options.count)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entries) > 0 {
+			reply.add(key, entries)
+		}
+	}
+
+	if len(reply) > 0 {
+		return reply.serialize(client)
+	}
+
+	if !options.block {
+		return client.Serialize(resp.ARRAY, nil)
+	}
+
+	// once blocked, the entries are read after the IDs $ stood for now
+	var blockArgs []any
+
+	if resolved {
+		blockArgs = append([]any{}, args[:options.idsStart]...)
+
+		for _, id := range ids {
+			blockArgs = append(blockArgs, id.String())
+		}
+	}
+
+	return h.blockWithArgs(client, options.keys, options.timeout, resp.ARRAY, blockArgs)
+}
+
+// XReadGroup delivers entries to a consumer of the group. > reads the entries
+// never delivered to the group, blocking until one is added when BLOCK is
+// given, other IDs read the entries pending for the consumer after them.
+// XREADGROUP GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] id [id ...]
+func (h *Handler) XReadGroup(client *Client, args ...any) ([]byte, error) {
+	options, err := parseStreamRead("xreadgroup", args, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	store := h.db(client)
+	reply := streamReads{}
+	history := false
+
+	for i, key := range options.keys {
+		fromLast := options.ids[i] == ">"
+		id := stream.ID{}
+
+		if !fromLast {
+			if id, err = stream.ParseID(options.ids[i], 0); err != nil {
+				return nil, err
+			}
+
+			history = true
+		}
+
+		entries, err := store.XReadGroup(key, options.group, options.consumer, id, fromLast, options.count, options.noAck)
+
+		if err != nil {
+			return nil, groupError(err, fmt.Sprintf("NOGROUP No such key '%s' or consumer group '%s' in XREADGROUP with GROUP option", key, options.group))
+		}
+
+		// pending entries are always replied, even when there are none
+		if !fromLast || len(entries) > 0 {
+			reply.add(key, entries)
+		}
+
+		if fromLast && len(entries) > 0 {
+			if err := h.propagateDelivery(client, key, options, entries); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(reply) > 0 || history {
+		return reply.serialize(client)
+	}
+
+	if !options.block {
+		return client.Serialize(resp.ARRAY, nil)
+	}
+
+	return h.block(client, options.keys, options.timeout, resp.ARRAY)
+}
+
+// propagateDelivery logs the entries delivered to a consumer as claims of
+// those entries, followed by the new position of the group
+func (h *Handler) propagateDelivery(client *Client, key string, options streamReadOptions, entries []stream.Entry) error {
+	group, err := h.db(client).XGroup(key, options.group)
+
+	if err != nil {
+		return err
+	}
+
+	if !options.noAck {
+		now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+		for _, entry := range entries {
+			client.Propagate(XCLAIM, key, options.group, options.consumer, "0", entry.ID.String(),
+				"TIME", now, "RETRYCOUNT", "1", "FORCE", "JUSTID", "LASTID", group.LastID.String())
+		}
+	}
+
+	client.Propagate(XGROUP, "SETID", key, options.group, group.LastID.String(),
+		"ENTRIESREAD", strconv.FormatInt(group.EntriesRead, 10))
+
+	return nil
+}
+
+// streamReads is the reply of XREAD and XREADGROUP, the entries read by key
+type streamReads []resp.MapType
+
+func (r *streamReads) add(key string, entries []stream.Entry) {
+	*r = append(*r, mapEntry(key, resp.ARRAY, streamEntries(entries)))
+}
+
+// serialize replies with a map for resp3 clients, and with an array of key
+// and entries pairs for resp2 ones
+func (r streamReads) serialize(client *Client) ([]byte, error) {
+	if client.Protocol == resp.RESP3 {
+		return client.Serialize(resp.MAP, []resp.MapType(r))
+	}
+
+	items := make([]resp.ArrayType, 0, len(r))
+
+	for _, entry := range r {
+		pair := []resp.ArrayType{entry.Key, entry.Value}
+		items = append(items, resp.ArrayType{Value: pair, Type: resp.ARRAY})
+	}
+
+	return client.Serialize(resp.ARRAY, items)
+}
+
+// streamEntries builds the reply of entries, each an array of its ID and its
+// fields. Deleted entries still pending have null fields.
+func streamEntries(entries []stream.Entry) []resp.ArrayType {
+	items := make([]resp.ArrayType, 0, len(entries))
+
+	for _, entry := range entries {
+		items = append(items, streamEntry(entry))
+	}
+
+	return items
+}
+
+func streamEntry(entry stream.Entry) resp.ArrayType {
+	var fields any
+
+	if entry.Fields != nil {
+		fields = bulkStrings(entry.Fields)
+	}
+
+	item := []resp.ArrayType{
+		{Value: entry.ID.String(), Type: resp.BULK_STRING},
+		{Value: fields, Type: resp.ARRAY},
+	}
+
+	return resp.ArrayType{Value: item, Type: resp.ARRAY}
+}
+
+func streamIDs(ids []stream.ID) []resp.ArrayType {
+	items := make([]resp.ArrayType, 0, len(ids))
+
+	for _, id := range ids {
+		items = append(items, resp.ArrayType{Value: id.String(), Type: resp.BULK_STRING})
+	}
+
+	return items
+}
+
+// groupError replaces the error of a missing group with message
+func groupError(err error, message string) error {
+	if errors.Is(err, stream.ErrNoGroup) {
+		return errors.New(message)
+	}
+
+	return err
+}
+
+// XGroup manages the consumer groups of a stream
+// XGROUP CREATE key group id | $ [MKSTREAM] [ENTRIESREAD entries-read]
+// XGROUP SETID key group id | $ [ENTRIESREAD entries-read]
+// XGROUP DESTROY key group
+// XGROUP CREATECONSUMER key group consumer
+// XGROUP DELCONSUMER key group consumer
+// XGROUP HELP
+func (h *Handler) XGroup(client *Client, args ...any) ([]byte, error) {
+	subcommand := strings.ToUpper(args[0].(string))
+	store := h.db(client)
+
+	switch {
+	case (subcommand == "CREATE" || subcommand == "SETID") && len(args) >= 4:
+		key, group := args[1].(string), args[2].(string)
+		create := false
+		entriesRead := int64(-1)
+
+		for i := 4; i < len(args); i++ {
+			switch option := strings.ToUpper(args[i].(string)); {
+			case option == "MKSTREAM" && subcommand == "CREATE":
+				create = true
+			case option == "ENTRIESREAD" && i+1 < len(args):
+				value, err := strconv.ParseInt(args[i+1].(string), 10, 64)
+
+				if err != nil {
+					return nil, errNotInteger
+				}
+
+				if value < -1 {
+					return nil, errEntriesRead
+				}
+
+				entriesRead = value
+				i++
+			default:
+				return nil, errSyntax
+			}
+		}
+
+		last := args[3] == "$"
+		id := stream.ID{}
+
+		if !last {
+			var err error
+
+			if id, err = stream.ParseID(args[3].(string), 0); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+
+		if subcommand == "CREATE" {
+			err = store.XGroupCreate(key, group, id, last, entriesRead, create)
+		} else {
+			err = store.XGroupSetID(key, group, id, last, entriesRead)
+		}
+
+		if err != nil {
+			return nil, groupError(err, fmt.Sprintf("NOGROUP No such consumer group '%s' for key name '%s'", group, key))
+		}
+
+		return client.Serialize(resp.SIMPLE_STRING, "OK")
+
+	case subcommand == "DESTROY" && len(args) == 3:
+		destroyed, err := store.XGroupDestroy(args[1].(string), args[2].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return client.Serialize(resp.INTEGER, boolToInt(destroyed))
+
+	case (subcommand == "CREATECONSUMER" || subcommand == "DELCONSUMER") && len(args) == 4:
+		key, group, consumer := args[1].(string), args[2].(string), args[3].(string)
+		result := 0
+		var err error
+
+		if subcommand == "CREATECONSUMER" {
+			var created bool
+			created, err = store.XGroupCreateConsumer(key, group, consumer)
+			result = boolToInt(created)
+		} else {
+			result, err = store.XGroupDelConsumer(key, group, consumer)
+		}
+
+		if err != nil {
+			return nil, groupError(err, fmt.Sprintf("NOGROUP No such consumer group '%s' for key name '%s'", group, key))
+		}
+
+		return client.Serialize(resp.INTEGER, result)
+
+	case subcommand == "HELP" && len(args) == 1:
+		return helpReply(client, XGROUP,
+			"CREATE <key> <groupname> <id|$> [option]",
+			"    Create a new consumer group. Options are:",
+			"    * MKSTREAM",
+			"      Create the empty stream if it does not exist.",
+			"    * ENTRIESREAD entries_read",
+			"      Set the group's entries_read counter (internal use).",
+			"CREATECONSUMER <key> <groupname> <consumer>",
+			"    Create a new consumer in the specified group.",
+			"DELCONSUMER <key> <groupname> <consumer>",
+			"    Remove the specified consumer.",
+			"DESTROY <key> <groupname>",
+			"    Remove the specified group.",
+			"SETID <key> <groupname> <id|$> [ENTRIESREAD entries_read]",
+			"    Set the current group ID and entries_read counter.",
+		)
+	}
+
+	return nil, fmt.Errorf("ERR unknown subcommand or wrong number of arguments for '%s'. Try XGROUP HELP.", args[0].(string))
+}
+
+// XAck acknowledges entries delivered to the group, replies with the number
+// of entries which were pending
+// XACK key group id [id ...]
+func (h *Handler) XAck(client *Client, args ...any) ([]byte, error) {
+	ids, err := parseStreamIDs(args[2:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledged, err := h.db(client).XAck(args[0].(string), args[1].(string), ids...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Serialize(resp.INTEGER, acknowledged)
+}
+
+// XPending replies with a summary of the entries pending for the group, or
+// with the pending entries themselves when a range is given
+// XPENDING key group [[IDLE min-idle-time] start end count [consumer]]
+func (h *Handler) XPending(client *Client, args ...any) ([]byte, error) {
+	key, group := args[0].(string), args[1].(string)
+	noGroup := fmt.Sprintf("NOGROUP No such key '%s' or consumer group '%s'", key, group)
+	store := h.db(client)
+
+	if len(args) == 2 {
+		summary, err := store.XPendingSummary(key, group)
+
+		if err != nil {
+			return nil, groupError(err, noGroup)
+		}
+
+		if summary.Count == 0 {
+			reply := []resp.ArrayType{
+				{Value: 0, Type: resp.INTEGER},
+				{Value: nil, Type: resp.NULL},
+				{Value: nil, Type: resp.NULL},
+				{Value: nil, Type: resp.ARRAY},
+			}
+
+			return client.Serialize(resp.ARRAY, reply)
+		}
+
+		consumers := []resp.ArrayType{}
+
+		for _, consumer := range summary.Consumers {
+			pair := bulkStrings([]string{consumer.Name, strconv.Itoa(consumer.Pending)})
+			consumers = append(consumers, resp.ArrayType{Value: pair, Type: resp.ARRAY})
+		}
+
+		reply := []resp.ArrayType{
+			{Value: summary.Count, Type: resp.INTEGER},
+			{Value: summary.First.String(), Type: resp.BULK_STRING},
+			{Value: summary.Last.String(), Type: resp.BULK_STRING},
+			{Value: consumers, Type: resp.ARRAY},
+		}
+
+		return client.Serialize(resp.ARRAY, reply)
+	}
+
+	query := stream.PendingQuery{}
+	options := args[2:]
+
+	if strings.ToUpper(options[0].(string)) == "IDLE" && len(options) >= 2 {
+		minIdle, err := strconv.ParseInt(options[1].(string), 10, 64)
+
+		if err != nil {
+			return nil, errNotInteger
+		}
+
+		query.MinIdle = minIdle
+		options = options[2:]
+	}
+
+	if len(options) != 3 && len(options) != 4 {
+		return nil, errSyntax
+	}
+
+	var err error
+
+	if query.Start, query.End, err = parseStreamRange(options[0].(string), options[1].(string)); err != nil {
+		return nil, err
+	}
+
+	if query.Count, err = strconv.Atoi(options[2].(string)); err != nil {
+		return nil, errNotInteger
+	}
+
+	if len(options) == 4 {
+		query.Consumer = options[3].(string)
+	}
+
+	if query.Count <= 0 {
+		if _, err := store.XPendingSummary(key, group); err != nil {
+			return nil, groupError(err, noGroup)
+		}
+
+		return client.Serialize(resp.ARRAY, []resp.ArrayType{})
+	}
+
+	pending, err := store.XPending(key, group, query)
+
+	if err != nil {
+		return nil, groupError(err, noGroup)
+	}
+
+	now := time.Now().UnixMilli()
+	items := make([]resp.ArrayType, 0, len(pending))
+
+	for _, entry := range pending {
+		item := []resp.ArrayType{
+			{Value: entry.ID.String(), Type: resp.BULK_STRING},
+			{Value: entry.Consumer, Type: resp.BULK_STRING},
+			{Value: int(max(now-entry.DeliveryTime, 0)), Type: resp.INTEGER},
+			{Value: int(entry.DeliveryCount), Type: resp.INTEGER},
+		}
+
+		items = append(items, resp.ArrayType{Value: item, Type: resp.ARRAY})
+	}
+
+	return client.Serialize(resp.ARRAY, items)
+}
+
+// XClaim gives pending entries idle for at least min-idle-time milliseconds
+// to the consumer, replies with the entries claimed
+// XCLAIM key group consumer min-idle-time id [id ...] [IDLE ms] [TIME unix-time-milliseconds] [RETRYCOUNT count] [FORCE] [JUSTID] [LASTID lastid]
+func (h *Handler) XClaim(client *Client, args ...any) ([]byte, error) {
+	key, group, consumer := args[0].(string), args[1].(string), args[2].(string)
+
+	minIdle, err := strconv.ParseInt(args[3].(string), 10, 64)
+
+	if err != nil {
+		return nil, errors.New("ERR Invalid min-idle-time argument for XCLAIM")
+	}
+
+	options := stream.ClaimOptions{MinIdle: max(minIdle, 0), DeliveryTime: -1, RetryCount: -1}
+	ids := []stream.ID{}
+	i := 4
+
+	// IDs come first, up to the first argument which is not one
+	for ; i < len(args); i++ {
+		id, err := stream.ParseID(args[i].(string), 0)
+
+		if err != nil {
+			break
+		}
+
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, stream.ErrInvalidID
+	}
+
+	for ; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+		hasValue := i+1 < len(args)
+
+		switch {
+		case option == "FORCE":
+			options.Force = true
+		case option == "JUSTID":
+			options.JustID = true
+		case option == "IDLE" && hasValue:
+			idle, err := strconv.ParseInt(args[i+1].(string), 10, 64)
+
+			if err != nil {
+				return nil, errors.New("ERR Invalid IDLE option argument for XCLAIM")
+			}
+
+			options.DeliveryTime = time.Now().UnixMilli() - max(idle, 0)
+			i++
+		case option == "TIME" && hasValue:
+			deliveryTime, err := strconv.ParseInt(args[i+1].(string), 10, 64)
+
+			if err != nil {
+				return nil, errors.New("ERR Invalid TIME option argument for XCLAIM")
+			}
+
+			options.DeliveryTime = max(deliveryTime, 0)
+			i++
+		case option == "RETRYCOUNT" && hasValue:
+			retryCount, err := strconv.ParseInt(args[i+1].(string), 10, 64)
+
+			if err != nil || retryCount < 0 {
+				return nil, errors.New("ERR Invalid RETRYCOUNT option argument for XCLAIM")
+			}
+
+			options.RetryCount = retryCount
+			i++
+		case option == "LASTID" && hasValue:
+			if options.LastID, err = stream.ParseID(args[i+1].(string), 0); err != nil {
+				return nil, err
+			}
+
+			i++
+		default:
+			return nil, fmt.Errorf("ERR Unrecognized XCLAIM option '%s'", args[i].(string))
+		}
+	}
+
+	result, err := h.db(client).XClaim(key, group, consumer, ids, options)
+
+	if err != nil {
+		return nil, groupError(err, fmt.Sprintf("NOGROUP No such key '%s' or consumer group '%s'", key, group))
+	}
+
+	if err := h.propagateClaims(client, key, group, result); err != nil {
+		return nil, err
+	}
+
+	if options.JustID {
+		return client.Serialize(resp.ARRAY, claimedIDs(result))
+	}
+
+	return client.Serialize(resp.ARRAY, streamEntries(result.Entries))
+}
+
+// XAutoClaim gives the pending entries from start idle for at least
+// min-idle-time milliseconds to the consumer. Replies with the ID to resume
+// from, the entries claimed and the IDs of entries found deleted.
+// XAUTOCLAIM key group consumer min-idle-time start [COUNT count] [JUSTID]
+func (h *Handler) XAutoClaim(client *Client, args ...any) ([]byte, error) {
+	key, group, consumer := args[0].(string), args[1].(string), args[2].(string)
+
+	minIdle, err := strconv.ParseInt(args[3].(string), 10, 64)
+
+	if err != nil {
+		return nil, errors.New("ERR Invalid min-idle-time argument for XAUTOCLAIM")
+	}
+
+	start, exclusive, err := stream.ParseRangeID(args[4].(string), false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if exclusive {
+		if start, exclusive = start.Next(); !exclusive {
+			return nil, errStreamStartID
+		}
+	}
+
+	count := 100
+	justID := false
+
+	for i := 5; i < len(args); i++ {
+		switch option := strings.ToUpper(args[i].(string)); {
+		case option == "JUSTID":
+			justID = true
+		case option == "COUNT" && i+1 < len(args):
+			// the number of pending entries scanned is bounded as well
+			if count, err = strconv.Atoi(args[i+1].(string)); err != nil || count < 1 || count > math.MaxInt32/10 {
+				return nil, errors.New("ERR COUNT must be > 0")
+			}
+
+			i++
+		default:
+			return nil, errSyntax
+		}
+	}
+
+	result, err := h.db(client).XAutoClaim(key, group, consumer, max(minIdle, 0), start, count, justID)
+
+	if err != nil {
+		return nil, groupError(err, fmt.Sprintf("NOGROUP No such key '%s' or consumer group '%s'", key, group))
+	}
+
+	if err := h.propagateClaims(client, key, group, result); err != nil {
+		return nil, err
+	}
+
+	claimed := claimedIDs(result)
+
+	if !justID {
+		claimed = streamEntries(result.Entries)
+	}
+
+	reply := []resp.ArrayType{
+		{Value: result.Next.String(), Type: resp.BULK_STRING},
+		{Value: claimed, Type: resp.ARRAY},
+		{Value: streamIDs(result.Deleted), Type: resp.ARRAY},
+	}
+
+	return client.Serialize(resp.ARRAY, reply)
+}
+
+func claimedIDs(result stream.ClaimResult) []resp.ArrayType {
+	ids := make([]stream.ID, 0, len(result.Entries))
+
+	for _, entry := range result.Entries {
+		ids = append(ids, entry.ID)
+	}
+
+	return streamIDs(ids)
+}
+
+// propagateClaims logs each claimed entry as a claim replaying its delivery
+// time and count, and the entries found deleted as acknowledged
+func (h *Handler) propagateClaims(client *Client, key string, group string, result stream.ClaimResult) error {
+	if len(result.Pending) == 0 && len(result.Deleted) == 0 {
+		return nil
+	}
+
+	info, err := h.db(client).XGroup(key, group)
+
+	if err != nil {
+		return err
+	}
+
+	for _, pending := range result.Pending {
+		client.Propagate(XCLAIM, key, group, pending.Consumer, "0", pending.ID.String(),
+			"TIME", strconv.FormatInt(pending.DeliveryTime, 10),
+			"RETRYCOUNT", strconv.FormatInt(pending.DeliveryCount, 10),
+			"FORCE", "JUSTID", "LASTID", info.LastID.String())
+	}
+
+	for _, id := range result.Deleted {
+		client.Propagate(XACK, key, group, id.String())
+	}
+
+	return nil
+}
+
+// XInfo describes a stream, its consumer groups or the consumers of a group
+// XINFO STREAM key
+// XINFO GROUPS key
+// XINFO CONSUMERS key group
+// XINFO HELP
+func (h *Handler) XInfo(client *Client, args ...any) ([]byte, error) {
+	subcommand := strings.ToUpper(args[0].(string))
+	store := h.db(client)
+
+	switch {
+	case subcommand == "STREAM" && len(args) == 2:
+		info, err := store.XInfoStream(args[1].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		reply := []resp.MapType{
+			mapEntry("length", resp.INTEGER, info.Length),
+			mapEntry("radix-tree-keys", resp.INTEGER, info.Nodes),
+			mapEntry("radix-tree-nodes", resp.INTEGER, info.Nodes),
+			mapEntry("last-generated-id", resp.BULK_STRING, info.LastID.String()),
+			mapEntry("max-deleted-entry-id", resp.BULK_STRING, info.MaxDeletedID.String()),
+			mapEntry("entries-added", resp.INTEGER, int(info.EntriesAdded)),
+			mapEntry("recorded-first-entry-id", resp.BULK_STRING, info.FirstID.String()),
+			mapEntry("groups", resp.INTEGER, info.Groups),
+			{Key: resp.ArrayType{Value: "first-entry", Type: resp.BULK_STRING}, Value: optionalEntry(info.FirstEntry)},
+			{Key: resp.ArrayType{Value: "last-entry", Type: resp.BULK_STRING}, Value: optionalEntry(info.LastEntry)},
+		}
+
+		return client.Serialize(resp.MAP, reply)
+
+	case subcommand == "GROUPS" && len(args) == 2:
+		groups, err := store.XInfoGroups(args[1].(string))
+
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]resp.ArrayType, 0, len(groups))
+
+		for _, group := range groups {
+			reply := []resp.MapType{
+				mapEntry("name", resp.BULK_STRING, group.Name),
+				mapEntry("consumers", resp.INTEGER, group.Consumers),
+				mapEntry("pending", resp.INTEGER, group.Pending),
+				mapEntry("last-delivered-id", resp.BULK_STRING, group.LastID.String()),
+				unknownCount("entries-read", group.EntriesRead),
+				unknownCount("lag", group.Lag),
+			}
+
+			items = append(items, resp.ArrayType{Value: reply, Type: resp.MAP})
+		}
+
+		return client.Serialize(resp.ARRAY, items)
+
+	case subcommand == "CONSUMERS" && len(args) == 3:
+		key, group := args[1].(string), args[2].(string)
+		consumers, err := store.XInfoConsumers(key, group)
+
+		if err != nil {
+			return nil, groupError(err, fmt.Sprintf("NOGROUP No such consumer group '%s' for key name '%s'", group, key))
+		}
+
+		now := time.Now().UnixMilli()
+		items := make([]resp.ArrayType, 0, len(consumers))
+
+		for _, consumer := range consumers {
+			inactive := int64(-1)
+
+			if consumer.ActiveTime != -1 {
+				inactive = max(now-consumer.ActiveTime, 0)
+			}
+
+			reply := []resp.MapType{
+				mapEntry("name", resp.BULK_STRING, consumer.Name),
+				mapEntry("pending", resp.INTEGER, consumer.Pending),
+				mapEntry("idle", resp.INTEGER, int(max(now-consumer.SeenTime, 0))),
+				mapEntry("inactive", resp.INTEGER, int(inactive)),
+			}
+
+			items = append(items, resp.ArrayType{Value: reply, Type: resp.MAP})
+		}
+
+		return client.Serialize(resp.ARRAY, items)
+
+	case subcommand == "HELP" && len(args) == 1:
+		return helpReply(client, XINFO,
+			"CONSUMERS <key> <groupname>",
+			"    Show consumers of <groupname>.",
+			"GROUPS <key>",
+			"    Show the stream consumer groups.",
+			"STREAM <key>",
+			"    Show information about the stream.",
+		)
+	}
+
+	return nil, fmt.Errorf("ERR unknown subcommand or wrong number of arguments for '%s'. Try XINFO HELP.", args[0].(string))
+}
+
+// optionalEntry builds the reply of an entry, a null when there is none
+func optionalEntry(entry *stream.Entry) resp.ArrayType {
+	if entry == nil {
+		return resp.ArrayType{Value: nil, Type: resp.NULL}
+	}
+
+	return streamEntry(*entry)
+}
+
+// unknownCount builds a map entry holding count, a null when it is -1
+func unknownCount(key string, count int64) resp.MapType {
+	if count == -1 {
+		return mapEntry(key, resp.NULL, nil)
+	}
+
+	return mapEntry(key, resp.INTEGER, int(count))
+}
@@ -0,0 +1,452 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// parseSetOptions parses the options of SET, or those of GETEX when getEx is
+// set. persist reports the PERSIST option of GETEX.
+func parseSetOptions(command string, args []any, getEx bool) (options data.SetOptions, persist bool, err error) {
+	hasExpiry := false
+
+	for i := 0; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+
+		switch option {
+		case "NX", "XX":
+			if getEx || options.Condition != data.SET_ALWAYS {
+				return options, false, errSyntax
+			}
+
+			options.Condition = option
+
+		case "GET":
+			if getEx {
+				return options, false, errSyntax
+			}
+
+			options.Get = true
+
+		case "KEEPTTL":
+			if getEx || hasExpiry {
+				return options, false, errSyntax
+			}
+
+			options.KeepTTL = true
+			hasExpiry = true
+
+		case "PERSIST":
+			if !getEx || hasExpiry {
+				return options, false, errSyntax
+			}
+
+			persist = true
+			hasExpiry = true
+
+		case "EX", "PX", "EXAT", "PXAT":
+			if hasExpiry || i+1 >= len(args) {
+				return options, false, errSyntax
+			}
+
+			if options.ExpireAt, err = parseSetExpiry(command, option, args[i+1].(string)); err != nil {
+				return options, false, err
+			}
+
+			hasExpiry = true
+			i++
+
+		default:
+			return options, false, errSyntax
+		}
+	}
+
+	return options, persist, nil
+}
+
+// parseSetExpiry converts the value of an EX, PX, EXAT or PXAT option to a
+// unix time in milliseconds, it must be positive
+func parseSetExpiry(command string, option string, arg string) (int64, error) {
+	value, err := strconv.ParseInt(arg, 10, 64)
+
+	if err != nil {
+		return 0, errNotInteger
+	}
+
+	if value <= 0 {
+		return 0, fmt.Errorf("ERR invalid expire time in '%s' command", command)
+	}
+
+	unit := time.Second
+
+	if option == "PX" || option == "PXAT" {
+		unit = time.Millisecond
+	}
+
+	return absoluteExpireTime(command, value, unit, option == "EXAT" || option == "PXAT")
+}
+
+// SetNX sets the key only when it does not exist, replies 1 when it was set
+// SETNX key value
+func (h *Handler) SetNX(client *Client, args ...any) ([]byte, error) {
+	_, set, err := h.db(client).SetWithOptions(args[0].(string), args[1].(string), data.SetOptions{Condition: data.SET_NX})
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(set))
+	return data, err
+}
+
+// SetEx sets the key along with a timeout in seconds
+// SETEX key seconds value
+func (h *Handler) SetEx(client *Client, args ...any) ([]byte, error) {
+	return h.setWithTimeout(client, "setex", "EX", args...)
+}
+
+// PSetEx sets the key along with a timeout in milliseconds
+// PSETEX key milliseconds value
+func (h *Handler) PSetEx(client *Client, args ...any) ([]byte, error) {
+	return h.setWithTimeout(client, "psetex", "PX", args...)
+}
+
+func (h *Handler) setWithTimeout(client *Client, command string, option string, args ...any) ([]byte, error) {
+	expireAt, err := parseSetExpiry(command, option, args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := h.db(client).SetWithOptions(args[0].(string), args[2].(string), data.SetOptions{ExpireAt: expireAt}); err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// GetSet sets the key, removing its timeout, and replies with the previous value
+// GETSET key value
+func (h *Handler) GetSet(client *Client, args ...any) ([]byte, error) {
+	previous, _, err := h.db(client).SetWithOptions(args[0].(string), args[1].(string), data.SetOptions{Get: true})
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, previous)
+	return data, err
+}
+
+// GetDel replies with the value of the key and deletes it
+// GETDEL key
+func (h *Handler) GetDel(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	value, found, err := h.db(client).Get(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		h.db(client).Delete(key)
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+	return data, err
+}
+
+// GetEx replies with the value of the key and changes its timeout. The new
+// timeout is logged instead of the command, as GETEX without options is a read.
+// GETEX key [EX seconds | PX milliseconds | EXAT unix-time-seconds |
+// PXAT unix-time-milliseconds | PERSIST]
+func (h *Handler) GetEx(client *Client, args ...any) ([]byte, error) {
+	options, persist, err := parseSetOptions("getex", args[1:], true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := args[0].(string)
+	value, found, err := h.db(client).Get(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case found && persist:
+		if h.db(client).Persist(key) {
+			client.Propagate(PERSIST, key)
+		}
+	case found && options.ExpireAt > 0:
+		if _, err := h.db(client).Expire(key, options.ExpireAt, data.EXPIRE_ALWAYS); err != nil {
+			return nil, err
+		}
+
+		client.Propagate(PEXPIREAT, key, strconv.FormatInt(options.ExpireAt, 10))
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+	return data, err
+}
+
+// Append appends to the string, replies with its new length
+// APPEND key value
+func (h *Handler) Append(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).Append(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+	return data, err
+}
+
+// StrLen replies with the length of the string, 0 when the key does not exist
+// STRLEN key
+func (h *Handler) StrLen(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).StrLen(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+	return data, err
+}
+
+// GetRange replies with the substring between two offsets, both inclusive,
+// negative offsets count from the end. SUBSTR is its former name.
+// GETRANGE key start end
+func (h *Handler) GetRange(client *Client, args ...any) ([]byte, error) {
+	start, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	end, err := strconv.Atoi(args[2].(string))
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	value, err := h.db(client).GetRange(args[0].(string), start, end)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+	return data, err
+}
+
+// SetRange overwrites part of the string, padding it with zero bytes when
+// needed, and replies with its new length
+// SETRANGE key offset value
+func (h *Handler) SetRange(client *Client, args ...any) ([]byte, error) {
+	offset, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	length, err := h.db(client).SetRange(args[0].(string), offset, args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+	return data, err
+}
+
+// LCS replies with the longest common subsequence of two strings, its length
+// with LEN, or the ranges it is made of with IDX
+// LCS key1 key2 [LEN] [IDX] [MINMATCHLEN min-match-len] [WITHMATCHLEN]
+func (h *Handler) LCS(client *Client, args ...any) ([]byte, error) {
+	onlyLength, withIndexes, withMatchLength := false, false, false
+	minMatchLength := 0
+
+	for i := 2; i < len(args); i++ {
+		option := strings.ToUpper(args[i].(string))
+
+		switch {
+		case option == "LEN":
+			onlyLength = true
+		case option == "IDX":
+			withIndexes = true
+		case option == "WITHMATCHLEN":
+			withMatchLength = true
+		case option == "MINMATCHLEN" && i+1 < len(args):
+			value, err := strconv.Atoi(args[i+1].(string))
+
+			if err != nil {
+				return nil, errNotInteger
+			}
+
+			minMatchLength = max(value, 0)
+			i++
+		default:
+			return nil, errSyntax
+		}
+	}
+
+	if onlyLength && withIndexes {
+		return nil, errors.New("ERR If you want both the length and indexes, please just use IDX.")
+	}
+
+	common, matches, err := h.db(client).LCS(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if onlyLength {
+		return client.Serialize(resp.INTEGER, len(common))
+	}
+
+	if !withIndexes {
+		return client.Serialize(resp.BULK_STRING, common)
+	}
+
+	items := []resp.ArrayType{}
+
+	for _, match := range matches {
+		if match.Len() < minMatchLength {
+			continue
+		}
+
+		item := []resp.ArrayType{
+			{Value: lcsRange(match.First), Type: resp.ARRAY},
+			{Value: lcsRange(match.Second), Type: resp.ARRAY},
+		}
+
+		if withMatchLength {
+			item = append(item, resp.ArrayType{Value: match.Len(), Type: resp.INTEGER})
+		}
+
+		items = append(items, resp.ArrayType{Value: item, Type: resp.ARRAY})
+	}
+
+	reply := []resp.MapType{
+		mapEntry("matches", resp.ARRAY, items),
+		mapEntry("len", resp.INTEGER, len(common)),
+	}
+
+	data, err := client.Serialize(resp.MAP, reply)
+	return data, err
+}
+
+func lcsRange(offsets data.LCSRange) []resp.ArrayType {
+	return []resp.ArrayType{
+		{Value: offsets.Start, Type: resp.INTEGER},
+		{Value: offsets.End, Type: resp.INTEGER},
+	}
+}
+
+// IncrBy adds to the integer value of the key, replies with the new value
+// INCRBY key increment
+func (h *Handler) IncrBy(client *Client, args ...any) ([]byte, error) {
+	increment, err := strconv.ParseInt(args[1].(string), 10, 64)
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	return h.incrBy(client, args[0].(string), increment)
+}
+
+// DecrBy subtracts from the integer value of the key, replies with the new value
+// DECRBY key decrement
+func (h *Handler) DecrBy(client *Client, args ...any) ([]byte, error) {
+	decrement, err := strconv.ParseInt(args[1].(string), 10, 64)
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	// the opposite of the smallest integer does not fit
+	if decrement == math.MinInt64 {
+		return nil, errors.New("ERR decrement would overflow")
+	}
+
+	return h.incrBy(client, args[0].(string), -decrement)
+}
+
+// incrBy is the path shared by every integer counter command
+func (h *Handler) incrBy(client *Client, key string, increment int64) ([]byte, error) {
+	value, err := h.db(client).IncrBy(key, increment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, int(value))
+	return data, err
+}
+
+// IncrByFloat adds to the float value of the key, replies with the new value.
+// The resulting value is logged rather than the increment, so replaying it
+// does not depend on float rounding.
+// INCRBYFLOAT key increment
+func (h *Handler) IncrByFloat(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	value, err := h.db(client).IncrByFloat(key, args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.Propagate(SET, key, value, "KEEPTTL")
+
+	data, err := client.Serialize(resp.BULK_STRING, value)
+	return data, err
+}
+
+// MGet replies with the values of the keys, nil for missing keys and for
+// keys which do not hold a string
+// MGET key [key ...]
+func (h *Handler) MGet(client *Client, args ...any) ([]byte, error) {
+	values := h.db(client).MGet(stringArgs(args)...)
+
+	data, err := client.Serialize(resp.ARRAY, bulkValues(values))
+	return data, err
+}
+
+// MSet sets the keys to their values
+// MSET key value [key value ...]
+func (h *Handler) MSet(client *Client, args ...any) ([]byte, error) {
+	if len(args)%2 != 0 {
+		return nil, errors.New("ERR wrong number of arguments for 'mset' command")
+	}
+
+	h.db(client).MSet(stringArgs(args), false)
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// MSetNX sets the keys to their values unless any of them exists, replies 1
+// when they were set
+// MSETNX key value [key value ...]
+func (h *Handler) MSetNX(client *Client, args ...any) ([]byte, error) {
+	if len(args)%2 != 0 {
+		return nil, errors.New("ERR wrong number of arguments for 'msetnx' command")
+	}
+
+	set := h.db(client).MSet(stringArgs(args), true)
+
+	data, err := client.Serialize(resp.INTEGER, boolToInt(set))
+	return data, err
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// Multi starts queuing the commands of the client, they are run by EXEC
+// MULTI
+func (h *Handler) Multi(client *Client, args ...any) ([]byte, error) {
+	if client.InTransaction() {
+		return nil, errors.New("ERR MULTI calls can not be nested")
+	}
+
+	client.transaction = &transaction{}
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// Exec runs the queued commands one after the other, replying with an array of
+// their replies. Nothing is run when a queued command was rejected, or when a
+// watched key was modified, the reply is then a nil array.
+// EXEC
+func (h *Handler) Exec(client *Client, args ...any) ([]byte, error) {
+	if !client.InTransaction() {
+		return nil, errors.New("ERR EXEC without MULTI")
+	}
+
+	transaction := client.transaction
+	client.transaction = nil
+
+	defer h.unwatch(client)
+
+	if transaction.failed {
+		return nil, errors.New("EXECABORT Transaction discarded because of previous errors.")
+	}
+
+	if client.watch != nil && client.watch.IsDirty() {
+		return client.Serialize(resp.ARRAY, nil)
+	}
+
+	replies := []byte(fmt.Sprintf("%c%d\r\n", resp.ARRAY_PREFIX, len(transaction.commands)))
+	propagated := []Command{}
+
+	// the server runs one command at a time, so nothing can be interleaved
+	// with the queued commands
+	for _, command := range transaction.commands {
+		reply, commands := h.execQueued(client, command)
+		replies = append(replies, reply...)
+		propagated = append(propagated, commands...)
+	}
+
+	// effects spanning several commands are logged as a transaction, so that a
+	// partial write is not replayed. Queued commands may have selected another
+	// database, their own is kept.
+	if len(propagated) > 1 {
+		client.propagated = append(client.propagated, Command{Name: MULTI, DB: propagated[0].DB})
+		client.propagated = append(client.propagated, propagated...)
+		client.propagated = append(client.propagated, Command{Name: EXEC, DB: propagated[len(propagated)-1].DB})
+	} else {
+		client.propagated = append(client.propagated, propagated...)
+	}
+
+	return replies, nil
+}
+
+// execQueued runs a queued command, returns its reply along with the commands
+// to log for it
+func (h *Handler) execQueued(client *Client, command Command) ([]byte, []Command) {
+	spec, _ := h.ResolveCommand(command.Name)
+	reply, err := spec.Handler(client, command.Args...)
+
+	if err != nil {
+		client.TakePropagated()
+		reply, _ = client.Serialize(resp.ERROR, err.Error())
+		return reply, nil
+	}
+
+	// a transaction never blocks, blocking commands which can not be served
+	// reply as if their timeout elapsed
+	if blockRequest := client.TakeBlockRequest(); blockRequest != nil {
+		client.TakePropagated()
+		return blockRequest.TimeoutReply, nil
+	}
+
+	commands := client.TakePropagated()
+
+	if len(commands) == 0 && spec.LoggedAsCalled() {
+		commands = []Command{{Name: command.Name, Args: command.Args, DB: client.DB}}
+	}
+
+	return reply, commands
+}
+
+// Discard drops the queued commands
+// DISCARD
+func (h *Handler) Discard(client *Client, args ...any) ([]byte, error) {
+	if !client.InTransaction() {
+		return nil, errors.New("ERR DISCARD without MULTI")
+	}
+
+	client.transaction = nil
+	h.unwatch(client)
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// Watch makes the next EXEC fail when one of the keys is modified before it runs
+// WATCH key [key ...]
+func (h *Handler) Watch(client *Client, args ...any) ([]byte, error) {
+	if client.InTransaction() {
+		return nil, errors.New("ERR WATCH inside MULTI is not allowed")
+	}
+
+	if client.watch == nil {
+		client.watch = data.NewWatch()
+	}
+
+	h.db(client).Watch(client.watch, stringArgs(args)...)
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+// Unwatch forgets the watched keys
+// UNWATCH
+func (h *Handler) Unwatch(client *Client, args ...any) ([]byte, error) {
+	h.unwatch(client)
+
+	data, err := client.Serialize(resp.SIMPLE_STRING, "OK")
+	return data, err
+}
+
+func (h *Handler) unwatch(client *Client) {
+	if client.watch == nil {
+		return
+	}
+
+	client.watch.Unwatch()
+	client.watch = nil
+}
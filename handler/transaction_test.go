@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// exec queues the commands in a transaction and runs it
+func exec(h *Handler, client *Client, commands ...[]any) (any, error) {
+	call(h, client, "MULTI")
+
+	for _, command := range commands {
+		client.Queue(command[0].(string), command[1:])
+	}
+
+	return call(h, client, "EXEC")
+}
+
+func TestExecPropagatesEffectsAsTransaction(t *testing.T) {
+	h, client := newTestHandler()
+
+	reply, _ := exec(h, client, []any{SET, "a", "1"}, []any{GET, "a"}, []any{INCR, "a"})
+	assert.Equal(t, []any{"OK", "1", 2}, reply)
+
+	assert.Equal(t, []Command{
+		{Name: MULTI},
+		{Name: SET, Args: []any{"a", "1"}},
+		{Name: INCR, Args: []any{"a"}},
+		{Name: EXEC},
+	}, client.TakePropagated())
+
+	// a single effect is logged alone
+	exec(h, client, []any{GET, "a"}, []any{DEL, "a"})
+	assert.Equal(t, []Command{{Name: DEL, Args: []any{"a"}}}, client.TakePropagated())
+
+	exec(h, client, []any{GET, "a"})
+	assert.Empty(t, client.TakePropagated())
+}
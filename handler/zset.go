@@ -0,0 +1,737 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// range kinds of ZRANGE
+const (
+	ZRANGE_BY_RANK = iota
+	ZRANGE_BY_SCORE
+	ZRANGE_BY_LEX
+)
+
+var (
+	errNotFloat      = errors.New("ERR value is not a valid float")
+	errMinMaxFloat   = errors.New("ERR min or max is not a float")
+	errMinMaxLex     = errors.New("ERR min or max not valid string range item")
+	errNotInteger    = errors.New("ERR value is not an integer or out of range")
+	errSyntax        = errors.New("ERR syntax error")
+	errWeightFloat   = errors.New("ERR weight value is not a float")
+	errZAddXXNX      = errors.New("ERR XX and NX options at the same time are not compatible")
+	errZAddGTLTNX    = errors.New("ERR GT, LT, and/or NX options at the same time are not compatible")
+	errZAddIncrPairs = errors.New("ERR INCR option supports a single increment-element pair")
+)
+
+// ZAdd adds members with their scores
+// ZADD key [NX | XX] [GT | LT] [CH] [INCR] score member [score member ...]
+func (h *Handler) ZAdd(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	flags := 0
+	changed := false
+	i := 1
+
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i].(string)) {
+		case "NX":
+			flags |= zset.ADD_NX
+		case "XX":
+			flags |= zset.ADD_XX
+		case "GT":
+			flags |= zset.ADD_GT
+		case "LT":
+			flags |= zset.ADD_LT
+		case "CH":
+			changed = true
+		case "INCR":
+			flags |= zset.ADD_INCR
+		default:
+			break options
+		}
+	}
+
+	pairs := args[i:]
+
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, errSyntax
+	}
+
+	if flags&zset.ADD_NX != 0 && flags&zset.ADD_XX != 0 {
+		return nil, errZAddXXNX
+	}
+
+	if (flags&zset.ADD_GT != 0 && flags&zset.ADD_LT != 0) || (flags&zset.ADD_NX != 0 && flags&(zset.ADD_GT|zset.ADD_LT) != 0) {
+		return nil, errZAddGTLTNX
+	}
+
+	if flags&zset.ADD_INCR != 0 && len(pairs) != 2 {
+		return nil, errZAddIncrPairs
+	}
+
+	entries := []zset.Entry{}
+
+	for j := 0; j < len(pairs); j += 2 {
+		score, err := parseScore(pairs[j].(string))
+
+		if err != nil {
+			return nil, errNotFloat
+		}
+
+		entries = append(entries, zset.Entry{Member: pairs[j+1].(string), Score: score})
+	}
+
+	if flags&zset.ADD_INCR != 0 {
+		score, err := h.db(client).ZIncrBy(key, entries[0].Member, entries[0].Score, flags&^zset.ADD_INCR)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return serializeScore(client, score)
+	}
+
+	added, updated, err := h.db(client).ZAdd(key, entries, flags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		added += updated
+	}
+
+	data, err := client.Serialize(resp.INTEGER, added)
+
+	return data, err
+}
+
+func (h *Handler) ZIncrBy(client *Client, args ...any) ([]byte, error) {
+	increment, err := parseScore(args[1].(string))
+
+	if err != nil {
+		return nil, errNotFloat
+	}
+
+	score, err := h.db(client).ZIncrBy(args[0].(string), args[2].(string), increment, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return serializeScore(client, score)
+}
+
+func (h *Handler) ZRem(client *Client, args ...any) ([]byte, error) {
+	removed, err := h.db(client).ZRem(args[0].(string), stringArgs(args[1:])...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, removed)
+
+	return data, err
+}
+
+func (h *Handler) ZScore(client *Client, args ...any) ([]byte, error) {
+	score, err := h.db(client).ZScore(args[0].(string), args[1].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return serializeScore(client, score)
+}
+
+func (h *Handler) ZMScore(client *Client, args ...any) ([]byte, error) {
+	key := args[0].(string)
+	reply := []resp.ArrayType{}
+
+	for _, member := range stringArgs(args[1:]) {
+		score, err := h.db(client).ZScore(key, member)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reply = append(reply, scoreItem(score))
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
+
+func (h *Handler) ZCard(client *Client, args ...any) ([]byte, error) {
+	length, err := h.db(client).ZCard(args[0].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, length)
+
+	return data, err
+}
+
+// ZRank replies with the rank of the member, ordered from the lowest score
+// ZRANK key member [WITHSCORE]
+func (h *Handler) ZRank(client *Client, args ...any) ([]byte, error) {
+	return h.rank(client, "zrank", false, args...)
+}
+
+// ZRevRank replies with the rank of the member, ordered from the highest score
+// ZREVRANK key member [WITHSCORE]
+func (h *Handler) ZRevRank(client *Client, args ...any) ([]byte, error) {
+	return h.rank(client, "zrevrank", true, args...)
+}
+
+func (h *Handler) rank(client *Client, command string, reverse bool, args ...any) ([]byte, error) {
+	if len(args) > 3 {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	withScore := len(args) == 3
+
+	if withScore && strings.ToUpper(args[2].(string)) != "WITHSCORE" {
+		return nil, errSyntax
+	}
+
+	entry, rank, err := h.db(client).ZRank(args[0].(string), args[1].(string), reverse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		if withScore {
+			return client.Serialize(resp.ARRAY, nil)
+		}
+
+		return client.Serialize(resp.BULK_STRING, nil)
+	}
+
+	if !withScore {
+		return client.Serialize(resp.INTEGER, rank)
+	}
+
+	reply := []resp.ArrayType{
+		{Value: rank, Type: resp.INTEGER},
+		scoreItem(entry.Score),
+	}
+
+	data, err := client.Serialize(resp.ARRAY, reply)
+
+	return data, err
+}
+
+// zrangeRequest holds the parsed arguments of the ZRANGE command family
+type zrangeRequest struct {
+	key        string
+	min        string
+	max        string
+	by         int
+	reverse    bool
+	offset     int
+	count      int
+	limit      bool
+	withScores bool
+}
+
+// ZRange replies with the members within a range of ranks, scores or members
+// ZRANGE key start stop [BYSCORE | BYLEX] [REV] [LIMIT offset count] [WITHSCORES]
+func (h *Handler) ZRange(client *Client, args ...any) ([]byte, error) {
+	return h.zrange(client, args)
+}
+
+// ZRevRange is the deprecated form of ZRANGE ... REV
+func (h *Handler) ZRevRange(client *Client, args ...any) ([]byte, error) {
+	return h.zrangeLegacy(client, args, "REV")
+}
+
+// ZRangeByScore is the deprecated form of ZRANGE ... BYSCORE
+func (h *Handler) ZRangeByScore(client *Client, args ...any) ([]byte, error) {
+	return h.zrangeLegacy(client, args, "BYSCORE")
+}
+
+// ZRevRangeByScore is the deprecated form of ZRANGE ... BYSCORE REV
+func (h *Handler) ZRevRangeByScore(client *Client, args ...any) ([]byte, error) {
+	return h.zrangeLegacy(client, args, "BYSCORE", "REV")
+}
+
+// ZRangeByLex is the deprecated form of ZRANGE ... BYLEX
+func (h *Handler) ZRangeByLex(client *Client, args ...any) ([]byte, error) {
+	return h.zrangeLegacy(client, args, "BYLEX")
+}
+
+// ZRevRangeByLex is the deprecated form of ZRANGE ... BYLEX REV
+func (h *Handler) ZRevRangeByLex(client *Client, args ...any) ([]byte, error) {
+	return h.zrangeLegacy(client, args, "BYLEX", "REV")
+}
+
+// zrangeLegacy rewrites the deprecated range commands into their ZRANGE form.
+// Their arguments are already given in the order ZRANGE expects with REV.
+func (h *Handler) zrangeLegacy(client *Client, args []any, options ...any) ([]byte, error) {
+	rewritten := append([]any{}, args[:3]...)
+	rewritten = append(rewritten, options...)
+	rewritten = append(rewritten, args[3:]...)
+
+	return h.zrange(client, rewritten)
+}
+
+func (h *Handler) zrange(client *Client, args []any) ([]byte, error) {
+	request, err := parseZRangeRequest(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := h.zrangeEntries(client, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.ARRAY, entriesReply(client, entries, request.withScores))
+
+	return data, err
+}
+
+// ZRangeStore stores the members within a range in destination
+// ZRANGESTORE dst src min max [BYSCORE | BYLEX] [REV] [LIMIT offset count]
+func (h *Handler) ZRangeStore(client *Client, args ...any) ([]byte, error) {
+	request, err := parseZRangeRequest(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if request.withScores {
+		return nil, errSyntax
+	}
+
+	entries, err := h.zrangeEntries(client, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := h.db(client).ZStore(args[0].(string), entries)
+
+	data, err := client.Serialize(resp.INTEGER, stored)
+
+	return data, err
+}
+
+func parseZRangeRequest(args []any) (zrangeRequest, error) {
+	request := zrangeRequest{
+		key:   args[0].(string),
+		min:   args[1].(string),
+		max:   args[2].(string),
+		by:    ZRANGE_BY_RANK,
+		count: -1,
+	}
+
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i].(string)) {
+		case "BYSCORE":
+			request.by = ZRANGE_BY_SCORE
+		case "BYLEX":
+			request.by = ZRANGE_BY_LEX
+		case "REV":
+			request.reverse = true
+		case "WITHSCORES":
+			request.withScores = true
+		case "LIMIT":
+			if i+2 >= len(args) {
+				return request, errSyntax
+			}
+
+			offset, err := strconv.Atoi(args[i+1].(string))
+
+			if err != nil {
+				return request, errNotInteger
+			}
+
+			count, err := strconv.Atoi(args[i+2].(string))
+
+			if err != nil {
+				return request, errNotInteger
+			}
+
+			request.offset, request.count, request.limit = offset, count, true
+			i += 2
+		default:
+			return request, errSyntax
+		}
+	}
+
+	if request.limit && request.by == ZRANGE_BY_RANK {
+		return request, errors.New("ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	}
+
+	if request.withScores && request.by == ZRANGE_BY_LEX {
+		return request, errors.New("ERR syntax error, WITHSCORES not supported in combination with BYLEX")
+	}
+
+	// with REV the range is given from the highest end
+	if request.reverse && request.by != ZRANGE_BY_RANK {
+		request.min, request.max = request.max, request.min
+	}
+
+	return request, nil
+}
+
+func (h *Handler) zrangeEntries(client *Client, request zrangeRequest) ([]zset.Entry, error) {
+	switch request.by {
+	case ZRANGE_BY_SCORE:
+		scoreRange, err := parseScoreRange(request.min, request.max)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return h.db(client).ZRangeByScore(request.key, scoreRange, request.reverse, request.offset, request.count)
+
+	case ZRANGE_BY_LEX:
+		lexRange, err := parseLexRange(request.min, request.max)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return h.db(client).ZRangeByLex(request.key, lexRange, request.reverse, request.offset, request.count)
+	}
+
+	start, err := strconv.Atoi(request.min)
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	stop, err := strconv.Atoi(request.max)
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	return h.db(client).ZRangeByRank(request.key, start, stop, request.reverse)
+}
+
+// ZCount replies with the number of members within the score range
+// ZCOUNT key min max
+func (h *Handler) ZCount(client *Client, args ...any) ([]byte, error) {
+	scoreRange, err := parseScoreRange(args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := h.db(client).ZCount(args[0].(string), scoreRange)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, count)
+
+	return data, err
+}
+
+// ZLexCount replies with the number of members within the lexicographical range
+// ZLEXCOUNT key min max
+func (h *Handler) ZLexCount(client *Client, args ...any) ([]byte, error) {
+	lexRange, err := parseLexRange(args[1].(string), args[2].(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := h.db(client).ZLexCount(args[0].(string), lexRange)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Serialize(resp.INTEGER, count)
+
+	return data, err
+}
+
+// ZPopMin removes the members with the lowest scores
+// ZPOPMIN key [count]
+func (h *Handler) ZPopMin(client *Client, args ...any) ([]byte, error) {
+	return h.pop(client, "zpopmin", false, args...)
+}
+
+// ZPopMax removes the members with the highest scores
+// ZPOPMAX key [count]
+func (h *Handler) ZPopMax(client *Client, args ...any) ([]byte, error) {
+	return h.pop(client, "zpopmax", true, args...)
+}
+
+func (h *Handler) pop(client *Client, command string, reverse bool, args ...any) ([]byte, error) {
+	if len(args) > 2 {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	count := 1
+
+	if len(args) == 2 {
+		var err error
+		count, err = strconv.Atoi(args[1].(string))
+
+		if err != nil || count < 0 {
+			return nil, errors.New("ERR value is out of range, must be positive")
+		}
+	}
+
+	entries, err := h.db(client).ZPop(args[0].(string), count, reverse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a single pop is a flat member score pair, even for resp3 clients
+	if len(args) == 1 {
+		reply := []resp.ArrayType{}
+
+		for _, entry := range entries {
+			reply = append(reply, resp.ArrayType{Value: entry.Member, Type: resp.BULK_STRING}, scoreItem(entry.Score))
+		}
+
+		return client.Serialize(resp.ARRAY, reply)
+	}
+
+	data, err := client.Serialize(resp.ARRAY, entriesReply(client, entries, true))
+
+	return data, err
+}
+
+// ZUnionStore stores the union of the sorted sets in destination
+// ZUNIONSTORE destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM | MIN | MAX]
+func (h *Handler) ZUnionStore(client *Client, args ...any) ([]byte, error) {
+	return h.combineStore(client, "zunionstore", true, args...)
+}
+
+// ZInterStore stores the intersection of the sorted sets in destination
+// ZINTERSTORE destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM | MIN | MAX]
+func (h *Handler) ZInterStore(client *Client, args ...any) ([]byte, error) {
+	return h.combineStore(client, "zinterstore", false, args...)
+}
+
+func (h *Handler) combineStore(client *Client, command string, union bool, args ...any) ([]byte, error) {
+	numKeys, err := strconv.Atoi(args[1].(string))
+
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	if numKeys < 1 {
+		return nil, fmt.Errorf("ERR at least 1 input key is needed for '%s' command", command)
+	}
+
+	if numKeys > len(args)-2 {
+		return nil, errSyntax
+	}
+
+	keys := stringArgs(args[2 : numKeys+2])
+	weights := make([]float64, numKeys)
+	aggregate := data.AGGREGATE_SUM
+
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	options := args[numKeys+2:]
+
+	for i := 0; i < len(options); i++ {
+		switch strings.ToUpper(options[i].(string)) {
+		case "WEIGHTS":
+			if i+numKeys >= len(options) {
+				return nil, errSyntax
+			}
+
+			for j := 0; j < numKeys; j++ {
+				weight, err := parseScore(options[i+1+j].(string))
+
+				if err != nil {
+					return nil, errWeightFloat
+				}
+
+				weights[j] = weight
+			}
+
+			i += numKeys
+
+		case "AGGREGATE":
+			if i+1 >= len(options) {
+				return nil, errSyntax
+			}
+
+			aggregate = strings.ToUpper(options[i+1].(string))
+
+			if aggregate != data.AGGREGATE_SUM && aggregate != data.AGGREGATE_MIN && aggregate != data.AGGREGATE_MAX {
+				return nil, errSyntax
+			}
+
+			i++
+
+		default:
+			return nil, errSyntax
+		}
+	}
+
+	entries, err := h.db(client).ZCombine(keys, weights, aggregate, union)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := h.db(client).ZStore(args[0].(string), entries)
+
+	return client.Serialize(resp.INTEGER, stored)
+}
+
+// ZScan iterates the members of a sorted set along with their scores
+// ZSCAN key cursor [MATCH pattern] [COUNT count]
+func (h *Handler) ZScan(client *Client, args ...any) ([]byte, error) {
+	options, err := parseScanOptions(args[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, cursor, err := h.db(client).ZScan(args[0].(string), options.cursor, options.pattern, options.count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []string{}
+
+	for _, entry := range entries {
+		items = append(items, entry.Member, resp.FormatDouble(entry.Score))
+	}
+
+	return scanReply(client, cursor, items)
+}
+
+// parseScore parses a score, accepting the inf forms but not NaN
+func parseScore(value string) (float64, error) {
+	score, err := strconv.ParseFloat(value, 64)
+
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, err
+	}
+
+	if math.IsNaN(score) {
+		return 0, errNotFloat
+	}
+
+	return score, nil
+}
+
+// parseScoreRange parses score bounds where a leading "(" makes the bound exclusive
+func parseScoreRange(min string, max string) (zset.ScoreRange, error) {
+	scoreRange := zset.ScoreRange{}
+	var err error
+
+	if scoreRange.Min, scoreRange.MinExclusive, err = parseScoreBound(min); err != nil {
+		return scoreRange, err
+	}
+
+	if scoreRange.Max, scoreRange.MaxExclusive, err = parseScoreBound(max); err != nil {
+		return scoreRange, err
+	}
+
+	return scoreRange, nil
+}
+
+func parseScoreBound(bound string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	score, err := parseScore(strings.TrimPrefix(bound, "("))
+
+	if err != nil {
+		return 0, false, errMinMaxFloat
+	}
+
+	return score, exclusive, nil
+}
+
+// parseLexRange parses lexicographical bounds, each being "-", "+", or a
+// value prefixed by "[" when inclusive and "(" when exclusive
+func parseLexRange(min string, max string) (zset.LexRange, error) {
+	lexRange := zset.LexRange{}
+	var err error
+
+	if lexRange.Min, err = parseLexBound(min); err != nil {
+		return lexRange, err
+	}
+
+	if lexRange.Max, err = parseLexBound(max); err != nil {
+		return lexRange, err
+	}
+
+	return lexRange, nil
+}
+
+func parseLexBound(bound string) (zset.LexBound, error) {
+	switch {
+	case bound == "-":
+		return zset.LexBound{Infinity: -1}, nil
+	case bound == "+":
+		return zset.LexBound{Infinity: 1}, nil
+	case strings.HasPrefix(bound, "["):
+		return zset.LexBound{Value: bound[1:]}, nil
+	case strings.HasPrefix(bound, "("):
+		return zset.LexBound{Value: bound[1:], Exclusive: true}, nil
+	}
+
+	return zset.LexBound{}, errMinMaxLex
+}
+
+// scoreItem builds a double reply item, nil scores become null replies
+func scoreItem(score interface{}) resp.ArrayType {
+	if score == nil {
+		return resp.ArrayType{Value: nil, Type: resp.BULK_STRING}
+	}
+
+	return resp.ArrayType{Value: score, Type: resp.DOUBLE}
+}
+
+func serializeScore(client *Client, score interface{}) ([]byte, error) {
+	item := scoreItem(score)
+	return client.Serialize(item.Type.(string), item.Value)
+}
+
+// entriesReply builds the members, followed by their score when withScores is
+// set. resp3 clients get member score pairs.
+func entriesReply(client *Client, entries []zset.Entry, withScores bool) []resp.ArrayType {
+	reply := []resp.ArrayType{}
+
+	for _, entry := range entries {
+		member := resp.ArrayType{Value: entry.Member, Type: resp.BULK_STRING}
+
+		switch {
+		case !withScores:
+			reply = append(reply, member)
+		case client.Protocol == resp.RESP3:
+			pair := []resp.ArrayType{member, scoreItem(entry.Score)}
+			reply = append(reply, resp.ArrayType{Value: pair, Type: resp.ARRAY})
+		default:
+			reply = append(reply, member, scoreItem(entry.Score))
+		}
+	}
+
+	return reply
+}
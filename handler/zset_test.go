@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZAddOptions(t *testing.T) {
+	h, client := newTestHandler()
+
+	reply, _ := call(h, client, "ZADD", "zset", "1", "a", "2", "b")
+	assert.Equal(t, 2, reply)
+
+	cases := []struct {
+		args  []any
+		reply any
+	}{
+		{[]any{"NX", "5", "a", "3", "c"}, 1},
+		{[]any{"XX", "5", "a", "4", "d"}, 0},
+		{[]any{"XX", "CH", "6", "a", "4", "d"}, 1},
+		{[]any{"GT", "CH", "1", "a", "3", "b"}, 1},
+		{[]any{"LT", "CH", "10", "a", "1", "b"}, 1},
+		{[]any{"CH", "6", "a", "1", "b", "7", "e"}, 1},
+		{[]any{"INCR", "2", "a"}, "8"},
+		{[]any{"NX", "INCR", "2", "a"}, nil},
+		{[]any{"XX", "INCR", "2", "missing"}, nil},
+		{[]any{"GT", "INCR", "-1", "a"}, nil},
+		{[]any{"LT", "INCR", "-1", "a"}, "7"},
+	}
+
+	for _, c := range cases {
+		reply, err := call(h, client, "ZADD", append([]any{"zset"}, c.args...)...)
+		assert.NoError(t, err, c.args)
+		assert.Equal(t, c.reply, reply, c.args)
+	}
+
+	reply, _ = call(h, client, "ZRANGE", "zset", "0", "-1", "WITHSCORES")
+	assert.Equal(t, []any{"b", "1", "c", "3", "a", "7", "e", "7"}, reply)
+
+	errors := []struct {
+		args []any
+		err  string
+	}{
+		{[]any{"NX", "XX", "1", "a"}, "ERR XX and NX options at the same time are not compatible"},
+		{[]any{"GT", "LT", "1", "a"}, "ERR GT, LT, and/or NX options at the same time are not compatible"},
+		{[]any{"NX", "GT", "1", "a"}, "ERR GT, LT, and/or NX options at the same time are not compatible"},
+		{[]any{"INCR", "1", "a", "2", "b"}, "ERR INCR option supports a single increment-element pair"},
+		{[]any{"1", "a", "2"}, "ERR syntax error"},
+		{[]any{"x", "a"}, "ERR value is not a valid float"},
+		{[]any{"nan", "a"}, "ERR value is not a valid float"},
+	}
+
+	for _, c := range errors {
+		_, err := call(h, client, "ZADD", append([]any{"zset"}, c.args...)...)
+		assert.EqualError(t, err, c.err, c.args)
+	}
+}
+
+func TestZRangeBounds(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "ZADD", "zset", "1", "a", "2", "b", "3", "c", "4", "d", "-inf", "low", "+inf", "high")
+
+	cases := []struct {
+		args  []any
+		reply []any
+	}{
+		{[]any{"1", "3", "BYSCORE"}, []any{"a", "b", "c"}},
+		{[]any{"(1", "3", "BYSCORE"}, []any{"b", "c"}},
+		{[]any{"(1", "(3", "BYSCORE"}, []any{"b"}},
+		{[]any{"-inf", "+inf", "BYSCORE", "LIMIT", "1", "2"}, []any{"a", "b"}},
+		{[]any{"-inf", "+inf", "BYSCORE", "LIMIT", "5", "-1"}, []any{"high"}},
+		{[]any{"(-inf", "(+inf", "BYSCORE"}, []any{"a", "b", "c", "d"}},
+		{[]any{"+inf", "3", "BYSCORE", "REV"}, []any{"high", "d", "c"}},
+		{[]any{"(4", "(1", "BYSCORE", "REV", "LIMIT", "0", "1"}, []any{"c"}},
+		{[]any{"3", "1", "BYSCORE"}, []any{}},
+		{[]any{"1", "2", "BYSCORE", "WITHSCORES"}, []any{"a", "1", "b", "2"}},
+		{[]any{"0", "1", "REV"}, []any{"high", "d"}},
+		{[]any{"-2", "-1"}, []any{"d", "high"}},
+		{[]any{"5", "10"}, []any{"high"}},
+	}
+
+	for _, c := range cases {
+		reply, err := call(h, client, "ZRANGE", append([]any{"zset"}, c.args...)...)
+		assert.NoError(t, err, c.args)
+		assert.Equal(t, c.reply, reply, c.args)
+	}
+
+	reply, _ := call(h, client, "ZRANGEBYSCORE", "zset", "(1", "+inf", "LIMIT", "0", "2")
+	assert.Equal(t, []any{"b", "c"}, reply)
+
+	reply, _ = call(h, client, "ZREVRANGEBYSCORE", "zset", "4", "(2", "WITHSCORES")
+	assert.Equal(t, []any{"d", "4", "c", "3"}, reply)
+
+	reply, _ = call(h, client, "ZCOUNT", "zset", "(1", "+inf")
+	assert.Equal(t, 4, reply)
+}
+
+func TestZRangeByLex(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "ZADD", "zset", "0", "a", "0", "b", "0", "c", "0", "d", "0", "e")
+
+	cases := []struct {
+		args  []any
+		reply []any
+	}{
+		{[]any{"-", "+", "BYLEX"}, []any{"a", "b", "c", "d", "e"}},
+		{[]any{"[b", "(d", "BYLEX"}, []any{"b", "c"}},
+		{[]any{"(b", "[d", "BYLEX"}, []any{"c", "d"}},
+		{[]any{"-", "+", "BYLEX", "LIMIT", "1", "2"}, []any{"b", "c"}},
+		{[]any{"+", "[c", "BYLEX", "REV"}, []any{"e", "d", "c"}},
+		{[]any{"(d", "-", "BYLEX", "REV", "LIMIT", "1", "5"}, []any{"b", "a"}},
+		{[]any{"[d", "[b", "BYLEX"}, []any{}},
+	}
+
+	for _, c := range cases {
+		reply, err := call(h, client, "ZRANGE", append([]any{"zset"}, c.args...)...)
+		assert.NoError(t, err, c.args)
+		assert.Equal(t, c.reply, reply, c.args)
+	}
+
+	reply, _ := call(h, client, "ZRANGEBYLEX", "zset", "[b", "+", "LIMIT", "0", "2")
+	assert.Equal(t, []any{"b", "c"}, reply)
+
+	reply, _ = call(h, client, "ZREVRANGEBYLEX", "zset", "(c", "-")
+	assert.Equal(t, []any{"b", "a"}, reply)
+
+	reply, _ = call(h, client, "ZLEXCOUNT", "zset", "(a", "[c")
+	assert.Equal(t, 2, reply)
+
+	errors := []struct {
+		args []any
+		err  string
+	}{
+		{[]any{"a", "+", "BYLEX"}, "ERR min or max not valid string range item"},
+		{[]any{"-", "+", "BYLEX", "WITHSCORES"}, "ERR syntax error, WITHSCORES not supported in combination with BYLEX"},
+		{[]any{"0", "1", "LIMIT", "0", "1"}, "ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX"},
+		{[]any{"x", "1", "BYSCORE"}, "ERR min or max is not a float"},
+		{[]any{"x", "1"}, "ERR value is not an integer or out of range"},
+	}
+
+	for _, c := range errors {
+		_, err := call(h, client, "ZRANGE", append([]any{"zset"}, c.args...)...)
+		assert.EqualError(t, err, c.err, c.args)
+	}
+}
+
+func TestZStoreWeightsAndAggregate(t *testing.T) {
+	h, client := newTestHandler()
+	call(h, client, "ZADD", "a", "1", "x", "2", "y")
+	call(h, client, "ZADD", "b", "10", "y", "20", "z")
+	call(h, client, "SADD", "set", "x", "z")
+
+	cases := []struct {
+		command string
+		args    []any
+		count   int
+		scores  []any
+	}{
+		{"ZUNIONSTORE", []any{"2", "a", "b"}, 3, []any{"x", "1", "y", "12", "z", "20"}},
+		{"ZUNIONSTORE", []any{"2", "a", "b", "WEIGHTS", "2", "0.5"}, 3, []any{"x", "2", "y", "9", "z", "10"}},
+		{"ZUNIONSTORE", []any{"2", "a", "b", "AGGREGATE", "MIN"}, 3, []any{"x", "1", "y", "2", "z", "20"}},
+		{"ZUNIONSTORE", []any{"2", "a", "b", "WEIGHTS", "1", "-1", "AGGREGATE", "MAX"}, 3, []any{"z", "-20", "x", "1", "y", "2"}},
+		{"ZINTERSTORE", []any{"2", "a", "b"}, 1, []any{"y", "12"}},
+		{"ZINTERSTORE", []any{"2", "a", "b", "AGGREGATE", "MAX"}, 1, []any{"y", "10"}},
+		{"ZINTERSTORE", []any{"2", "a", "b", "WEIGHTS", "3", "1", "AGGREGATE", "SUM"}, 1, []any{"y", "16"}},
+		// members of plain sets count with a score of one
+		{"ZINTERSTORE", []any{"2", "a", "set", "WEIGHTS", "1", "5"}, 1, []any{"x", "6"}},
+		{"ZINTERSTORE", []any{"2", "a", "missing"}, 0, []any{}},
+	}
+
+	for _, c := range cases {
+		reply, err := call(h, client, c.command, append([]any{"dest"}, c.args...)...)
+		assert.NoError(t, err, c.args)
+		assert.Equal(t, c.count, reply, c.args)
+
+		reply, _ = call(h, client, "ZRANGE", "dest", "0", "-1", "WITHSCORES")
+		assert.Equal(t, c.scores, reply, c.args)
+	}
+
+	errors := []struct {
+		args []any
+		err  string
+	}{
+		{[]any{"2", "a", "b", "WEIGHTS", "1"}, "ERR syntax error"},
+		{[]any{"2", "a", "b", "WEIGHTS", "1", "x"}, "ERR weight value is not a float"},
+		{[]any{"2", "a", "b", "AGGREGATE", "AVG"}, "ERR syntax error"},
+		{[]any{"0", "a"}, "ERR at least 1 input key is needed for 'zunionstore' command"},
+		{[]any{"3", "a", "b"}, "ERR syntax error"},
+	}
+
+	for _, c := range errors {
+		_, err := call(h, client, "ZUNIONSTORE", append([]any{"dest"}, c.args...)...)
+		assert.EqualError(t, err, c.err, c.args)
+	}
+}
+
+func TestZScoreFormatting(t *testing.T) {
+	h, client := newTestHandler()
+
+	cases := []struct {
+		score     string
+		formatted string
+	}{
+		{"1", "1"},
+		{"1.5", "1.5"},
+		{"-0.25", "-0.25"},
+		{"3.0", "3"},
+		{"1e20", "1e+20"},
+		{"0.1", "0.1"},
+		{"1e-7", "1e-07"},
+		{"+inf", "inf"},
+		{"-inf", "-inf"},
+	}
+
+	for _, c := range cases {
+		call(h, client, "ZADD", "zset", c.score, "member")
+
+		reply, _ := call(h, client, "ZSCORE", "zset", "member")
+		assert.Equal(t, c.formatted, reply, c.score)
+	}
+
+	call(h, client, "ZADD", "sum", "0.1", "member")
+	reply, _ := call(h, client, "ZINCRBY", "sum", "0.2", "member")
+	assert.Equal(t, "0.30000000000000004", reply)
+
+	_, err := call(h, client, "ZINCRBY", "zset", "+inf", "member")
+	assert.EqualError(t, err, "ERR resulting score is not a number (NaN)")
+}
@@ -0,0 +1,277 @@
+package pubsub
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data/scan"
+)
+
+// Message is a published message as received by a subscriber
+type Message struct {
+	// the matching pattern, empty for channel subscriptions
+	Pattern string
+	Channel string
+	Payload string
+}
+
+// Subscriber receives the messages published to its channels and patterns.
+// Deliver is called while the hub is locked, so it must not block.
+type Subscriber interface {
+	Deliver(message Message)
+}
+
+// PubSub keeps track of the channel and pattern subscriptions
+type PubSub struct {
+	channels map[string]map[Subscriber]struct{}
+	patterns map[string]map[Subscriber]struct{}
+	// channels and patterns of every subscriber, to answer counts and to
+	// unsubscribe from everything
+	subscriptions map[Subscriber]*subscriptions
+	lock          *sync.RWMutex
+}
+
+type subscriptions struct {
+	channels map[string]struct{}
+	patterns map[string]struct{}
+}
+
+func NewPubSub() *PubSub {
+	return &PubSub{
+		channels:      make(map[string]map[Subscriber]struct{}),
+		patterns:      make(map[string]map[Subscriber]struct{}),
+		subscriptions: make(map[Subscriber]*subscriptions),
+		lock:          &sync.RWMutex{},
+	}
+}
+
+// Subscribe subscribes to channel, returns the number of subscriptions of the subscriber
+func (p *PubSub) Subscribe(subscriber Subscriber, channel string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.subscriptionsOf(subscriber).channels[channel] = struct{}{}
+	add(p.channels, channel, subscriber)
+
+	return p.count(subscriber)
+}
+
+// PSubscribe subscribes to the channels matching pattern, returns the number
+// of subscriptions of the subscriber
+func (p *PubSub) PSubscribe(subscriber Subscriber, pattern string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.subscriptionsOf(subscriber).patterns[pattern] = struct{}{}
+	add(p.patterns, pattern, subscriber)
+
+	return p.count(subscriber)
+}
+
+// Unsubscribe unsubscribes from channel, returns the number of subscriptions left
+func (p *PubSub) Unsubscribe(subscriber Subscriber, channel string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if current, found := p.subscriptions[subscriber]; found {
+		delete(current.channels, channel)
+	}
+
+	remove(p.channels, channel, subscriber)
+
+	return p.forgetIfIdle(subscriber)
+}
+
+// PUnsubscribe unsubscribes from pattern, returns the number of subscriptions left
+func (p *PubSub) PUnsubscribe(subscriber Subscriber, pattern string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if current, found := p.subscriptions[subscriber]; found {
+		delete(current.patterns, pattern)
+	}
+
+	remove(p.patterns, pattern, subscriber)
+
+	return p.forgetIfIdle(subscriber)
+}
+
+// SubscribedChannels returns the channels of the subscriber, sorted
+func (p *PubSub) SubscribedChannels(subscriber Subscriber) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if current, found := p.subscriptions[subscriber]; found {
+		return sortedKeys(current.channels)
+	}
+
+	return []string{}
+}
+
+// SubscribedPatterns returns the patterns of the subscriber, sorted
+func (p *PubSub) SubscribedPatterns(subscriber Subscriber) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if current, found := p.subscriptions[subscriber]; found {
+		return sortedKeys(current.patterns)
+	}
+
+	return []string{}
+}
+
+// UnsubscribeAll removes every subscription of the subscriber, as done when it disconnects
+func (p *PubSub) UnsubscribeAll(subscriber Subscriber) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	current, found := p.subscriptions[subscriber]
+
+	if !found {
+		return
+	}
+
+	for channel := range current.channels {
+		remove(p.channels, channel, subscriber)
+	}
+
+	for pattern := range current.patterns {
+		remove(p.patterns, pattern, subscriber)
+	}
+
+	delete(p.subscriptions, subscriber)
+}
+
+// Publish delivers payload to the subscribers of channel and of the matching
+// patterns, returns the number of deliveries
+func (p *PubSub) Publish(channel string, payload string) int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	receivers := 0
+
+	for subscriber := range p.channels[channel] {
+		subscriber.Deliver(Message{Channel: channel, Payload: payload})
+		receivers++
+	}
+
+	for pattern, subscribers := range p.patterns {
+		if !scan.Match(pattern, channel) {
+			continue
+		}
+
+		for subscriber := range subscribers {
+			subscriber.Deliver(Message{Pattern: pattern, Channel: channel, Payload: payload})
+			receivers++
+		}
+	}
+
+	return receivers
+}
+
+// Channels returns the channels having subscribers, only those matching
+// pattern unless it is empty
+func (p *PubSub) Channels(pattern string) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	channels := []string{}
+
+	for channel := range p.channels {
+		if pattern == "" || scan.Match(pattern, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	sort.Strings(channels)
+	return channels
+}
+
+// NumSub returns the number of subscribers of channel, pattern subscribers excluded
+func (p *PubSub) NumSub(channel string) int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.channels[channel])
+}
+
+// NumPat returns the number of patterns having subscribers
+func (p *PubSub) NumPat() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.patterns)
+}
+
+func (p *PubSub) subscriptionsOf(subscriber Subscriber) *subscriptions {
+	current, found := p.subscriptions[subscriber]
+
+	if !found {
+		current = &subscriptions{
+			channels: make(map[string]struct{}),
+			patterns: make(map[string]struct{}),
+		}
+
+		p.subscriptions[subscriber] = current
+	}
+
+	return current
+}
+
+func (p *PubSub) count(subscriber Subscriber) int {
+	current, found := p.subscriptions[subscriber]
+
+	if !found {
+		return 0
+	}
+
+	return len(current.channels) + len(current.patterns)
+}
+
+// forgetIfIdle drops the bookkeeping of a subscriber without subscriptions,
+// returns its number of subscriptions
+func (p *PubSub) forgetIfIdle(subscriber Subscriber) int {
+	count := p.count(subscriber)
+
+	if count == 0 {
+		delete(p.subscriptions, subscriber)
+	}
+
+	return count
+}
+
+func add(index map[string]map[Subscriber]struct{}, name string, subscriber Subscriber) {
+	subscribers, found := index[name]
+
+	if !found {
+		subscribers = make(map[Subscriber]struct{})
+		index[name] = subscribers
+	}
+
+	subscribers[subscriber] = struct{}{}
+}
+
+func remove(index map[string]map[Subscriber]struct{}, name string, subscriber Subscriber) {
+	subscribers, found := index[name]
+
+	if !found {
+		return
+	}
+
+	delete(subscribers, subscriber)
+
+	if len(subscribers) == 0 {
+		delete(index, name)
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	messages []Message
+}
+
+func (r *recorder) Deliver(message Message) {
+	r.messages = append(r.messages, message)
+}
+
+func TestPublish(t *testing.T) {
+	hub := NewPubSub()
+	channel, pattern := &recorder{}, &recorder{}
+
+	assert.Equal(t, 1, hub.Subscribe(channel, "news"))
+	assert.Equal(t, 2, hub.Subscribe(channel, "sports"))
+	assert.Equal(t, 1, hub.PSubscribe(pattern, "n*"))
+
+	assert.Equal(t, 2, hub.Publish("news", "hello"))
+	assert.Equal(t, 0, hub.Publish("weather", "sunny"))
+
+	assert.Equal(t, []Message{{Channel: "news", Payload: "hello"}}, channel.messages)
+	assert.Equal(t, []Message{{Pattern: "n*", Channel: "news", Payload: "hello"}}, pattern.messages)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	hub := NewPubSub()
+	subscriber := &recorder{}
+
+	hub.Subscribe(subscriber, "a")
+	hub.Subscribe(subscriber, "b")
+	hub.PSubscribe(subscriber, "c*")
+
+	assert.Equal(t, []string{"a", "b"}, hub.Channels(""))
+	assert.Equal(t, 2, hub.Unsubscribe(subscriber, "a"))
+	assert.Equal(t, 2, hub.Unsubscribe(subscriber, "missing"))
+	assert.Equal(t, []string{"b"}, hub.SubscribedChannels(subscriber))
+
+	hub.UnsubscribeAll(subscriber)
+
+	assert.Equal(t, []string{}, hub.Channels(""))
+	assert.Equal(t, 0, hub.NumPat())
+	assert.Equal(t, 0, hub.NumSub("b"))
+	assert.Equal(t, 0, len(hub.subscriptions))
+}
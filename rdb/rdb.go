@@ -0,0 +1,671 @@
+package rdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"math"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/set"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+)
+
+// File layout
+//
+//	magic, version
+//	entries : [OPCODE_SELECTDB <db>] [OPCODE_EXPIRE_MS <int64>] <value type> <key> <value>
+//	OPCODE_EOF
+//	crc64 of everything before
+//
+// entries are grouped by database, each group starting with OPCODE_SELECTDB
+// unless it is database 0. Strings are written as an unsigned varint length followed by the bytes,
+// collections as an unsigned varint count followed by their elements. Sorted
+// set scores are written as the little endian bits of their float64 value.
+//
+// Streams are written as their entries, each an ID and its fields, followed
+// by their last ID, the number of entries ever added, the largest deleted ID
+// and their consumer groups. Each group is written as its name, last ID,
+// number of entries read, consumers and pending entries. IDs are written as
+// two unsigned varints, times and counters which may be -1 as signed varints.
+const (
+	MAGIC   string = "REDISLITE"
+	VERSION string = "0001"
+)
+
+// opcodes
+const (
+	OPCODE_EXPIRE_MS byte = 0xFC
+	OPCODE_SELECTDB  byte = 0xFE
+	OPCODE_EOF       byte = 0xFF
+)
+
+// value types
+const (
+	TYPE_STRING byte = 0
+	TYPE_LIST   byte = 1
+	TYPE_SET    byte = 2
+	TYPE_ZSET   byte = 3
+	TYPE_HASH   byte = 4
+	TYPE_STREAM byte = 5
+)
+
+var crcTable = crc64.MakeTable(crc64.ECMA)
+
+var ErrChecksum = errors.New("Snapshot checksum mismatch")
+
+type encoder struct {
+	writer  *bufio.Writer
+	scratch []byte
+	// database of the entries written last
+	db int
+}
+
+// Encode writes the entries to writer in the snapshot format
+func Encode(writer io.Writer, entries []data.Entry) error {
+	checksum := crc64.New(crcTable)
+
+	e := &encoder{
+		writer: bufio.NewWriter(io.MultiWriter(writer, checksum)),
+	}
+
+	e.writeRaw([]byte(MAGIC + VERSION))
+
+	for _, entry := range entries {
+		if err := e.writeEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	e.writeRaw([]byte{OPCODE_EOF})
+
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+
+	return binary.Write(writer, binary.LittleEndian, checksum.Sum64())
+}
+
+func (e *encoder) writeEntry(entry data.Entry) error {
+	if entry.DB != e.db {
+		e.writeRaw([]byte{OPCODE_SELECTDB})
+		e.writeLength(entry.DB)
+		e.db = entry.DB
+	}
+
+	if entry.ExpireAt > 0 {
+		e.writeRaw([]byte{OPCODE_EXPIRE_MS})
+		e.writeRaw(binary.LittleEndian.AppendUint64(nil, uint64(entry.ExpireAt)))
+	}
+
+	switch value := entry.Value.(type) {
+	case string:
+		e.writeRaw([]byte{TYPE_STRING})
+		e.writeString(entry.Key)
+		e.writeString(value)
+
+	case []byte:
+		e.writeRaw([]byte{TYPE_STRING})
+		e.writeString(entry.Key)
+		e.writeString(string(value))
+
+	case *list.List:
+		e.writeRaw([]byte{TYPE_LIST})
+		e.writeString(entry.Key)
+
+		items := value.GetValues()
+		e.writeLength(len(items))
+
+		for _, item := range items {
+			e.writeString(item.Value.(string))
+		}
+
+	case *set.Set:
+		e.writeRaw([]byte{TYPE_SET})
+		e.writeString(entry.Key)
+
+		members := value.Members()
+		e.writeLength(len(members))
+
+		for _, member := range members {
+			e.writeString(member)
+		}
+
+	case *zset.SortedSet:
+		e.writeRaw([]byte{TYPE_ZSET})
+		e.writeString(entry.Key)
+
+		entries := value.Entries()
+		e.writeLength(len(entries))
+
+		for _, member := range entries {
+			e.writeString(member.Member)
+			e.writeRaw(binary.LittleEndian.AppendUint64(nil, math.Float64bits(member.Score)))
+		}
+
+	case *hash.Hash:
+		e.writeRaw([]byte{TYPE_HASH})
+		e.writeString(entry.Key)
+
+		entries := value.Entries()
+		e.writeLength(len(entries))
+
+		for field, fieldValue := range entries {
+			e.writeString(field)
+			e.writeString(fieldValue)
+		}
+
+	case *stream.Stream:
+		e.writeRaw([]byte{TYPE_STREAM})
+		e.writeString(entry.Key)
+
+		return e.writeStream(value)
+
+	default:
+		return fmt.Errorf("Can not snapshot value of type %T", entry.Value)
+	}
+
+	return nil
+}
+
+func (e *encoder) writeStream(value *stream.Stream) error {
+	entries := value.Range(stream.MinID, stream.MaxID, 0, false)
+	e.writeLength(len(entries))
+
+	for _, current := range entries {
+		e.writeID(current.ID)
+		e.writeLength(len(current.Fields))
+
+		for _, field := range current.Fields {
+			e.writeString(field)
+		}
+	}
+
+	info := value.Info()
+	e.writeID(info.LastID)
+	e.writeLength(int(info.EntriesAdded))
+	e.writeID(info.MaxDeletedID)
+
+	groups := value.Groups()
+	e.writeLength(len(groups))
+
+	for _, group := range groups {
+		e.writeString(group.Name)
+		e.writeID(group.LastID)
+		e.writeVarint(group.EntriesRead)
+
+		consumers, err := value.Consumers(group.Name)
+
+		if err != nil {
+			return err
+		}
+
+		e.writeLength(len(consumers))
+
+		for _, consumer := range consumers {
+			e.writeString(consumer.Name)
+			e.writeVarint(consumer.SeenTime)
+			e.writeVarint(consumer.ActiveTime)
+		}
+
+		pending, err := value.Pending(group.Name, stream.PendingQuery{Start: stream.MinID, End: stream.MaxID})
+
+		if err != nil {
+			return err
+		}
+
+		e.writeLength(len(pending))
+
+		for _, entry := range pending {
+			e.writeID(entry.ID)
+			e.writeString(entry.Consumer)
+			e.writeVarint(entry.DeliveryTime)
+			e.writeVarint(entry.DeliveryCount)
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) writeRaw(data []byte) {
+	e.writer.Write(data)
+}
+
+func (e *encoder) writeLength(length int) {
+	e.scratch = binary.AppendUvarint(e.scratch[:0], uint64(length))
+	e.writeRaw(e.scratch)
+}
+
+func (e *encoder) writeVarint(value int64) {
+	e.scratch = binary.AppendVarint(e.scratch[:0], value)
+	e.writeRaw(e.scratch)
+}
+
+func (e *encoder) writeID(id stream.ID) {
+	e.scratch = binary.AppendUvarint(e.scratch[:0], id.Ms)
+	e.scratch = binary.AppendUvarint(e.scratch, id.Seq)
+	e.writeRaw(e.scratch)
+}
+
+func (e *encoder) writeString(value string) {
+	e.writeLength(len(value))
+	e.writer.WriteString(value)
+}
+
+type decoder struct {
+	reader *bytes.Reader
+	// database of the entries read next
+	db int
+}
+
+// Decode reads the entries of a snapshot, verifying its checksum
+func Decode(content []byte) ([]data.Entry, error) {
+	headerLength := len(MAGIC) + len(VERSION)
+
+	if len(content) < headerLength+1+8 || string(content[:len(MAGIC)]) != MAGIC {
+		return nil, errors.New("Not a snapshot file")
+	}
+
+	if string(content[len(MAGIC):headerLength]) != VERSION {
+		return nil, fmt.Errorf("Unsupported snapshot version %s", content[len(MAGIC):headerLength])
+	}
+
+	body := content[:len(content)-8]
+	expected := binary.LittleEndian.Uint64(content[len(content)-8:])
+
+	if crc64.Checksum(body, crcTable) != expected {
+		return nil, ErrChecksum
+	}
+
+	d := &decoder{reader: bytes.NewReader(body[headerLength:])}
+	entries := []data.Entry{}
+
+	for {
+		entry, done, err := d.readEntry()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if done {
+			return entries, nil
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+func (d *decoder) readEntry() (data.Entry, bool, error) {
+	entry := data.Entry{}
+
+	opcode, err := d.reader.ReadByte()
+
+	if err != nil {
+		return entry, false, err
+	}
+
+	if opcode == OPCODE_EOF {
+		return entry, true, nil
+	}
+
+	if opcode == OPCODE_SELECTDB {
+		db, err := binary.ReadUvarint(d.reader)
+
+		if err != nil {
+			return entry, false, err
+		}
+
+		d.db = int(db)
+
+		if opcode, err = d.reader.ReadByte(); err != nil {
+			return entry, false, err
+		}
+	}
+
+	entry.DB = d.db
+
+	if opcode == OPCODE_EXPIRE_MS {
+		var expireAt uint64
+
+		if err := binary.Read(d.reader, binary.LittleEndian, &expireAt); err != nil {
+			return entry, false, err
+		}
+
+		entry.ExpireAt = int64(expireAt)
+
+		if opcode, err = d.reader.ReadByte(); err != nil {
+			return entry, false, err
+		}
+	}
+
+	if entry.Key, err = d.readString(); err != nil {
+		return entry, false, err
+	}
+
+	switch opcode {
+	case TYPE_STRING:
+		entry.Value, err = d.readString()
+
+	case TYPE_LIST:
+		entry.Value, err = d.readList()
+
+	case TYPE_SET:
+		entry.Value, err = d.readSet()
+
+	case TYPE_ZSET:
+		entry.Value, err = d.readSortedSet()
+
+	case TYPE_HASH:
+		entry.Value, err = d.readHash()
+
+	case TYPE_STREAM:
+		entry.Value, err = d.readStream()
+
+	default:
+		err = fmt.Errorf("Unknown value type %d in snapshot", opcode)
+	}
+
+	return entry, false, err
+}
+
+func (d *decoder) readLength() (int, error) {
+	length, err := binary.ReadUvarint(d.reader)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if length > uint64(d.reader.Len()) {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return int(length), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return "", err
+	}
+
+	value := make([]byte, length)
+
+	if _, err := io.ReadFull(d.reader, value); err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+func (d *decoder) readList() (*list.List, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.NewList()
+
+	for i := 0; i < length; i++ {
+		item, err := d.readString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		items.InsertLast(item, resp.BULK_STRING)
+	}
+
+	return items, nil
+}
+
+func (d *decoder) readHash() (*hash.Hash, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := hash.NewHash()
+
+	for i := 0; i < length; i++ {
+		field, err := d.readString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.readString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		fields.Set(field, value)
+	}
+
+	return fields, nil
+}
+
+func (d *decoder) readSet() (*set.Set, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	members := set.NewSet()
+
+	for i := 0; i < length; i++ {
+		member, err := d.readString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		members.Add(member)
+	}
+
+	return members, nil
+}
+
+func (d *decoder) readSortedSet() (*zset.SortedSet, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	members := zset.NewSortedSet()
+
+	for i := 0; i < length; i++ {
+		member, err := d.readString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var score uint64
+
+		if err := binary.Read(d.reader, binary.LittleEndian, &score); err != nil {
+			return nil, err
+		}
+
+		members.Add(member, math.Float64frombits(score), 0)
+	}
+
+	return members, nil
+}
+
+func (d *decoder) readID() (stream.ID, error) {
+	ms, err := binary.ReadUvarint(d.reader)
+
+	if err != nil {
+		return stream.ID{}, err
+	}
+
+	seq, err := binary.ReadUvarint(d.reader)
+
+	return stream.ID{Ms: ms, Seq: seq}, err
+}
+
+func (d *decoder) readStream() (*stream.Stream, error) {
+	length, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	value := stream.NewStream()
+
+	for i := 0; i < length; i++ {
+		id, err := d.readID()
+
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := d.readLength()
+
+		if err != nil {
+			return nil, err
+		}
+
+		fields := make([]string, count)
+
+		for j := range fields {
+			if fields[j], err = d.readString(); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := value.Add(stream.NewID{ID: id}, fields, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	lastID, err := d.readID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	entriesAdded, err := binary.ReadUvarint(d.reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	maxDeletedID, err := d.readID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	value.SetID(lastID, int64(entriesAdded), maxDeletedID)
+
+	groups, err := d.readLength()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < groups; i++ {
+		if err := d.readGroup(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+func (d *decoder) readGroup(value *stream.Stream) error {
+	name, err := d.readString()
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := d.readID()
+
+	if err != nil {
+		return err
+	}
+
+	entriesRead, err := binary.ReadVarint(d.reader)
+
+	if err != nil {
+		return err
+	}
+
+	if err := value.CreateGroup(name, lastID, entriesRead); err != nil {
+		return err
+	}
+
+	consumers, err := d.readLength()
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < consumers; i++ {
+		consumer := stream.ConsumerInfo{}
+
+		if consumer.Name, err = d.readString(); err != nil {
+			return err
+		}
+
+		if consumer.SeenTime, err = binary.ReadVarint(d.reader); err != nil {
+			return err
+		}
+
+		if consumer.ActiveTime, err = binary.ReadVarint(d.reader); err != nil {
+			return err
+		}
+
+		if err := value.RestoreConsumer(name, consumer); err != nil {
+			return err
+		}
+	}
+
+	pending, err := d.readLength()
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < pending; i++ {
+		entry := stream.PendingEntry{}
+
+		if entry.ID, err = d.readID(); err != nil {
+			return err
+		}
+
+		if entry.Consumer, err = d.readString(); err != nil {
+			return err
+		}
+
+		if entry.DeliveryTime, err = binary.ReadVarint(d.reader); err != nil {
+			return err
+		}
+
+		if entry.DeliveryCount, err = binary.ReadVarint(d.reader); err != nil {
+			return err
+		}
+
+		if err := value.RestorePending(name, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
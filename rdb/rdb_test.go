@@ -0,0 +1,181 @@
+package rdb
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/hash"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/list"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/stream"
+	"github.com/iamvineettiwari/go-redis-server-lite/data/zset"
+	"github.com/iamvineettiwari/go-redis-server-lite/resp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	items := list.NewList()
+	items.InsertLast("a", resp.BULK_STRING)
+	items.InsertLast("b", resp.BULK_STRING)
+
+	fields := hash.NewHash()
+	fields.Set("field", "value")
+
+	scores := zset.NewSortedSet()
+	scores.Add("a", 1.5, 0)
+	scores.Add("b", -2, 0)
+
+	expireAt := time.Now().Add(time.Hour).UnixMilli()
+
+	entries := []data.Entry{
+		{Key: "string", Value: "value", ExpireAt: expireAt},
+		{Key: "list", Value: items},
+		{Key: "hash", Value: fields},
+		{Key: "zset", Value: scores},
+		{DB: 3, Key: "other", Value: "value"},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	if err := Encode(buffer, entries); err != nil {
+		log.Fatal(err)
+	}
+
+	decoded, err := Decode(buffer.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, len(decoded), 5)
+	assert.Equal(t, decoded[0], entries[0])
+	assert.Equal(t, decoded[1].Key, "list")
+	assert.Equal(t, decoded[1].Value.(*list.List).GetValues(), items.GetValues())
+	assert.Equal(t, decoded[2].Key, "hash")
+	assert.Equal(t, decoded[2].Value.(*hash.Hash).Entries(), fields.Entries())
+	assert.Equal(t, decoded[3].Key, "zset")
+	assert.Equal(t, decoded[3].Value.(*zset.SortedSet).Entries(), scores.Entries())
+	assert.Equal(t, decoded[4], entries[4])
+}
+
+func TestEncodeDecodeStream(t *testing.T) {
+	entries := stream.NewStream()
+
+	for _, id := range []uint64{1, 2, 3} {
+		entries.Add(stream.NewID{ID: stream.ID{Ms: id}}, []string{"field", "value"}, 0)
+	}
+
+	entries.Delete(stream.ID{Ms: 2})
+	entries.CreateGroup("group", stream.MinID, 0)
+	entries.ReadGroup("group", "consumer", stream.MinID, true, 1, false, 100)
+	entries.CreateConsumer("group", "idle", 200)
+
+	buffer := &bytes.Buffer{}
+
+	if err := Encode(buffer, []data.Entry{{Key: "stream", Value: entries}}); err != nil {
+		log.Fatal(err)
+	}
+
+	decoded, err := Decode(buffer.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	restored := decoded[0].Value.(*stream.Stream)
+
+	assert.Equal(t, entries.Range(stream.MinID, stream.MaxID, 0, false), restored.Range(stream.MinID, stream.MaxID, 0, false))
+	assert.Equal(t, entries.Info(), restored.Info())
+	assert.Equal(t, entries.Groups(), restored.Groups())
+
+	consumers, _ := restored.Consumers("group")
+	assert.Equal(t, []stream.ConsumerInfo{
+		{Name: "consumer", Pending: 1, SeenTime: 100, ActiveTime: 100},
+		{Name: "idle", Pending: 0, SeenTime: 200, ActiveTime: -1},
+	}, consumers)
+
+	pending, _ := restored.Pending("group", stream.PendingQuery{Start: stream.MinID, End: stream.MaxID})
+	assert.Equal(t, []stream.PendingEntry{{ID: stream.ID{Ms: 1}, Consumer: "consumer", DeliveryTime: 100, DeliveryCount: 1}}, pending)
+}
+
+func TestDecodeCorrupted(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	if err := Encode(buffer, []data.Entry{{Key: "key", Value: "value"}}); err != nil {
+		log.Fatal(err)
+	}
+
+	content := buffer.Bytes()
+	content[len(MAGIC)+len(VERSION)+3] ^= 0xFF
+
+	_, err := Decode(content)
+	assert.Equal(t, err, ErrChecksum)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dump.rdb")
+
+	store := data.NewStore()
+	store.Set("key", "value", "EX", 100)
+
+	other := data.NewStore()
+	other.Set("other", "value", "", 0)
+
+	snapshotter := NewSnapshotter(filename, []*data.Store{store, other}, nil)
+
+	if err := snapshotter.Save(); err != nil {
+		log.Fatal(err)
+	}
+
+	restored, restoredOther := data.NewStore(), data.NewStore()
+
+	if err := NewSnapshotter(filename, []*data.Store{restored, restoredOther}, nil).Load(); err != nil {
+		log.Fatal(err)
+	}
+
+	value, _, _ := restored.Get("key")
+
+	assert.Equal(t, value, "value")
+	assert.Equal(t, restored.ExpireTime("key"), store.ExpireTime("key"))
+	assert.False(t, restored.Exists("other"))
+	assert.True(t, restoredOther.Exists("other"))
+
+	// databases missing from the configuration can not be restored
+	assert.Error(t, NewSnapshotter(filename, []*data.Store{data.NewStore()}, nil).Load())
+}
+
+func TestCheckSaveRules(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dump.rdb")
+
+	store := data.NewStore()
+	store.Append("key", "value")
+
+	snapshotter := NewSnapshotter(filename, []*data.Store{store}, []SaveRule{{Seconds: 0, Changes: 2}})
+	assert.True(t, snapshotter.HasSaveRules())
+
+	snapshotter.AddChanges(1)
+	snapshotter.CheckSaveRules()
+	assert.NoFileExists(t, filename)
+
+	snapshotter.AddChanges(1)
+	snapshotter.CheckSaveRules()
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filename)
+		return err == nil && !snapshotter.saving.Load()
+	}, time.Second, 10*time.Millisecond)
+
+	restored := data.NewStore()
+
+	if err := NewSnapshotter(filename, []*data.Store{restored}, nil).Load(); err != nil {
+		log.Fatal(err)
+	}
+
+	// strings changed in place are saved like any other string
+	value, _, _ := restored.Get("key")
+	assert.Equal(t, "value", value)
+}
@@ -0,0 +1,236 @@
+package rdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+)
+
+var ErrSaveInProgress = errors.New("ERR Background save already in progress")
+
+// SaveRule triggers a background save once at least Changes writes happened
+// and Seconds passed since the last save
+type SaveRule struct {
+	Seconds int
+	Changes int
+}
+
+// ParseSaveRules parses the "<seconds> <changes> [<seconds> <changes> ...]"
+// format of the redis save config, an empty string disables automatic saves
+func ParseSaveRules(config string) ([]SaveRule, error) {
+	fields := strings.Fields(config)
+
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("Invalid save rules %q", config)
+	}
+
+	rules := []SaveRule{}
+
+	for i := 0; i < len(fields); i += 2 {
+		seconds, err := strconv.Atoi(fields[i])
+
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("Invalid save rules %q", config)
+		}
+
+		changes, err := strconv.Atoi(fields[i+1])
+
+		if err != nil || changes < 0 {
+			return nil, fmt.Errorf("Invalid save rules %q", config)
+		}
+
+		rules = append(rules, SaveRule{Seconds: seconds, Changes: changes})
+	}
+
+	return rules, nil
+}
+
+// Snapshotter saves point in time snapshots of the stores of every database to a file
+type Snapshotter struct {
+	filename string
+	stores   []*data.Store
+	rules    []SaveRule
+	// only one save writes the file at a time
+	saveLock *sync.Mutex
+	saving   atomic.Bool
+	// unix time in seconds of the last successful save
+	lastSave atomic.Int64
+	// writes since the last successful save
+	changes atomic.Int64
+}
+
+func NewSnapshotter(filename string, stores []*data.Store, rules []SaveRule) *Snapshotter {
+	snapshotter := &Snapshotter{
+		filename: filename,
+		stores:   stores,
+		rules:    rules,
+		saveLock: &sync.Mutex{},
+	}
+
+	snapshotter.lastSave.Store(time.Now().Unix())
+
+	return snapshotter
+}
+
+// Load restores the snapshot file into the stores, a missing file is not an error
+func (s *Snapshotter) Load() error {
+	content, err := os.ReadFile(s.filename)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := Decode(content)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.DB >= len(s.stores) {
+			return fmt.Errorf("Snapshot has keys in database %d, only %d databases are configured", entry.DB, len(s.stores))
+		}
+
+		s.stores[entry.DB].Restore(entry)
+	}
+
+	return nil
+}
+
+// Save writes a snapshot and returns once it is on disk
+func (s *Snapshotter) Save() error {
+	if s.saving.Load() {
+		return ErrSaveInProgress
+	}
+
+	entries := s.snapshot()
+	defer data.ReleaseSnapshot(entries)
+
+	return s.write(entries, s.changes.Load())
+}
+
+// BackgroundSave takes the snapshot right away and writes it in the background.
+// It must be called between two commands, like Save, for the snapshot to
+// never see the partial effects of one.
+func (s *Snapshotter) BackgroundSave() error {
+	if !s.saving.CompareAndSwap(false, true) {
+		return ErrSaveInProgress
+	}
+
+	entries := s.snapshot()
+	changes := s.changes.Load()
+
+	go func() {
+		defer s.saving.Store(false)
+		defer data.ReleaseSnapshot(entries)
+
+		if err := s.write(entries, changes); err != nil {
+			fmt.Println("Error while saving snapshot : ", err)
+		}
+	}()
+
+	return nil
+}
+
+// snapshot takes the keys of every database, see Store.Snapshot
+func (s *Snapshotter) snapshot() []data.Entry {
+	entries := []data.Entry{}
+
+	for db, store := range s.stores {
+		for _, entry := range store.Snapshot() {
+			entry.DB = db
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// LastSave returns the unix time in seconds of the last successful save
+func (s *Snapshotter) LastSave() int64 {
+	return s.lastSave.Load()
+}
+
+// AddChanges records writes made to the store since the last save
+func (s *Snapshotter) AddChanges(count int) {
+	s.changes.Add(int64(count))
+}
+
+// write saves the entries into a temporary file which then replaces the
+// snapshot, so a crash never leaves a partially written snapshot behind
+func (s *Snapshotter) write(entries []data.Entry, changes int64) error {
+	s.saveLock.Lock()
+	defer s.saveLock.Unlock()
+
+	file, err := os.CreateTemp(filepath.Dir(s.filename), "temp-*.rdb")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if err := Encode(file, entries); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), s.filename); err != nil {
+		return err
+	}
+
+	// writes which happened while saving count towards the next save
+	s.changes.Add(-changes)
+	s.lastSave.Store(time.Now().Unix())
+
+	return nil
+}
+
+// CheckSaveRules starts a background save when one of the save rules is met,
+// it is called every second between two commands
+func (s *Snapshotter) CheckSaveRules() {
+	if s.saving.Load() {
+		return
+	}
+
+	changes := s.changes.Load()
+	elapsed := time.Now().Unix() - s.lastSave.Load()
+
+	for _, rule := range s.rules {
+		if changes >= int64(rule.Changes) && changes > 0 && elapsed >= int64(rule.Seconds) {
+			fmt.Printf("%d changes in %d seconds. Saving...\n", rule.Changes, rule.Seconds)
+
+			if err := s.BackgroundSave(); err != nil && err != ErrSaveInProgress {
+				fmt.Println("Error while saving snapshot : ", err)
+			}
+
+			return
+		}
+	}
+}
+
+// HasSaveRules reports whether saves are ever started automatically
+func (s *Snapshotter) HasSaveRules() bool {
+	return len(s.rules) > 0
+}
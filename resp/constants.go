@@ -1,10 +1,30 @@
 package resp
 
+import "errors"
+
 type ArrayType struct {
 	Value any
 	Type  any
 }
 
+// MapType is a single key-value entry of a resp3 map or attribute
+type MapType struct {
+	Key   ArrayType
+	Value ArrayType
+}
+
+// VerbatimType is a resp3 verbatim string along with its three letter format
+type VerbatimType struct {
+	Format string
+	Value  string
+}
+
+// protocol versions
+const (
+	RESP2 int = 2
+	RESP3 int = 3
+)
+
 // CRLF constants
 var (
 	// \r
@@ -23,6 +43,20 @@ const (
 	ARRAY           string = "ARRAY"
 	ERROR           string = "ERROR"
 	UNSUPORTED_TYPE string = "UNSUPORTED_TYPE"
+	// plain text command sent by telnet like clients
+	INLINE string = "INLINE"
+
+	// resp3 only types
+	NULL            string = "NULL"
+	BOOLEAN         string = "BOOLEAN"
+	DOUBLE          string = "DOUBLE"
+	BIG_NUMBER      string = "BIG_NUMBER"
+	BULK_ERROR      string = "BULK_ERROR"
+	VERBATIM_STRING string = "VERBATIM_STRING"
+	MAP             string = "MAP"
+	SET             string = "SET"
+	ATTRIBUTE       string = "ATTRIBUTE"
+	PUSH            string = "PUSH"
 )
 
 // resp prefixes
@@ -32,4 +66,35 @@ const (
 	INTEGER_PREFIX       byte = ':'
 	ARRAY_PREFIX         byte = '*'
 	ERROR_PREFIX         byte = '-'
+
+	// resp3 only prefixes
+	NULL_PREFIX            byte = '_'
+	BOOLEAN_PREFIX         byte = '#'
+	DOUBLE_PREFIX          byte = ','
+	BIG_NUMBER_PREFIX      byte = '('
+	BULK_ERROR_PREFIX      byte = '!'
+	VERBATIM_STRING_PREFIX byte = '='
+	MAP_PREFIX             byte = '%'
+	SET_PREFIX             byte = '~'
+	ATTRIBUTE_PREFIX       byte = '|'
+	PUSH_PREFIX            byte = '>'
+)
+
+// limits
+const (
+	// max size of a single bulk string (512 MB)
+	MAX_BULK_LENGTH int = 512 * 1024 * 1024
+	// max number of elements in a single array
+	MAX_MULTIBULK_LENGTH int = 1024 * 1024
+	// max size of an inline command
+	MAX_INLINE_LENGTH int = 64 * 1024
+)
+
+// deserialization errors
+var (
+	ErrIncompleteData = errors.New("incomplete data")
+	ErrInvalidLength  = errors.New("ERR Protocol error: invalid length")
+	ErrProtocol       = errors.New("ERR Protocol error: expected '\\r\\n'")
+	ErrInlineTooBig   = errors.New("ERR Protocol error: too big inline request")
+	ErrUnbalanced     = errors.New("ERR Protocol error: unbalanced quotes in request")
 )
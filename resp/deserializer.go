@@ -3,74 +3,133 @@ package resp
 import (
 	"bytes"
 	"errors"
+	"math"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
+// readLine returns the content of the line at the beginning of data (without CRLF)
+// along with the total number of bytes consumed including the CRLF
+func readLine(data []byte) ([]byte, int, error) {
+	end := bytes.Index(data, breakPoint)
+
+	if end < 0 {
+		return nil, 0, ErrIncompleteData
+	}
+
+	return data[:end], end + len(breakPoint), nil
+}
+
+func readLength(data []byte) (int, int, error) {
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	length, err := strconv.Atoi(string(line))
+
+	if err != nil {
+		return 0, 0, ErrInvalidLength
+	}
+
+	return length, readLength, nil
+}
+
 func decodeSimpleString(data []byte) (any, error, int) {
-	splitedData := bytes.Split(data, breakPoint)
-	value := string(splitedData[0])
+	line, readLength, err := readLine(data)
 
-	return value, nil, len(splitedData[0]) + len(breakPoint)
+	if err != nil {
+		return nil, err, 0
+	}
+
+	return string(line), nil, readLength
 }
 
 func decodeBulkString(data []byte) (any, error, int) {
-	splitedData := bytes.Split(data, breakPoint)
+	length, headerLength, err := readLength(data)
 
-	if string(splitedData[0]) == "-1" {
-		return nil, nil, len(splitedData[0]) + len(breakPoint)
+	if err != nil {
+		return nil, err, 0
+	}
+
+	if length < 0 {
+		return nil, nil, headerLength
+	}
+
+	if length > MAX_BULK_LENGTH {
+		return nil, ErrInvalidLength, 0
 	}
 
-	return string(splitedData[1]), nil, len(splitedData[1]) + len(splitedData[0]) + (len(breakPoint) * 2)
+	totalLength := headerLength + length + len(breakPoint)
+
+	if len(data) < totalLength {
+		return nil, ErrIncompleteData, 0
+	}
+
+	if !bytes.Equal(data[headerLength+length:totalLength], breakPoint) {
+		return nil, ErrProtocol, 0
+	}
+
+	return string(data[headerLength : headerLength+length]), nil, totalLength
 }
 
 func decodeInteger(data []byte) (any, error, int) {
-	splitedData := bytes.Split(data, breakPoint)
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
 
-	value, err := strconv.Atoi(string(splitedData[0]))
+	value, err := strconv.Atoi(string(line))
 
 	if err != nil {
 		return nil, err, 0
 	}
 
-	return value, nil, len(splitedData[0]) + len(breakPoint)
+	return value, nil, readLength
 }
 
 func decodeError(data []byte) (any, error, int) {
-	splitedData := bytes.Split(data, breakPoint)
+	line, readLength, err := readLine(data)
 
-	value := string(splitedData[0])
+	if err != nil {
+		return nil, err, 0
+	}
 
-	return value, nil, len(splitedData[0]) + len(breakPoint)
+	return string(line), nil, readLength
 }
 
 func decodeArray(data []byte) (any, error, int) {
-	firstSplit := bytes.SplitN(data, breakPoint, 2)
-
-	length, err := strconv.Atoi(string(firstSplit[0]))
+	length, headerLength, err := readLength(data)
 
 	if err != nil {
 		return nil, err, 0
 	}
 
 	if length < 0 {
-		return nil, nil, 0
+		return nil, nil, headerLength
 	}
 
-	values := []ArrayType{}
+	if length > MAX_MULTIBULK_LENGTH {
+		return nil, ErrInvalidLength, 0
+	}
 
-	body := firstSplit[1]
-	totalRead := 0
-	startPositon := 0
+	values := []ArrayType{}
+	startPositon := headerLength
 
 	for i := 0; i < length; i++ {
-		elements, dataType, err, readLength := Deserialize(body[startPositon:])
+		if startPositon >= len(data) {
+			return nil, ErrIncompleteData, 0
+		}
+
+		elements, dataType, err, readLength := Deserialize(data[startPositon:])
 
 		if err != nil {
 			return nil, err, 0
 		}
 
-		totalRead += readLength
-
 		values = append(values, ArrayType{
 			Value: elements,
 			Type:  dataType,
@@ -79,10 +138,159 @@ func decodeArray(data []byte) (any, error, int) {
 		startPositon += readLength
 	}
 
-	return values, nil, totalRead + len(firstSplit[0]) + len(breakPoint)
+	return values, nil, startPositon
+}
+
+func decodeNull(data []byte) (any, error, int) {
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	if len(line) != 0 {
+		return nil, ErrProtocol, 0
+	}
+
+	return nil, nil, readLength
+}
+
+func decodeBoolean(data []byte) (any, error, int) {
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	switch string(line) {
+	case "t":
+		return true, nil, readLength
+	case "f":
+		return false, nil, readLength
+	}
+
+	return nil, errors.New("Protocol error: invalid boolean"), 0
+}
+
+func decodeDouble(data []byte) (any, error, int) {
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	switch strings.ToLower(string(line)) {
+	case "inf", "+inf":
+		return math.Inf(1), nil, readLength
+	case "-inf":
+		return math.Inf(-1), nil, readLength
+	case "nan":
+		return math.NaN(), nil, readLength
+	}
+
+	value, err := strconv.ParseFloat(string(line), 64)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	return value, nil, readLength
 }
 
+func decodeBigNumber(data []byte) (any, error, int) {
+	line, readLength, err := readLine(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	value, valid := new(big.Int).SetString(string(line), 10)
+
+	if !valid {
+		return nil, errors.New("Protocol error: invalid big number"), 0
+	}
+
+	return value, nil, readLength
+}
+
+func decodeVerbatimString(data []byte) (any, error, int) {
+	value, err, readLength := decodeBulkString(data)
+
+	if err != nil || value == nil {
+		return value, err, readLength
+	}
+
+	str := value.(string)
+
+	if len(str) < 4 || str[3] != ':' {
+		return nil, errors.New("Protocol error: invalid verbatim string"), 0
+	}
+
+	return VerbatimType{Format: str[:3], Value: str[4:]}, nil, readLength
+}
+
+func decodeMap(data []byte) (any, error, int) {
+	length, headerLength, err := readLength(data)
+
+	if err != nil {
+		return nil, err, 0
+	}
+
+	if length < 0 {
+		return nil, nil, headerLength
+	}
+
+	if length > MAX_MULTIBULK_LENGTH {
+		return nil, ErrInvalidLength, 0
+	}
+
+	entries := []MapType{}
+	startPositon := headerLength
+
+	for i := 0; i < length; i++ {
+		pair := [2]ArrayType{}
+
+		for j := range pair {
+			if startPositon >= len(data) {
+				return nil, ErrIncompleteData, 0
+			}
+
+			element, dataType, err, readLength := Deserialize(data[startPositon:])
+
+			if err != nil {
+				return nil, err, 0
+			}
+
+			pair[j] = ArrayType{Value: element, Type: dataType}
+			startPositon += readLength
+		}
+
+		entries = append(entries, MapType{Key: pair[0], Value: pair[1]})
+	}
+
+	return entries, nil, startPositon
+}
+
+func isTypePrefix(prefix byte) bool {
+	switch prefix {
+	case SIMPLE_STRING_PREFIX, BULK_STRING_PREFIX, INTEGER_PREFIX, ERROR_PREFIX, ARRAY_PREFIX,
+		NULL_PREFIX, BOOLEAN_PREFIX, DOUBLE_PREFIX, BIG_NUMBER_PREFIX, BULK_ERROR_PREFIX,
+		VERBATIM_STRING_PREFIX, MAP_PREFIX, SET_PREFIX, ATTRIBUTE_PREFIX, PUSH_PREFIX:
+		return true
+	}
+
+	return false
+}
+
+// Deserialize decodes the first RESP value found in data and returns it along
+// with its type and the number of bytes consumed. ErrIncompleteData is returned
+// when data holds only a part of the value, in which case the caller should
+// retry once more bytes are available.
 func Deserialize(data []byte) (any, string, error, int) {
+	if len(data) == 0 {
+		return nil, "", ErrIncompleteData, 0
+	}
+
 	prefix := data[0]
 
 	var decodedData any
@@ -95,53 +303,70 @@ func Deserialize(data []byte) (any, string, error, int) {
 		dataType = SIMPLE_STRING
 		decodedData, err, readLength = decodeSimpleString(data[1:])
 
-		if err != nil {
-			decodedData = nil
-			readLength = -1
-		}
-
 	case BULK_STRING_PREFIX:
 		dataType = BULK_STRING
 		decodedData, err, readLength = decodeBulkString(data[1:])
 
-		if err != nil {
-			decodedData = nil
-			readLength = -1
-		}
-
 	case INTEGER_PREFIX:
 		dataType = INTEGER
 		decodedData, err, readLength = decodeInteger(data[1:])
 
-		if err != nil {
-			decodedData = nil
-			readLength = -1
-		}
-
 	case ERROR_PREFIX:
 		dataType = ERROR
 		decodedData, err, readLength = decodeError(data[1:])
 
-		if err != nil {
-			decodedData = nil
-			readLength = -1
-		}
-
 	case ARRAY_PREFIX:
 		dataType = ARRAY
 		decodedData, err, readLength = decodeArray(data[1:])
 
-		if err != nil {
-			decodedData = nil
-			readLength = -1
-		}
+	case NULL_PREFIX:
+		dataType = NULL
+		decodedData, err, readLength = decodeNull(data[1:])
+
+	case BOOLEAN_PREFIX:
+		dataType = BOOLEAN
+		decodedData, err, readLength = decodeBoolean(data[1:])
+
+	case DOUBLE_PREFIX:
+		dataType = DOUBLE
+		decodedData, err, readLength = decodeDouble(data[1:])
+
+	case BIG_NUMBER_PREFIX:
+		dataType = BIG_NUMBER
+		decodedData, err, readLength = decodeBigNumber(data[1:])
+
+	case BULK_ERROR_PREFIX:
+		dataType = BULK_ERROR
+		decodedData, err, readLength = decodeBulkString(data[1:])
+
+	case VERBATIM_STRING_PREFIX:
+		dataType = VERBATIM_STRING
+		decodedData, err, readLength = decodeVerbatimString(data[1:])
+
+	case MAP_PREFIX:
+		dataType = MAP
+		decodedData, err, readLength = decodeMap(data[1:])
+
+	case SET_PREFIX:
+		dataType = SET
+		decodedData, err, readLength = decodeArray(data[1:])
+
+	case ATTRIBUTE_PREFIX:
+		dataType = ATTRIBUTE
+		decodedData, err, readLength = decodeMap(data[1:])
+
+	case PUSH_PREFIX:
+		dataType = PUSH
+		decodedData, err, readLength = decodeArray(data[1:])
 
 	default:
 		dataType = UNSUPORTED_TYPE
-		decodedData = nil
 		err = errors.New("Data type is not supported")
-		readLength = -1
 	}
 
-	return decodedData, dataType, err, readLength + 1
+	if err != nil {
+		return nil, dataType, err, 0
+	}
+
+	return decodedData, dataType, nil, readLength + 1
 }
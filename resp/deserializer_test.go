@@ -2,6 +2,8 @@ package resp
 
 import (
 	"log"
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -122,3 +124,72 @@ func TestArray(t *testing.T) {
 	})
 	assert.Equal(t, dataType, ARRAY)
 }
+
+func TestResp3Scalars(t *testing.T) {
+	cases := []struct {
+		str      string
+		data     any
+		dataType string
+	}{
+		{"_\r\n", nil, NULL},
+		{"#t\r\n", true, BOOLEAN},
+		{"#f\r\n", false, BOOLEAN},
+		{",3.25\r\n", 3.25, DOUBLE},
+		{",-inf\r\n", math.Inf(-1), DOUBLE},
+		{"=15\r\ntxt:Some string\r\n", VerbatimType{Format: "txt", Value: "Some string"}, VERBATIM_STRING},
+		{"!9\r\nERR wrong\r\n", "ERR wrong", BULK_ERROR},
+	}
+
+	for _, c := range cases {
+		data, dataType, err, _ := Deserialize([]byte(c.str))
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		assert.Equal(t, data, c.data)
+		assert.Equal(t, dataType, c.dataType)
+	}
+
+	data, dataType, err, _ := Deserialize([]byte("(3492890328409238509324850943850943825024385\r\n"))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data.(*big.Int).String(), "3492890328409238509324850943850943825024385")
+	assert.Equal(t, dataType, BIG_NUMBER)
+}
+
+func TestResp3Aggregates(t *testing.T) {
+	str := "%2\r\n+first\r\n:1\r\n+second\r\n~2\r\n$1\r\na\r\n$1\r\nb\r\n"
+
+	data, dataType, err, _ := Deserialize([]byte(str))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, []MapType{
+		{Key: ArrayType{Value: "first", Type: SIMPLE_STRING}, Value: ArrayType{Value: 1, Type: INTEGER}},
+		{Key: ArrayType{Value: "second", Type: SIMPLE_STRING}, Value: ArrayType{Value: []ArrayType{
+			{Value: "a", Type: BULK_STRING},
+			{Value: "b", Type: BULK_STRING},
+		}, Type: SET}},
+	})
+	assert.Equal(t, dataType, MAP)
+
+	str = ">2\r\n$7\r\nmessage\r\n$5\r\nhello\r\n"
+
+	data, dataType, err, _ = Deserialize([]byte(str))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, []ArrayType{
+		{Value: "message", Type: BULK_STRING},
+		{Value: "hello", Type: BULK_STRING},
+	})
+	assert.Equal(t, dataType, PUSH)
+}
@@ -0,0 +1,140 @@
+package resp
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// decodeInline reads a single plain text command line, terminated by "\n" or "\r\n"
+func decodeInline(data []byte) (any, error, int) {
+	end := bytes.IndexByte(data, LF)
+
+	if end < 0 {
+		if len(data) > MAX_INLINE_LENGTH {
+			return nil, ErrInlineTooBig, 0
+		}
+
+		return nil, ErrIncompleteData, 0
+	}
+
+	line := data[:end]
+
+	if len(line) > 0 && line[len(line)-1] == CR {
+		line = line[:len(line)-1]
+	}
+
+	return string(line), nil, end + 1
+}
+
+// SplitInlineArgs splits an inline command into its arguments following the
+// redis quoting rules. Arguments are separated by white space, double quoted
+// arguments support the escapes \n \r \t \b \a \\ \" and \xHH, while single
+// quoted arguments only support \'. A closing quote must be followed by white
+// space or the end of the line.
+func SplitInlineArgs(line string) ([]string, error) {
+	args := []string{}
+	position := 0
+
+	for {
+		for position < len(line) && isSpace(line[position]) {
+			position++
+		}
+
+		if position >= len(line) {
+			return args, nil
+		}
+
+		current := []byte{}
+		inDoubleQuotes := false
+		inSingleQuotes := false
+		done := false
+
+		for !done {
+			if position >= len(line) {
+				if inDoubleQuotes || inSingleQuotes {
+					return nil, ErrUnbalanced
+				}
+
+				break
+			}
+
+			char := line[position]
+
+			switch {
+			case inDoubleQuotes:
+				if char == '\\' && position+3 < len(line) && line[position+1] == 'x' && isHexDigit(line[position+2]) && isHexDigit(line[position+3]) {
+					value, _ := strconv.ParseUint(line[position+2:position+4], 16, 8)
+					current = append(current, byte(value))
+					position += 3
+				} else if char == '\\' && position+1 < len(line) {
+					position++
+					current = append(current, unescape(line[position]))
+				} else if char == '"' {
+					// closing quote must be followed by a space or nothing at all
+					if position+1 < len(line) && !isSpace(line[position+1]) {
+						return nil, ErrUnbalanced
+					}
+
+					done = true
+				} else {
+					current = append(current, char)
+				}
+
+			case inSingleQuotes:
+				if char == '\\' && position+1 < len(line) && line[position+1] == '\'' {
+					position++
+					current = append(current, '\'')
+				} else if char == '\'' {
+					if position+1 < len(line) && !isSpace(line[position+1]) {
+						return nil, ErrUnbalanced
+					}
+
+					done = true
+				} else {
+					current = append(current, char)
+				}
+
+			default:
+				switch char {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inDoubleQuotes = true
+				case '\'':
+					inSingleQuotes = true
+				default:
+					current = append(current, char)
+				}
+			}
+
+			position++
+		}
+
+		args = append(args, string(current))
+	}
+}
+
+func unescape(char byte) byte {
+	switch char {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	}
+
+	return char
+}
+
+func isSpace(char byte) bool {
+	return char == ' ' || char == '\n' || char == '\r' || char == '\t' || char == '\v' || char == '\f'
+}
+
+func isHexDigit(char byte) bool {
+	return (char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
@@ -0,0 +1,66 @@
+package resp
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitInlineArgs(t *testing.T) {
+	cases := []struct {
+		line string
+		args []string
+	}{
+		{"PING", []string{"PING"}},
+		{"  SET   foo   bar  ", []string{"SET", "foo", "bar"}},
+		{`SET foo "hello world"`, []string{"SET", "foo", "hello world"}},
+		{`SET foo "a\"b\n\x41"`, []string{"SET", "foo", "a\"b\nA"}},
+		{`SET foo 'it\'s \n'`, []string{"SET", "foo", `it's \n`}},
+		{`SET foo ""`, []string{"SET", "foo", ""}},
+		{"", []string{}},
+	}
+
+	for _, c := range cases {
+		args, err := SplitInlineArgs(c.line)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		assert.Equal(t, args, c.args, c.line)
+	}
+}
+
+func TestSplitInlineArgsUnbalanced(t *testing.T) {
+	for _, line := range []string{`SET foo "bar`, `SET foo 'bar`, `SET foo "bar"baz`} {
+		_, err := SplitInlineArgs(line)
+		assert.Equal(t, err, ErrUnbalanced, line)
+	}
+}
+
+func TestReaderInline(t *testing.T) {
+	str := "PING\r\nSET foo bar\n*1\r\n$4\r\nPING\r\n"
+
+	reader := NewReader(bytes.NewReader([]byte(str)))
+
+	for _, expected := range []string{"PING", "SET foo bar"} {
+		data, dataType, err := reader.Read()
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		assert.Equal(t, data, expected)
+		assert.Equal(t, dataType, INLINE)
+	}
+
+	_, dataType, err := reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, dataType, ARRAY)
+}
@@ -0,0 +1,216 @@
+package resp
+
+import (
+	"io"
+)
+
+const readChunkSize int = 16 * 1024
+
+// Reader reads RESP values from a stream. Bytes which do not yet form a
+// complete value are kept and completed by subsequent reads, so values split
+// across several reads as well as several values in a single read (pipelining)
+// are both handled.
+type Reader struct {
+	reader io.Reader
+	buffer []byte
+	start  int
+	chunk  []byte
+	// how far the value at start has been checked for completeness
+	frame frameScan
+}
+
+// frameScan tracks the progress through a value which is not fully buffered
+// yet, so that every read only looks at the bytes it added and the value is
+// decoded once, when all of it is available
+type frameScan struct {
+	// bytes of the value known to belong to complete elements or headers
+	scanned int
+	// elements still expected by each aggregate opened so far, outermost first
+	pending []int
+}
+
+func NewReader(reader io.Reader) *Reader {
+	return &Reader{
+		reader: reader,
+		buffer: []byte{},
+		chunk:  make([]byte, readChunkSize),
+	}
+}
+
+// Read returns the next complete value from the stream, reading from the
+// underlying reader only when the buffered bytes do not hold one.
+func (r *Reader) Read() (any, string, error) {
+	for {
+		if r.Buffered() > 0 && r.frameComplete() {
+			data, dataType, err, readLength := r.decode(r.buffer[r.start:])
+
+			if err == nil {
+				r.start += readLength
+				r.frame = frameScan{}
+				return data, dataType, nil
+			}
+
+			if err != ErrIncompleteData {
+				r.reset()
+				return nil, dataType, err
+			}
+		}
+
+		if err := r.fill(); err != nil {
+			return nil, "", err
+		}
+	}
+}
+
+// frameComplete continues checking the value at start from where the previous
+// call stopped, and reports whether it is fully buffered. Malformed values are
+// reported as complete, leaving the error to decode.
+func (r *Reader) frameComplete() bool {
+	frame := &r.frame
+
+	for {
+		data := r.buffer[r.start+frame.scanned:]
+
+		if len(data) == 0 {
+			return false
+		}
+
+		// inline commands are not nested and never longer than MAX_INLINE_LENGTH,
+		// decode finds their end by itself
+		if !isTypePrefix(data[0]) {
+			return true
+		}
+
+		elements, readLength, err := scanHeader(data)
+
+		if err == ErrIncompleteData {
+			return false
+		}
+
+		if err != nil {
+			return true
+		}
+
+		frame.scanned += readLength
+
+		if elements > 0 {
+			frame.pending = append(frame.pending, elements)
+			continue
+		}
+
+		// the element is complete, and so is every aggregate it completes
+		for len(frame.pending) > 0 {
+			last := len(frame.pending) - 1
+			frame.pending[last]--
+
+			if frame.pending[last] > 0 {
+				break
+			}
+
+			frame.pending = frame.pending[:last]
+		}
+
+		if len(frame.pending) == 0 {
+			return true
+		}
+	}
+}
+
+// scanHeader returns the number of nested elements announced by the value at
+// the beginning of data and the bytes it takes before them. Bulk values are
+// skipped whole, and only once all of their bytes are buffered.
+func scanHeader(data []byte) (int, int, error) {
+	switch data[0] {
+	case ARRAY_PREFIX, SET_PREFIX, PUSH_PREFIX, MAP_PREFIX, ATTRIBUTE_PREFIX:
+		length, headerLength, err := readLength(data[1:])
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if length > MAX_MULTIBULK_LENGTH {
+			return 0, 0, ErrInvalidLength
+		}
+
+		if data[0] == MAP_PREFIX || data[0] == ATTRIBUTE_PREFIX {
+			length *= 2
+		}
+
+		return max(length, 0), headerLength + 1, nil
+
+	case BULK_STRING_PREFIX, BULK_ERROR_PREFIX, VERBATIM_STRING_PREFIX:
+		length, headerLength, err := readLength(data[1:])
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if length < 0 {
+			return 0, headerLength + 1, nil
+		}
+
+		if length > MAX_BULK_LENGTH {
+			return 0, 0, ErrInvalidLength
+		}
+
+		totalLength := headerLength + length + len(breakPoint) + 1
+
+		if len(data) < totalLength {
+			return 0, 0, ErrIncompleteData
+		}
+
+		return 0, totalLength, nil
+	}
+
+	_, readLength, err := readLine(data[1:])
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return 0, readLength + 1, nil
+}
+
+// decode deserializes the next value, anything which does not start with a
+// known type prefix is treated as an inline command
+func (r *Reader) decode(data []byte) (any, string, error, int) {
+	if isTypePrefix(data[0]) {
+		return Deserialize(data)
+	}
+
+	value, err, readLength := decodeInline(data)
+	return value, INLINE, err, readLength
+}
+
+// Buffered returns the number of bytes read from the stream but not yet consumed
+func (r *Reader) Buffered() int {
+	return len(r.buffer) - r.start
+}
+
+func (r *Reader) fill() error {
+	// move the leftover to the front so the buffer does not grow without bound
+	if r.start > 0 {
+		remaining := copy(r.buffer, r.buffer[r.start:])
+		r.buffer = r.buffer[:remaining]
+		r.start = 0
+	}
+
+	n, err := r.reader.Read(r.chunk)
+
+	if n > 0 {
+		r.buffer = append(r.buffer, r.chunk[:n]...)
+		return nil
+	}
+
+	if err == io.EOF && r.Buffered() > 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+func (r *Reader) reset() {
+	r.buffer = r.buffer[:0]
+	r.start = 0
+	r.frame = frameScan{}
+}
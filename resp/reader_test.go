@@ -0,0 +1,178 @@
+package resp
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// chunkedReader returns the underlying data a few bytes at a time
+type chunkedReader struct {
+	data      []byte
+	chunkSize int
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if len(c.data) == 0 {
+		return 0, io.EOF
+	}
+
+	n := min(c.chunkSize, len(p), len(c.data))
+	copy(p, c.data[:n])
+	c.data = c.data[n:]
+
+	return n, nil
+}
+
+func TestReaderPipelined(t *testing.T) {
+	str := "*1\r\n$4\r\nPING\r\n*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n:10\r\n"
+
+	reader := NewReader(bytes.NewReader([]byte(str)))
+
+	data, dataType, err := reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, []ArrayType{
+		{Value: "PING", Type: BULK_STRING},
+	})
+	assert.Equal(t, dataType, ARRAY)
+
+	data, dataType, err = reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, []ArrayType{
+		{Value: "SET", Type: BULK_STRING},
+		{Value: "key", Type: BULK_STRING},
+		{Value: "value", Type: BULK_STRING},
+	})
+	assert.Equal(t, dataType, ARRAY)
+
+	data, dataType, err = reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, 10)
+	assert.Equal(t, dataType, INTEGER)
+
+	_, _, err = reader.Read()
+	assert.Equal(t, err, io.EOF)
+}
+
+func TestReaderPartialFrames(t *testing.T) {
+	str := "*2\r\n$4\r\necho\r\n$12\r\nhello\r\nworld\r\n+OK\r\n"
+
+	reader := NewReader(&chunkedReader{data: []byte(str), chunkSize: 3})
+
+	data, dataType, err := reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, []ArrayType{
+		{Value: "echo", Type: BULK_STRING},
+		{Value: "hello\r\nworld", Type: BULK_STRING},
+	})
+	assert.Equal(t, dataType, ARRAY)
+
+	data, dataType, err = reader.Read()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, data, "OK")
+	assert.Equal(t, dataType, SIMPLE_STRING)
+	assert.Equal(t, reader.Buffered(), 0)
+}
+
+// largeFrame returns an array of count bulk strings followed by a ping
+func largeFrame(count int) ([]byte, []ArrayType) {
+	frame := []byte("*" + strconv.Itoa(count+2) + "\r\n*2\r\n:1\r\n%1\r\n+key\r\n$-1\r\n")
+	expected := []ArrayType{
+		{Value: []ArrayType{{Value: 1, Type: INTEGER}, {Value: []MapType{{
+			Key:   ArrayType{Value: "key", Type: SIMPLE_STRING},
+			Value: ArrayType{Value: nil, Type: BULK_STRING},
+		}}, Type: MAP}}, Type: ARRAY},
+	}
+
+	for i := 0; i < count; i++ {
+		value := "value:" + strconv.Itoa(i)
+		frame = append(frame, "$"+strconv.Itoa(len(value))+"\r\n"+value+"\r\n"...)
+		expected = append(expected, ArrayType{Value: value, Type: BULK_STRING})
+	}
+
+	big := strings.Repeat("x", 64*1024)
+	frame = append(frame, "$"+strconv.Itoa(len(big))+"\r\n"+big+"\r\n+PING\r\n"...)
+	expected = append(expected, ArrayType{Value: big, Type: BULK_STRING})
+
+	return frame, expected
+}
+
+func TestReaderLargeFrameInSmallChunks(t *testing.T) {
+	frame, expected := largeFrame(20000)
+
+	reader := NewReader(&chunkedReader{data: frame, chunkSize: 7})
+
+	data, dataType, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, ARRAY, dataType)
+	assert.Equal(t, expected, data)
+
+	data, _, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "PING", data)
+	assert.Equal(t, frameScan{}, reader.frame)
+}
+
+func TestReaderMalformedFrame(t *testing.T) {
+	// the errors found while buffering a value are the ones decoding reports
+	for _, str := range []string{"*2\r\n$3\r\nGET\r\n$x\r\n", "*1\r\n$3\r\nGETxx", "*1\r\nGET\r\n"} {
+		reader := NewReader(&chunkedReader{data: []byte(str), chunkSize: 2})
+
+		_, _, err := reader.Read()
+		assert.Error(t, err, str)
+		assert.NotEqual(t, io.ErrUnexpectedEOF, err, str)
+	}
+}
+
+func BenchmarkReaderLargeFrameInSmallChunks(b *testing.B) {
+	frame, _ := largeFrame(20000)
+
+	for i := 0; i < b.N; i++ {
+		reader := NewReader(&chunkedReader{data: frame, chunkSize: 64})
+
+		if _, _, err := reader.Read(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	str := "*2\r\n$3\r\nGET\r\n$3\r\nke"
+
+	reader := NewReader(bytes.NewReader([]byte(str)))
+
+	_, _, err := reader.Read()
+	assert.Equal(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestDeserializeIncomplete(t *testing.T) {
+	for _, str := range []string{"", "+OK", "$5\r\nhel", "*2\r\n$3\r\nGET\r\n", ":1"} {
+		_, _, err, _ := Deserialize([]byte(str))
+		assert.Equal(t, err, ErrIncompleteData, str)
+	}
+}
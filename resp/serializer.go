@@ -3,6 +3,8 @@ package resp
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 )
 
@@ -14,26 +16,28 @@ func serializeSimpleString(input string) ([]byte, error) {
 	return data, nil
 }
 
-func serializeBulkString(input any) ([]byte, error) {
-	data := []byte{}
+func serializeBulkString(protocol int, input any) ([]byte, error) {
+	if input == nil {
+		return serializeNull(protocol, BULK_STRING_PREFIX)
+	}
 
-	data = append(data, BULK_STRING_PREFIX)
+	inputString, isString := input.(string)
 
-	if input == nil {
-		data = append(data, []byte("-1")...)
-		data = append(data, breakPoint...)
-		return data, nil
+	if !isString {
+		return []byte{}, errors.New("Can not convert data to string")
 	}
 
-	inputString := input.(string)
-	length := strconv.Itoa(len(inputString))
+	return serializeBlob(BULK_STRING_PREFIX, inputString), nil
+}
 
-	data = append(data, []byte(length)...)
+func serializeBlob(prefix byte, input string) []byte {
+	data := []byte{}
+	data = append(data, prefix)
+	data = append(data, []byte(strconv.Itoa(len(input)))...)
 	data = append(data, breakPoint...)
-	data = append(data, []byte(inputString)...)
+	data = append(data, []byte(input)...)
 	data = append(data, breakPoint...)
-
-	return data, nil
+	return data
 }
 
 func serializeError(input string) ([]byte, error) {
@@ -52,100 +56,243 @@ func serializeInteger(input int) ([]byte, error) {
 	return data, nil
 }
 
-func serializeArray(input any) ([]byte, error) {
+// serializeNull writes the resp3 null, or the resp2 null of the given
+// aggregate prefix ($-1 / *-1) for resp2 connections
+func serializeNull(protocol int, resp2Prefix byte) ([]byte, error) {
 	data := []byte{}
 
-	data = append(data, ARRAY_PREFIX)
-
-	if input == nil {
-		data = append(data, []byte("-1")...)
+	if protocol == RESP3 {
+		data = append(data, NULL_PREFIX)
 		data = append(data, breakPoint...)
 		return data, nil
 	}
 
-	elements := input.([]ArrayType)
-	length := strconv.Itoa(len(elements))
-	data = append(data, []byte(length)...)
+	data = append(data, resp2Prefix)
+	data = append(data, []byte("-1")...)
 	data = append(data, breakPoint...)
+	return data, nil
+}
 
-	for _, item := range elements {
-		switch item.Type {
-		case SIMPLE_STRING:
-			serializedData, err := serializeSimpleString(item.Value.(string))
+func serializeBoolean(protocol int, input any) ([]byte, error) {
+	value, isBool := input.(bool)
 
-			if err != nil {
-				return []byte{}, err
-			}
+	if !isBool {
+		return []byte{}, errors.New("Can not convert data to boolean")
+	}
 
-			data = append(data, serializedData...)
-		case BULK_STRING:
-			serializedData, err := serializeBulkString(item.Value)
+	if protocol != RESP3 {
+		if value {
+			return serializeInteger(1)
+		}
 
-			if err != nil {
-				return []byte{}, err
-			}
+		return serializeInteger(0)
+	}
 
-			data = append(data, serializedData...)
-		case ERROR:
-			serializedData, err := serializeError(item.Value.(string))
+	data := []byte{BOOLEAN_PREFIX}
 
-			if err != nil {
-				return []byte{}, err
-			}
+	if value {
+		data = append(data, 't')
+	} else {
+		data = append(data, 'f')
+	}
 
-			data = append(data, serializedData...)
-		case INTEGER:
-			number, err := getIntValue(item.Value)
+	data = append(data, breakPoint...)
+	return data, nil
+}
 
-			if err != nil {
-				return []byte{}, err
-			}
+// FormatDouble formats a float the way redis replies with doubles
+func FormatDouble(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	case math.IsNaN(value):
+		return "nan"
+	}
 
-			serializedData, err := serializeInteger(number)
+	// plain notation for everything that fits in 17 significant digits
+	if absolute := math.Abs(value); absolute == 0 || (absolute >= 1e-5 && absolute < 1e17) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
 
-			if err != nil {
-				return []byte{}, err
-			}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
 
-			data = append(data, serializedData...)
+func serializeDouble(protocol int, input any) ([]byte, error) {
+	var value string
+
+	switch number := input.(type) {
+	case float64:
+		value = FormatDouble(number)
+	case int:
+		value = strconv.Itoa(number)
+	case string:
+		if _, err := strconv.ParseFloat(number, 64); err != nil {
+			return []byte{}, fmt.Errorf("Can not convert %v to double", input)
+		}
 
-		case ARRAY:
-			serializedData, err := serializeArray(item.Value)
+		value = number
+	default:
+		return []byte{}, fmt.Errorf("Can not convert %v to double", input)
+	}
 
-			if err != nil {
-				return []byte{}, err
-			}
+	if protocol != RESP3 {
+		return serializeBlob(BULK_STRING_PREFIX, value), nil
+	}
 
-			data = append(data, serializedData...)
+	data := []byte{DOUBLE_PREFIX}
+	data = append(data, []byte(value)...)
+	data = append(data, breakPoint...)
+	return data, nil
+}
+
+func serializeBigNumber(protocol int, input any) ([]byte, error) {
+	var value string
+
+	switch number := input.(type) {
+	case *big.Int:
+		value = number.String()
+	case int:
+		value = strconv.Itoa(number)
+	case string:
+		if _, valid := new(big.Int).SetString(number, 10); !valid {
+			return []byte{}, fmt.Errorf("Can not convert %v to big number", input)
 		}
+
+		value = number
+	default:
+		return []byte{}, fmt.Errorf("Can not convert %v to big number", input)
+	}
+
+	if protocol != RESP3 {
+		return serializeBlob(BULK_STRING_PREFIX, value), nil
 	}
 
+	data := []byte{BIG_NUMBER_PREFIX}
+	data = append(data, []byte(value)...)
+	data = append(data, breakPoint...)
 	return data, nil
 }
 
-func getIntValue(data any) (int, error) {
-	num, isNumber := data.(int)
+func serializeBulkError(protocol int, input string) ([]byte, error) {
+	if protocol != RESP3 {
+		return serializeError(input)
+	}
 
-	if isNumber {
-		return num, nil
+	return serializeBlob(BULK_ERROR_PREFIX, input), nil
+}
+
+func serializeVerbatimString(protocol int, input any) ([]byte, error) {
+	var verbatim VerbatimType
+
+	switch value := input.(type) {
+	case VerbatimType:
+		verbatim = value
+	case string:
+		verbatim = VerbatimType{Format: "txt", Value: value}
+	default:
+		return []byte{}, errors.New("Can not convert data to verbatim string")
 	}
 
-	str, isString := data.(string)
+	if protocol != RESP3 {
+		return serializeBlob(BULK_STRING_PREFIX, verbatim.Value), nil
+	}
 
-	if isString {
-		num, err := strconv.Atoi(str)
+	if len(verbatim.Format) != 3 {
+		return []byte{}, errors.New("Verbatim string format must be three characters")
+	}
+
+	return serializeBlob(VERBATIM_STRING_PREFIX, verbatim.Format+":"+verbatim.Value), nil
+}
+
+func serializeAggregate(protocol int, prefix byte, input any) ([]byte, error) {
+	if input == nil {
+		return serializeNull(protocol, ARRAY_PREFIX)
+	}
+
+	elements, isArray := input.([]ArrayType)
+
+	if !isArray {
+		return []byte{}, errors.New("Can not convert data to array")
+	}
+
+	if protocol != RESP3 {
+		prefix = ARRAY_PREFIX
+	}
+
+	data := []byte{}
+	data = append(data, prefix)
+	data = append(data, []byte(strconv.Itoa(len(elements)))...)
+	data = append(data, breakPoint...)
+
+	for _, item := range elements {
+		serializedData, err := serializeElement(protocol, item)
 
 		if err != nil {
-			return 0, err
+			return []byte{}, err
 		}
 
-		return num, nil
+		data = append(data, serializedData...)
 	}
 
-	return 0, errors.New(fmt.Sprintf("Can not convert %v to integer", data))
+	return data, nil
 }
 
-func Serialize(dataType string, data any) ([]byte, error) {
+// serializeMap writes a resp3 map (or attribute). For resp2 connections maps
+// are flattened into an array of alternating keys and values and attributes
+// are dropped, just as redis does.
+func serializeMap(protocol int, prefix byte, input any) ([]byte, error) {
+	if input == nil {
+		return serializeNull(protocol, ARRAY_PREFIX)
+	}
+
+	entries, isMap := input.([]MapType)
+
+	if !isMap {
+		return []byte{}, errors.New("Can not convert data to map")
+	}
+
+	if protocol != RESP3 {
+		if prefix == ATTRIBUTE_PREFIX {
+			return []byte{}, nil
+		}
+
+		flattened := []ArrayType{}
+
+		for _, entry := range entries {
+			flattened = append(flattened, entry.Key, entry.Value)
+		}
+
+		return serializeAggregate(protocol, ARRAY_PREFIX, flattened)
+	}
+
+	data := []byte{}
+	data = append(data, prefix)
+	data = append(data, []byte(strconv.Itoa(len(entries)))...)
+	data = append(data, breakPoint...)
+
+	for _, entry := range entries {
+		for _, item := range []ArrayType{entry.Key, entry.Value} {
+			serializedData, err := serializeElement(protocol, item)
+
+			if err != nil {
+				return []byte{}, err
+			}
+
+			data = append(data, serializedData...)
+		}
+	}
+
+	return data, nil
+}
+
+func serializeElement(protocol int, item ArrayType) ([]byte, error) {
+	dataType, _ := item.Type.(string)
+	return serializeValue(protocol, dataType, item.Value)
+}
+
+func serializeValue(protocol int, dataType string, data any) ([]byte, error) {
 	switch dataType {
 	case SIMPLE_STRING:
 		str, isString := data.(string)
@@ -157,7 +304,7 @@ func Serialize(dataType string, data any) ([]byte, error) {
 		return serializeSimpleString(str)
 
 	case BULK_STRING:
-		return serializeBulkString(data)
+		return serializeBulkString(protocol, data)
 
 	case ERROR:
 		str, isString := data.(string)
@@ -175,9 +322,80 @@ func Serialize(dataType string, data any) ([]byte, error) {
 			return []byte{}, err
 		}
 		return serializeInteger(number)
+
 	case ARRAY:
-		return serializeArray(data)
+		return serializeAggregate(protocol, ARRAY_PREFIX, data)
+
+	case NULL:
+		return serializeNull(protocol, BULK_STRING_PREFIX)
+
+	case BOOLEAN:
+		return serializeBoolean(protocol, data)
+
+	case DOUBLE:
+		return serializeDouble(protocol, data)
+
+	case BIG_NUMBER:
+		return serializeBigNumber(protocol, data)
+
+	case BULK_ERROR:
+		str, isString := data.(string)
+
+		if !isString {
+			return []byte{}, errors.New("Can not convert data to error")
+		}
+
+		return serializeBulkError(protocol, str)
+
+	case VERBATIM_STRING:
+		return serializeVerbatimString(protocol, data)
+
+	case MAP:
+		return serializeMap(protocol, MAP_PREFIX, data)
+
+	case ATTRIBUTE:
+		return serializeMap(protocol, ATTRIBUTE_PREFIX, data)
+
+	case SET:
+		return serializeAggregate(protocol, SET_PREFIX, data)
+
+	case PUSH:
+		return serializeAggregate(protocol, PUSH_PREFIX, data)
 	}
 
 	return []byte{}, nil
 }
+
+func getIntValue(data any) (int, error) {
+	num, isNumber := data.(int)
+
+	if isNumber {
+		return num, nil
+	}
+
+	str, isString := data.(string)
+
+	if isString {
+		num, err := strconv.Atoi(str)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return num, nil
+	}
+
+	return 0, errors.New(fmt.Sprintf("Can not convert %v to integer", data))
+}
+
+// Serialize encodes data for a resp2 connection, resp3 only types are
+// converted to their closest resp2 counterpart
+func Serialize(dataType string, data any) ([]byte, error) {
+	return serializeValue(RESP2, dataType, data)
+}
+
+// SerializeWithProtocol encodes data for a connection speaking the given
+// protocol version
+func SerializeWithProtocol(protocol int, dataType string, data any) ([]byte, error) {
+	return serializeValue(protocol, dataType, data)
+}
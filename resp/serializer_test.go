@@ -72,3 +72,63 @@ func TestArraySerialization(t *testing.T) {
 
 	assert.Equal(t, string(response), "*3\r\n+Hello\r\n$8\r\nGET KEYS\r\n*1\r\n:1\r\n")
 }
+
+func TestResp3Serialization(t *testing.T) {
+	data := []MapType{
+		{Key: ArrayType{Value: "proto", Type: BULK_STRING}, Value: ArrayType{Value: 3, Type: INTEGER}},
+		{Key: ArrayType{Value: "tags", Type: BULK_STRING}, Value: ArrayType{Value: []ArrayType{
+			{Value: "a", Type: BULK_STRING},
+		}, Type: SET}},
+	}
+
+	response, err := SerializeWithProtocol(RESP3, MAP, data)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, string(response), "%2\r\n$5\r\nproto\r\n:3\r\n$4\r\ntags\r\n~1\r\n$1\r\na\r\n")
+
+	response, err = SerializeWithProtocol(RESP2, MAP, data)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, string(response), "*4\r\n$5\r\nproto\r\n:3\r\n$4\r\ntags\r\n*1\r\n$1\r\na\r\n")
+}
+
+func TestResp3ScalarSerialization(t *testing.T) {
+	cases := []struct {
+		dataType string
+		data     any
+		resp3    string
+		resp2    string
+	}{
+		{NULL, nil, "_\r\n", "$-1\r\n"},
+		{BULK_STRING, nil, "_\r\n", "$-1\r\n"},
+		{BOOLEAN, true, "#t\r\n", ":1\r\n"},
+		{DOUBLE, 1.5, ",1.5\r\n", "$3\r\n1.5\r\n"},
+		{BIG_NUMBER, "3492890328409238509324850943850943825024385", "(3492890328409238509324850943850943825024385\r\n", "$43\r\n3492890328409238509324850943850943825024385\r\n"},
+		{VERBATIM_STRING, "Some string", "=15\r\ntxt:Some string\r\n", "$11\r\nSome string\r\n"},
+		{PUSH, []ArrayType{{Value: "message", Type: BULK_STRING}}, ">1\r\n$7\r\nmessage\r\n", "*1\r\n$7\r\nmessage\r\n"},
+	}
+
+	for _, c := range cases {
+		response, err := SerializeWithProtocol(RESP3, c.dataType, c.data)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		assert.Equal(t, string(response), c.resp3)
+
+		response, err = SerializeWithProtocol(RESP2, c.dataType, c.data)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		assert.Equal(t, string(response), c.resp2)
+	}
+}
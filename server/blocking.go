@@ -0,0 +1,115 @@
+package server
+
+import (
+	"io"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/data"
+	"github.com/iamvineettiwari/go-redis-server-lite/handler"
+)
+
+// blockedClient is a client parked by a blocking command
+type blockedClient struct {
+	store        *data.Store
+	waiter       *data.Waiter
+	timeout      time.Duration
+	timeoutReply []byte
+	// closed once the command is served and its reply written
+	served chan struct{}
+}
+
+// block registers the client as waiting on the keys of blockRequest. It must
+// be called while holding execLock, so no element can be pushed in between.
+func (s *RedisServer) block(client *handler.Client, command string, handlerFunc handler.HandlerFunc, args []any, blockRequest *handler.BlockRequest) *blockedClient {
+	blocked := &blockedClient{
+		store:        s.stores[client.DB],
+		timeout:      blockRequest.Timeout,
+		timeoutReply: blockRequest.TimeoutReply,
+		served:       make(chan struct{}),
+	}
+
+	if blockRequest.Args != nil {
+		args = blockRequest.Args
+	}
+
+	// runs with execLock held, from whichever client pushed to one of the keys
+	serve := func() bool {
+		response, err := handlerFunc(client, args...)
+
+		// still nothing to pop, another waiter came first
+		if client.TakeBlockRequest() != nil {
+			return false
+		}
+
+		if err != nil {
+			errorHelper(err, client.Writer)
+		} else {
+			s.propagate(client, command, args)
+			client.Write(response)
+		}
+
+		close(blocked.served)
+		return true
+	}
+
+	blocked.waiter = blocked.store.Block(blockRequest.Keys, serve)
+	return blocked
+}
+
+// waitUnblocked parks the connection until the blocked command is served, its
+// timeout elapses or the client disconnects. A request read in the meantime is
+// returned to be handled next, reads stop until then.
+func (s *RedisServer) waitUnblocked(client *handler.Client, blocked *blockedClient, requests <-chan request) *request {
+	var timeout <-chan time.Time
+
+	if blocked.timeout > 0 {
+		timer := time.NewTimer(blocked.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var pending *request
+
+	for {
+		select {
+		case <-blocked.served:
+			return pending
+
+		case <-timeout:
+			s.unblock(client, blocked, blocked.timeoutReply)
+			return pending
+
+		case current, open := <-requests:
+			if !open {
+				current = request{err: io.EOF}
+			}
+
+			pending = &current
+
+			// the client went away, or broke the protocol, stop waiting for it
+			if current.err != nil {
+				s.unblock(client, blocked, nil)
+				return pending
+			}
+
+			requests = nil
+		}
+	}
+}
+
+// unblock removes the client from the waiters unless it was served right
+// before, in which case the reply is already written
+func (s *RedisServer) unblock(client *handler.Client, blocked *blockedClient, reply []byte) {
+	s.execLock.Lock()
+	defer s.execLock.Unlock()
+
+	select {
+	case <-blocked.served:
+		return
+	default:
+	}
+
+	blocked.store.Unblock(blocked.waiter)
+
+	client.Write(reply)
+}
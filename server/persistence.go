@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamvineettiwari/go-redis-server-lite/aof"
+	"github.com/iamvineettiwari/go-redis-server-lite/handler"
+	"github.com/iamvineettiwari/go-redis-server-lite/rdb"
+)
+
+// EnableAppendOnly replays the append only file into the store and logs every
+// write command executed from now on into it
+func (s *RedisServer) EnableAppendOnly(filename string, fsyncPolicy string) error {
+	appendOnlyFile, err := aof.NewAOF(filename, fsyncPolicy)
+
+	if err != nil {
+		return err
+	}
+
+	replayClient := handler.NewClient(0)
+
+	err = appendOnlyFile.Load(func(command string, args []any) error {
+		spec, commandRegistered := s.handlers.ResolveCommand(strings.ToUpper(command))
+
+		if !commandRegistered {
+			return fmt.Errorf("Unknown command %s", command)
+		}
+
+		if err := spec.CheckArity(args); err != nil {
+			return err
+		}
+
+		_, err := spec.Handler(replayClient, args...)
+		return err
+	})
+
+	if err != nil {
+		appendOnlyFile.Close()
+		return err
+	}
+
+	s.aof = appendOnlyFile
+	return nil
+}
+
+// EnableSnapshots configures the snapshot file used by SAVE, BGSAVE and the
+// automatic save rules, restoring the store from it when load is set
+func (s *RedisServer) EnableSnapshots(filename string, rules []rdb.SaveRule, load bool) error {
+	snapshotter := rdb.NewSnapshotter(filename, s.stores, rules)
+
+	if load {
+		if err := snapshotter.Load(); err != nil {
+			return err
+		}
+	}
+
+	s.snapshotter = snapshotter
+	s.handlers.ConfigureSnapshotter(snapshotter)
+
+	if snapshotter.HasSaveRules() {
+		go s.checkSaveRules()
+	}
+
+	return nil
+}
+
+// checkSaveRules starts the automatic saves between two commands, so that
+// like with BGSAVE the snapshot never holds half of a transaction, or a key
+// in the middle of being renamed or moved
+func (s *RedisServer) checkSaveRules() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.execLock.Lock()
+		s.snapshotter.CheckSaveRules()
+		s.execLock.Unlock()
+	}
+}
+
+func (s *RedisServer) feedAppendOnly(db int, command string, args []any) {
+	if s.aof == nil {
+		return
+	}
+
+	if db != s.aofDB {
+		if err := s.aof.Append(handler.SELECT, strconv.Itoa(db)); err != nil {
+			fmt.Println("Error while writing append only file : ", err)
+			return
+		}
+
+		s.aofDB = db
+	}
+
+	if err := s.aof.Append(command, args...); err != nil {
+		fmt.Println("Error while writing append only file : ", err)
+		return
+	}
+
+	if !slices.Contains(handler.RELATIVE_EXPIRE_COMMANDS, command) || len(args) < 1 {
+		return
+	}
+
+	// replaying a relative timeout would restart it, so the absolute time is logged too
+	key := args[0].(string)
+	expireAt := s.stores[db].ExpireTime(key)
+
+	if expireAt < 0 {
+		return
+	}
+
+	if err := s.aof.Append(handler.PEXPIREAT, key, strconv.FormatInt(expireAt, 10)); err != nil {
+		fmt.Println("Error while writing append only file : ", err)
+	}
+}
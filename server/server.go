@@ -5,30 +5,51 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/iamvineettiwari/go-redis-server-lite/aof"
 	"github.com/iamvineettiwari/go-redis-server-lite/data"
 	"github.com/iamvineettiwari/go-redis-server-lite/handler"
+	"github.com/iamvineettiwari/go-redis-server-lite/rdb"
 	"github.com/iamvineettiwari/go-redis-server-lite/resp"
 )
 
 type RedisServer struct {
 	ListenAddr string
 	Listener   net.Listener
-	store      *data.Store
-	connLock   chan struct{}
-	handlers   *handler.Handler
+	// one store per logical database
+	stores   []*data.Store
+	connLock chan struct{}
+	handlers *handler.Handler
+	clientID atomic.Int64
+	// commands run one at a time, like in redis, so the effects of a command
+	// are never interleaved with another one and the logs keep their order
+	execLock *sync.Mutex
+	aof      *aof.AOF
+	// database of the commands logged last, -1 until one is logged
+	aofDB       int
+	snapshotter *rdb.Snapshotter
 }
 
-func NewRedisServer(listenAddr string, handler *handler.Handler) *RedisServer {
-	store := data.NewStore()
-	handler.ConfigureStore(store)
+func NewRedisServer(listenAddr string, handler *handler.Handler, databases int) *RedisServer {
+	stores := make([]*data.Store, databases)
+
+	for i := range stores {
+		stores[i] = data.NewStore()
+	}
+
+	handler.ConfigureStores(stores)
 
 	return &RedisServer{
 		ListenAddr: listenAddr,
 		connLock:   make(chan struct{}),
-		store:      store,
+		stores:     stores,
 		handlers:   handler,
+		execLock:   &sync.Mutex{},
+		aofDB:      -1,
 	}
 }
 
@@ -66,56 +87,194 @@ func (s *RedisServer) closeConnection(conn net.Conn) {
 	conn.Close()
 }
 
+// request is a command read from a connection, or the error which ended the reads
+type request struct {
+	value       any
+	requestType string
+	err         error
+}
+
 func (s *RedisServer) read(conn net.Conn) {
 	defer s.closeConnection(conn)
 
-	buffer := make([]byte, 6048)
+	writer := newConnWriter(conn)
+	defer writer.Close()
+
+	client := handler.NewClient(s.clientID.Add(1))
+	client.Writer = writer
+	defer s.handlers.Disconnect(client)
+
+	// requests are read by their own goroutine, so a disconnection is noticed
+	// while the client is blocked
+	requests := make(chan request)
+	done := make(chan struct{})
+	defer close(done)
+
+	go readRequests(resp.NewReader(conn), requests, done)
+
+	var pending *request
 
 	for {
-		n, err := conn.Read(buffer)
+		var current request
 
-		if err != nil {
-			if err == io.EOF {
+		if pending != nil {
+			current, pending = *pending, nil
+		} else {
+			var open bool
+
+			if current, open = <-requests; !open {
 				break
 			}
+		}
 
-			fmt.Println("Error while reading : ", err)
-			continue
+		if current.err != nil {
+			if current.err == io.EOF {
+				break
+			}
+
+			if isProtocolError(current.err) {
+				errorHelper(current.err, writer)
+				break
+			}
+
+			fmt.Println("Error while reading : ", current.err)
+			break
 		}
 
-		data := buffer[:n]
+		if blocked := s.handleRequest(client, current.value, current.requestType); blocked != nil {
+			pending = s.waitUnblocked(client, blocked, requests)
+		}
+	}
+}
 
-		request, requestType, err, _ := resp.Deserialize(data)
+func readRequests(reader *resp.Reader, requests chan<- request, done <-chan struct{}) {
+	defer close(requests)
 
-		s.handleRequest(conn, request, requestType)
+	for {
+		value, requestType, err := reader.Read()
+
+		select {
+		case requests <- request{value: value, requestType: requestType, err: err}:
+		case <-done:
+			return
+		}
+
+		if err != nil {
+			return
+		}
 	}
 }
 
-func (s *RedisServer) handleRequest(conn net.Conn, request any, requestType string) {
+// handleRequest executes a request and writes its reply. The client is
+// returned when the command blocked it, the reply is then written once it is served.
+func (s *RedisServer) handleRequest(client *handler.Client, request any, requestType string) *blockedClient {
 	command, args, err := parseAndGetRequestData(request, requestType)
 
 	if err != nil {
-		errorHelper(err, conn)
-		return
+		errorHelper(err, client.Writer)
+		return nil
+	}
+
+	if command == nil {
+		return nil
 	}
 
 	commandStr := strings.ToUpper(command.(string))
 
-	handlerFunc, handlerRegistered := s.handlers.ResolveHandler(commandStr)
+	spec, commandRegistered := s.handlers.ResolveCommand(commandStr)
 
-	if !handlerRegistered {
-		errorHelper(errors.New("Invalid operation"), conn)
-		return
+	if !commandRegistered {
+		err = handler.ErrUnknownCommand(command.(string), args)
+	} else {
+		err = spec.CheckArity(args)
+	}
+
+	if err != nil {
+		// an invalid command can not be queued, EXEC then discards the transaction
+		if client.InTransaction() {
+			client.FailTransaction()
+		}
+
+		errorHelper(err, client.Writer)
+		return nil
 	}
 
-	response, err := handlerFunc(args...)
+	// resp2 connections can only manage their subscriptions once subscribed,
+	// as anything else would be mixed up with the pushed messages
+	if client.Subscriptions() > 0 && client.Protocol == resp.RESP2 && !slices.Contains(handler.SUBSCRIBED_MODE_COMMANDS, commandStr) {
+		errorHelper(fmt.Errorf("ERR Can't execute '%s': only (P)SUBSCRIBE / (P)UNSUBSCRIBE / PING are allowed in this context", strings.ToLower(commandStr)), client.Writer)
+		return nil
+	}
+
+	if client.InTransaction() && !slices.Contains(handler.TRANSACTION_COMMANDS, commandStr) {
+		client.Queue(commandStr, args)
+		s.reply(client, resp.SIMPLE_STRING, "QUEUED")
+		return nil
+	}
+
+	return s.execute(client, commandStr, spec.Handler, args)
+}
+
+// reply writes a reply made outside of a command handler
+func (s *RedisServer) reply(client *handler.Client, dataType string, value any) {
+	data, err := client.Serialize(dataType, value)
 
 	if err != nil {
-		errorHelper(err, conn)
+		fmt.Println("Error while serializing : ", err)
 		return
 	}
 
-	conn.Write(response)
+	client.Write(data)
+}
+
+// execute runs the command and writes its reply before letting other commands
+// run, so replies are ordered with the messages other clients push
+func (s *RedisServer) execute(client *handler.Client, command string, handlerFunc handler.HandlerFunc, args []any) *blockedClient {
+	s.execLock.Lock()
+	defer s.execLock.Unlock()
+
+	response, err := handlerFunc(client, args...)
+
+	if err != nil {
+		errorHelper(err, client.Writer)
+		return nil
+	}
+
+	if blockRequest := client.TakeBlockRequest(); blockRequest != nil {
+		return s.block(client, command, handlerFunc, args, blockRequest)
+	}
+
+	s.propagate(client, command, args)
+	client.Write(response)
+	s.serveBlocked()
+
+	return nil
+}
+
+// serveBlocked serves the clients blocked on keys which received elements,
+// in any database as commands like MOVE write to another one
+func (s *RedisServer) serveBlocked() {
+	for _, store := range s.stores {
+		store.ServeBlocked()
+	}
+}
+
+// propagate logs the effects of the command which just ran
+func (s *RedisServer) propagate(client *handler.Client, command string, args []any) {
+	commands := client.TakePropagated()
+
+	if spec, _ := s.handlers.ResolveCommand(command); len(commands) == 0 && spec.LoggedAsCalled() {
+		commands = []handler.Command{{Name: command, Args: args, DB: client.DB}}
+	}
+
+	for _, propagated := range commands {
+		s.feedAppendOnly(propagated.DB, propagated.Name, propagated.Args)
+
+		// the commands around the effects of a transaction change nothing
+		if s.snapshotter != nil && propagated.Name != handler.MULTI && propagated.Name != handler.EXEC {
+			s.snapshotter.AddChanges(1)
+		}
+	}
 }
 
 func parseAndGetRequestData(request any, requestType string) (any, []any, error) {
@@ -123,29 +282,70 @@ func parseAndGetRequestData(request any, requestType string) (any, []any, error)
 	case resp.ARRAY:
 		items := request.([]resp.ArrayType)
 
+		// like redis, empty requests are ignored
 		if len(items) < 1 {
-			return nil, nil, errors.New("Invalid operations")
+			return nil, nil, nil
+		}
+
+		command, isString := items[0].Value.(string)
+
+		if !isString {
+			return nil, nil, errors.New("ERR Protocol error: expected '$'")
 		}
 
-		command := items[0].Value.(string)
 		args := []any{}
 
 		for _, argItem := range items[1:] {
+			// commands are made of bulk strings only
+			if _, isString := argItem.Value.(string); !isString {
+				return nil, nil, errors.New("ERR Protocol error: expected '$'")
+			}
+
 			args = append(args, argItem.Value)
 		}
 
 		return command, args, nil
+
+	case resp.INLINE:
+		items, err := resp.SplitInlineArgs(request.(string))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// empty lines are ignored, like a bare newline sent from telnet
+		if len(items) < 1 {
+			return nil, nil, nil
+		}
+
+		args := []any{}
+
+		for _, argItem := range items[1:] {
+			args = append(args, argItem)
+		}
+
+		return items[0], args, nil
 	}
 
 	return nil, nil, errors.New("Operation not supported")
 }
 
-func errorHelper(err error, conn net.Conn) {
+func isProtocolError(err error) bool {
+	var netErr net.Error
+
+	if errors.As(err, &netErr) || err == io.ErrUnexpectedEOF {
+		return false
+	}
+
+	return true
+}
+
+func errorHelper(err error, writer io.Writer) {
 	data, err := resp.Serialize(resp.ERROR, err.Error())
 
 	if err != nil {
 		fmt.Println("Error while serializing : ", err)
 	}
 
-	conn.Write(data)
+	writer.Write(data)
 }
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net"
+	"sync"
+)
+
+// connWriter queues the writes to a connection and sends them from its own
+// goroutine, so commands never wait on a slow client, like a publisher
+// pushing messages to a subscriber which does not read them. The writes are
+// sent in the order they were queued.
+type connWriter struct {
+	conn   net.Conn
+	lock   *sync.Mutex
+	queue  net.Buffers
+	closed bool
+	// signals queued writes, closed to stop the writer
+	wake chan struct{}
+	// closed once the queue is flushed after the writer is stopped
+	flushed chan struct{}
+}
+
+func newConnWriter(conn net.Conn) *connWriter {
+	writer := &connWriter{
+		conn:    conn,
+		lock:    &sync.Mutex{},
+		wake:    make(chan struct{}, 1),
+		flushed: make(chan struct{}),
+	}
+
+	go writer.run()
+
+	return writer
+}
+
+// Write queues data, it never blocks
+func (w *connWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return 0, net.ErrClosed
+	}
+
+	w.queue = append(w.queue, data)
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+
+	return len(data), nil
+}
+
+// Close flushes what is already queued and stops the writer, later writes
+// are dropped
+func (w *connWriter) Close() {
+	w.lock.Lock()
+
+	if w.closed {
+		w.lock.Unlock()
+		return
+	}
+
+	w.closed = true
+	close(w.wake)
+	w.lock.Unlock()
+
+	<-w.flushed
+}
+
+func (w *connWriter) run() {
+	defer close(w.flushed)
+
+	for range w.wake {
+		w.flush()
+	}
+
+	w.flush()
+}
+
+func (w *connWriter) flush() {
+	w.lock.Lock()
+	queue := w.queue
+	w.queue = nil
+	w.lock.Unlock()
+
+	if len(queue) > 0 {
+		queue.WriteTo(w.conn)
+	}
+}